@@ -0,0 +1,777 @@
+// Package archive exports and imports sites as zip archives.
+//
+// An archive contains a manifest, the site document, one BSON file per content
+// collection (mongodump style: concatenated documents) and all uploaded files
+// of the site under the uploads/ directory.
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/helpers"
+	"github.com/aymerick/kowa/models"
+)
+
+const (
+	// Version is the current archive format version
+	Version = 1
+
+	manifestName   = "manifest.json"
+	siteName       = "site.bson"
+	postsName      = "posts.bson"
+	eventsName     = "events.bson"
+	pagesName      = "pages.bson"
+	activitiesName = "activities.bson"
+	membersName    = "members.bson"
+	locationsName  = "locations.bson"
+	imagesName     = "images.bson"
+	filesName      = "files.bson"
+	redirectsName  = "redirects.bson"
+	uploadsDir     = "uploads/"
+
+	// max size of a single BSON document, as enforced by MongoDB
+	maxDocSize = 16 * 1024 * 1024
+
+	// max size of the manifest
+	maxManifestSize = 64 * 1024
+)
+
+// Manifest describes an archive
+type Manifest struct {
+	Version    int       `json:"version"`
+	SiteID     string    `json:"site"`
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+// content holds all documents of an archived site
+type content struct {
+	site       *models.Site
+	posts      models.PostsList
+	events     models.EventsList
+	pages      models.PagesList
+	activities models.ActivitiesList
+	members    models.MembersList
+	locations  models.LocationsList
+	images     models.ImagesList
+	files      models.FilesList
+	redirects  models.RedirectsList
+}
+
+// Export writes a zip archive of given site
+func Export(site *models.Site, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	manifest := &Manifest{
+		Version:    Version,
+		SiteID:     site.ID,
+		ExportedAt: time.Now(),
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeEntry(zw, manifestName, data); err != nil {
+		return err
+	}
+
+	if err := writeDocs(zw, siteName, []*models.Site{site}); err != nil {
+		return err
+	}
+
+	docs := []struct {
+		name string
+		list interface{}
+	}{
+		{postsName, *site.FindAllPosts()},
+		{eventsName, *site.FindAllEvents()},
+		{pagesName, *site.FindAllPages()},
+		{activitiesName, *site.FindAllActivities()},
+		{membersName, *site.FindAllMembers()},
+		{locationsName, *site.FindAllLocations()},
+		{imagesName, *site.FindAllImages()},
+		{filesName, *site.FindAllFiles()},
+		{redirectsName, *site.FindAllRedirects()},
+	}
+
+	for _, doc := range docs {
+		if err := writeDocs(zw, doc.name, doc.list); err != nil {
+			return err
+		}
+	}
+
+	if err := writeUploads(zw, core.UploadSiteDir(site.ID)); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Import creates a new site from given zip archive
+//
+// The site is created with given id and owned by given user. If siteID is empty, the archived site id is used.
+// If userID is empty, the archived site owner is used. All documents ids are remapped.
+func Import(dbSession *models.DBSession, zr *zip.Reader, siteID string, userID string) (*models.Site, error) {
+	manifest, err := ReadManifest(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Version > Version {
+		return nil, fmt.Errorf("Unsupported archive version: %d", manifest.Version)
+	}
+
+	c, err := readContent(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	if siteID == "" {
+		siteID = c.site.ID
+	}
+
+	// site id is used in upload paths
+	if (siteID == "") || (helpers.NormalizeToSiteID(siteID) != siteID) {
+		return nil, fmt.Errorf("Invalid site id: %q", siteID)
+	}
+
+	if userID == "" {
+		userID = c.site.UserID
+	}
+
+	if dbSession.FindSite(siteID) != nil {
+		return nil, fmt.Errorf("There is already a site with that id: %s", siteID)
+	}
+
+	if dbSession.FindUser(userID) == nil {
+		return nil, fmt.Errorf("User not found: %s", userID)
+	}
+
+	c.remap(siteID, userID)
+
+	core.EnsureSiteUploadDir(siteID)
+
+	if err := extractUploads(zr, core.UploadSiteDir(siteID)); err != nil {
+		os.RemoveAll(core.UploadSiteDir(siteID))
+		return nil, err
+	}
+
+	if err := c.insert(dbSession); err != nil {
+		// rollback
+		if site := dbSession.FindSite(siteID); site != nil {
+			site.Delete()
+		} else {
+			os.RemoveAll(core.UploadSiteDir(siteID))
+		}
+
+		return nil, err
+	}
+
+	return dbSession.FindSite(siteID), nil
+}
+
+// Size returns the total uncompressed size of given zip archive entries, and the uncompressed size of its largest
+// uploaded file
+//
+// Declared sizes can be trusted because reading an entry fails as soon as more bytes than declared are decompressed.
+func Size(zr *zip.Reader) (total uint64, largestUpload uint64) {
+	for _, f := range zr.File {
+		total = addSize(total, f.UncompressedSize64)
+
+		if uploadPath(f.Name) != "" && f.UncompressedSize64 > largestUpload {
+			largestUpload = f.UncompressedSize64
+		}
+	}
+
+	return total, largestUpload
+}
+
+// UploadsSize returns the total uncompressed size of uploaded files in given zip archive
+func UploadsSize(zr *zip.Reader) uint64 {
+	var result uint64
+
+	for _, f := range zr.File {
+		if uploadPath(f.Name) != "" && f.Mode().IsRegular() {
+			result = addSize(result, f.UncompressedSize64)
+		}
+	}
+
+	return result
+}
+
+// addSize returns a + b, saturated to avoid overflows with forged sizes
+func addSize(a uint64, b uint64) uint64 {
+	if a+b < a {
+		return math.MaxUint64
+	}
+
+	return a + b
+}
+
+// ReadManifest returns the manifest of given zip archive
+func ReadManifest(zr *zip.Reader) (*Manifest, error) {
+	data, err := readEntry(zr, manifestName, maxManifestSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Manifest{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("Invalid archive manifest: %v", err)
+	}
+
+	return result, nil
+}
+
+func readContent(zr *zip.Reader) (*content, error) {
+	result := &content{}
+
+	var sites []*models.Site
+	if err := readDocs(zr, siteName, &sites); err != nil {
+		return nil, err
+	}
+
+	if len(sites) != 1 {
+		return nil, errors.New("Invalid archive: site document not found")
+	}
+
+	result.site = sites[0]
+
+	lists := []struct {
+		name string
+		list interface{}
+	}{
+		{postsName, &result.posts},
+		{eventsName, &result.events},
+		{pagesName, &result.pages},
+		{activitiesName, &result.activities},
+		{membersName, &result.members},
+		{locationsName, &result.locations},
+		{imagesName, &result.images},
+		{filesName, &result.files},
+		{redirectsName, &result.redirects},
+	}
+
+	for _, l := range lists {
+		if err := readDocs(zr, l.name, l.list); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// insert content in database
+func (c *content) insert(dbSession *models.DBSession) error {
+	if err := dbSession.SitesCol().Insert(c.site); err != nil {
+		return err
+	}
+
+	// images and files first, so that content never references missing documents
+	for _, img := range c.images {
+		if err := dbSession.ImagesCol().Insert(img); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range c.files {
+		if err := dbSession.FilesCol().Insert(file); err != nil {
+			return err
+		}
+	}
+
+	for _, loc := range c.locations {
+		if err := dbSession.LocationsCol().Insert(loc); err != nil {
+			return err
+		}
+	}
+
+	for _, post := range c.posts {
+		if err := dbSession.PostsCol().Insert(post); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range c.events {
+		if err := dbSession.EventsCol().Insert(event); err != nil {
+			return err
+		}
+	}
+
+	for _, page := range c.pages {
+		if err := dbSession.PagesCol().Insert(page); err != nil {
+			return err
+		}
+	}
+
+	for _, activity := range c.activities {
+		if err := dbSession.ActivitiesCol().Insert(activity); err != nil {
+			return err
+		}
+	}
+
+	for _, member := range c.members {
+		if err := dbSession.MembersCol().Insert(member); err != nil {
+			return err
+		}
+	}
+
+	for _, redirect := range c.redirects {
+		if err := dbSession.RedirectsCol().Insert(redirect); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//
+// Ids remapping
+//
+
+// idsMap maps archived ids to new ids
+// internal links and media shortcodes syntax in contents, cf. builder/links.go and builder/media.go, eg:
+//
+//	[[page:5565ad2c4e6f6c4a0c000004]]
+//	[[image:5565ad2c4e6f6c4a0c000004:small]]
+//	[[gallery:5565ad2c4e6f6c4a0c000004,5565ad2c4e6f6c4a0c000005]]
+var bodyIDsRegexp = regexp.MustCompile(`\[\[(page|post|image|gallery):([0-9a-fA-F]{24}(?:,[0-9a-fA-F]{24})*)(:[a-z0-9_]+)?\]\]`)
+
+type idsMap map[bson.ObjectId]bson.ObjectId
+
+func (m idsMap) add(id bson.ObjectId) bson.ObjectId {
+	result := bson.NewObjectId()
+	m[id] = result
+	return result
+}
+
+// get returns the new id for given archived id, or an empty id if the referenced document is not in archive
+func (m idsMap) get(id bson.ObjectId) bson.ObjectId {
+	if id == "" {
+		return ""
+	}
+
+	return m[id]
+}
+
+// getAll returns the new ids for given archived ids, skipping referenced documents that are not in archive
+func (m idsMap) getAll(ids []bson.ObjectId) []bson.ObjectId {
+	var result []bson.ObjectId
+
+	for _, id := range ids {
+		if newID := m.get(id); newID != "" {
+			result = append(result, newID)
+		}
+	}
+
+	return result
+}
+
+// getKey remaps a string key that may be an hex id (cf. Site.NavBarOrder)
+func (m idsMap) getKey(key string) string {
+	if bson.IsObjectIdHex(key) {
+		if newID, ok := m[bson.ObjectIdHex(key)]; ok {
+			return newID.Hex()
+		}
+	}
+
+	return key
+}
+
+// remapBody updates ids in internal links and media shortcodes of given content body
+func (m idsMap) remapBody(body string) string {
+	return bodyIDsRegexp.ReplaceAllStringFunc(body, func(shortcode string) string {
+		matches := bodyIDsRegexp.FindStringSubmatch(shortcode)
+
+		ids := strings.Split(matches[2], ",")
+		for i, id := range ids {
+			ids[i] = m.getKey(id)
+		}
+
+		return "[[" + matches[1] + ":" + strings.Join(ids, ",") + matches[3] + "]]"
+	})
+}
+
+// remap sets new ids on all documents, and updates all references
+func (c *content) remap(siteID string, userID string) {
+	ids := make(idsMap)
+
+	// allocate new ids
+	for _, img := range c.images {
+		img.ID = ids.add(img.ID)
+	}
+
+	for _, file := range c.files {
+		file.ID = ids.add(file.ID)
+	}
+
+	for _, loc := range c.locations {
+		loc.ID = ids.add(loc.ID)
+	}
+
+	for _, post := range c.posts {
+		post.ID = ids.add(post.ID)
+	}
+
+	for _, event := range c.events {
+		event.ID = ids.add(event.ID)
+	}
+
+	for _, page := range c.pages {
+		page.ID = ids.add(page.ID)
+	}
+
+	for _, activity := range c.activities {
+		activity.ID = ids.add(activity.ID)
+	}
+
+	for _, member := range c.members {
+		member.ID = ids.add(member.ID)
+	}
+
+	for _, redirect := range c.redirects {
+		redirect.ID = ids.add(redirect.ID)
+	}
+
+	// uploaded files urls and internal links in bodies
+	oldPrefix := core.UploadSiteUrlPath(c.site.ID, "") + "/"
+	newPrefix := core.UploadSiteUrlPath(siteID, "") + "/"
+
+	body := func(s string) string {
+		return ids.remapBody(strings.Replace(s, oldPrefix, newPrefix, -1))
+	}
+
+	// update references
+	site := c.site
+
+	if site.CustomURL != "" && site.CustomURL == core.BaseUrl(site.ID) {
+		// default url computed from site id
+		site.CustomURL = core.BaseUrl(siteID)
+	}
+
+	site.ID = siteID
+	site.UserID = userID
+	site.Description = body(site.Description)
+	site.MoreDesc = body(site.MoreDesc)
+	site.JoinText = body(site.JoinText)
+	site.Location = ids.get(site.Location)
+	site.Logo = ids.get(site.Logo)
+	site.Cover = ids.get(site.Cover)
+	site.Favicon = ids.get(site.Favicon)
+	site.Membership = ids.get(site.Membership)
+
+	for _, settings := range site.PageSettings {
+		settings.Cover = ids.get(settings.Cover)
+	}
+
+	for _, link := range site.NavBarLinks {
+		link.Parent = ids.getKey(link.Parent)
+	}
+
+	for i, key := range site.NavBarOrder {
+		site.NavBarOrder[i] = ids.getKey(key)
+	}
+
+	for _, img := range c.images {
+		img.SiteID = siteID
+	}
+
+	for _, file := range c.files {
+		file.SiteID = siteID
+	}
+
+	for _, loc := range c.locations {
+		loc.SiteID = siteID
+	}
+
+	for _, post := range c.posts {
+		post.SiteID = siteID
+		post.Body = body(post.Body)
+		post.Cover = ids.get(post.Cover)
+		post.Files = ids.getAll(post.Files)
+	}
+
+	for _, event := range c.events {
+		event.SiteID = siteID
+		event.Body = body(event.Body)
+		event.Location = ids.get(event.Location)
+		event.Cover = ids.get(event.Cover)
+		event.Files = ids.getAll(event.Files)
+
+		for _, override := range event.Overrides {
+			override.Body = body(override.Body)
+		}
+	}
+
+	for _, page := range c.pages {
+		page.SiteID = siteID
+		page.Body = body(page.Body)
+		page.Cover = ids.get(page.Cover)
+		page.Files = ids.getAll(page.Files)
+		page.ParentID = ids.get(page.ParentID)
+	}
+
+	for _, activity := range c.activities {
+		activity.SiteID = siteID
+		activity.Body = body(activity.Body)
+		activity.Cover = ids.get(activity.Cover)
+	}
+
+	for _, member := range c.members {
+		member.SiteID = siteID
+		member.Description = body(member.Description)
+		member.Photo = ids.get(member.Photo)
+	}
+
+	redirects := models.RedirectsList{}
+	for _, redirect := range c.redirects {
+		redirect.SiteID = siteID
+
+		// skip redirects to missing targets
+		if redirect.TargetID = ids.get(redirect.TargetID); redirect.TargetID != "" {
+			redirects = append(redirects, redirect)
+		}
+	}
+
+	c.redirects = redirects
+}
+
+//
+// Zip helpers
+//
+
+func writeEntry(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+func findEntry(zr *zip.Reader, name string) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+func readEntry(zr *zip.Reader, name string, maxSize uint64) ([]byte, error) {
+	f := findEntry(zr, name)
+	if f == nil {
+		return nil, fmt.Errorf("Invalid archive: %s not found", name)
+	}
+
+	if f.UncompressedSize64 > maxSize {
+		return nil, fmt.Errorf("Invalid archive: %s is too large", name)
+	}
+
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
+
+// writeDocs writes a slice of documents as concatenated BSON documents
+func writeDocs(zw *zip.Writer, name string, docs interface{}) error {
+	var buf bytes.Buffer
+
+	list := reflect.ValueOf(docs)
+	for i := 0; i < list.Len(); i++ {
+		data, err := bson.Marshal(list.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+
+		buf.Write(data)
+	}
+
+	return writeEntry(zw, name, buf.Bytes())
+}
+
+// readDocs reads concatenated BSON documents into given pointer to a slice of pointers
+func readDocs(zr *zip.Reader, name string, result interface{}) error {
+	f := findEntry(zr, name)
+	if f == nil {
+		// missing collection means no document
+		return nil
+	}
+
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	list := reflect.ValueOf(result).Elem()
+	docType := list.Type().Elem().Elem()
+
+	for {
+		data, err := readDoc(r)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("Invalid archive: %s: %v", name, err)
+		}
+
+		doc := reflect.New(docType)
+		if err := bson.Unmarshal(data, doc.Interface()); err != nil {
+			return fmt.Errorf("Invalid archive: %s: %v", name, err)
+		}
+
+		list.Set(reflect.Append(list, doc))
+	}
+
+	return nil
+}
+
+// readDoc reads next BSON document, or returns io.EOF
+func readDoc(r io.Reader) ([]byte, error) {
+	var header [4]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, errors.New("truncated document")
+		}
+
+		return nil, err
+	}
+
+	size := int(binary.LittleEndian.Uint32(header[:]))
+	if size < 5 || size > maxDocSize {
+		return nil, fmt.Errorf("invalid document size: %d", size)
+	}
+
+	data := make([]byte, size)
+	copy(data, header[:])
+
+	if _, err := io.ReadFull(r, data[4:]); err != nil {
+		return nil, errors.New("truncated document")
+	}
+
+	return data, nil
+}
+
+// writeUploads adds all files found in given directory
+func writeUploads(zw *zip.Writer, dirPath string) error {
+	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.Walk(dirPath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dirPath, filePath)
+		if err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+
+		header.Name = uploadsDir + filepath.ToSlash(relPath)
+		header.Method = zip.Deflate
+
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(w, f)
+		return err
+	})
+}
+
+// uploadPath returns the path relative to uploads directory of given archive entry, or an empty string if entry is
+// not an uploaded file or if its path is unsafe
+func uploadPath(name string) string {
+	if !strings.HasPrefix(name, uploadsDir) || strings.HasSuffix(name, "/") {
+		return ""
+	}
+
+	result := path.Clean(strings.TrimPrefix(name, uploadsDir))
+	if result == "." || result == ".." || strings.HasPrefix(result, "../") || path.IsAbs(result) {
+		return ""
+	}
+
+	return result
+}
+
+// extractUploads extracts all uploaded files to given directory
+func extractUploads(zr *zip.Reader, dirPath string) error {
+	for _, f := range zr.File {
+		relPath := uploadPath(f.Name)
+		if relPath == "" || !f.Mode().IsRegular() {
+			continue
+		}
+
+		if err := extractFile(f, filepath.Join(dirPath, filepath.FromSlash(relPath))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractFile(f *zip.File, dstPath string) error {
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return err
+	}
+
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, r)
+	return err
+}
@@ -0,0 +1,271 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/models"
+)
+
+type ArchiveTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestArchiveTestSuite(t *testing.T) {
+	suite.Run(t, new(ArchiveTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *ArchiveTestSuite) TestDocsRoundTrip() {
+	t := suite.T()
+
+	createdAt := time.Date(2015, time.March, 10, 8, 30, 0, 0, time.UTC)
+
+	posts := models.PostsList{
+		{ID: bson.NewObjectId(), CreatedAt: createdAt, SiteID: "test", Title: "First", Body: "body one"},
+		{ID: bson.NewObjectId(), CreatedAt: createdAt, SiteID: "test", Title: "Second", Cover: bson.NewObjectId()},
+	}
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+	assert.Nil(t, writeDocs(zw, postsName, posts))
+	assert.Nil(t, zw.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+
+	var result models.PostsList
+	assert.Nil(t, readDocs(zr, postsName, &result))
+
+	if assert.Len(t, result, 2) {
+		for i, post := range result {
+			assert.Equal(t, posts[i].ID, post.ID)
+			assert.Equal(t, posts[i].Title, post.Title)
+			assert.Equal(t, posts[i].Body, post.Body)
+			assert.Equal(t, posts[i].Cover, post.Cover)
+			assert.True(t, posts[i].CreatedAt.Equal(post.CreatedAt))
+		}
+	}
+
+	// missing collection
+	var pages models.PagesList
+	assert.Nil(t, readDocs(zr, pagesName, &pages))
+	assert.Len(t, pages, 0)
+}
+
+func (suite *ArchiveTestSuite) TestRemap() {
+	t := suite.T()
+
+	imgID := bson.NewObjectId()
+	pageID := bson.NewObjectId()
+	subPageID := bson.NewObjectId()
+	linkID := bson.NewObjectId()
+	postID := bson.NewObjectId()
+	eventID := bson.NewObjectId()
+	missingID := bson.NewObjectId()
+	fileID := bson.NewObjectId()
+
+	c := &content{
+		site: &models.Site{
+			ID:          "old",
+			UserID:      "jeanjean",
+			Logo:        imgID,
+			Cover:       bson.NewObjectId(), // not in archive
+			NavBarLinks: map[string]*models.SiteNavBarLink{linkID.Hex(): {ID: linkID, Parent: pageID.Hex()}},
+			NavBarOrder: []string{"posts", pageID.Hex(), linkID.Hex()},
+			JoinText:    "See [[page:" + pageID.Hex() + "]]",
+		},
+		images: models.ImagesList{{ID: imgID, SiteID: "old", Path: "foo.jpg"}},
+		files:  models.FilesList{{ID: fileID, SiteID: "old", Path: "foo.pdf"}},
+		pages: models.PagesList{
+			{ID: pageID, SiteID: "old", Cover: imgID, Body: `<img src="/upload/old/foo.jpg">`},
+			{ID: subPageID, SiteID: "old", ParentID: pageID, Body: "Back to [[page:" + pageID.Hex() + "]] or [[post:" + missingID.Hex() + "]]"},
+		},
+		posts: models.PostsList{{ID: postID, SiteID: "old", Body: "See [[page:" + subPageID.Hex() + "]]", Files: []bson.ObjectId{fileID, missingID}}},
+		events: models.EventsList{{
+			ID:        eventID,
+			SiteID:    "old",
+			Files:     []bson.ObjectId{fileID},
+			Overrides: map[string]*models.EventOverride{"20150601T200000": {Body: "Read [[post:" + postID.Hex() + "]]"}},
+		}},
+	}
+
+	c.remap("new", "bob")
+
+	newImgID := c.images[0].ID
+	newPageID := c.pages[0].ID
+
+	assert.NotEqual(t, imgID, newImgID)
+	assert.NotEqual(t, pageID, newPageID)
+	assert.NotEqual(t, subPageID, c.pages[1].ID)
+
+	assert.Equal(t, "new", c.site.ID)
+	assert.Equal(t, "bob", c.site.UserID)
+	assert.Equal(t, newImgID, c.site.Logo)
+	assert.Equal(t, bson.ObjectId(""), c.site.Cover)
+	assert.Equal(t, newPageID.Hex(), c.site.NavBarLinks[linkID.Hex()].Parent)
+	assert.Equal(t, []string{"posts", newPageID.Hex(), linkID.Hex()}, c.site.NavBarOrder)
+
+	assert.Equal(t, "new", c.images[0].SiteID)
+	assert.Equal(t, "foo.jpg", c.images[0].Path)
+
+	assert.Equal(t, "new", c.pages[0].SiteID)
+	assert.Equal(t, newImgID, c.pages[0].Cover)
+	assert.Equal(t, `<img src="/upload/new/foo.jpg">`, c.pages[0].Body)
+	assert.Equal(t, newPageID, c.pages[1].ParentID)
+
+	// internal links
+	newSubPageID := c.pages[1].ID
+	newPostID := c.posts[0].ID
+
+	assert.Equal(t, "See [[page:"+newPageID.Hex()+"]]", c.site.JoinText)
+	assert.Equal(t, "Back to [[page:"+newPageID.Hex()+"]] or [[post:"+missingID.Hex()+"]]", c.pages[1].Body)
+	assert.Equal(t, "See [[page:"+newSubPageID.Hex()+"]]", c.posts[0].Body)
+	assert.Equal(t, "Read [[post:"+newPostID.Hex()+"]]", c.events[0].Overrides["20150601T200000"].Body)
+
+	// attachments
+	newFileID := c.files[0].ID
+
+	assert.NotEqual(t, fileID, newFileID)
+	assert.Equal(t, []bson.ObjectId{newFileID}, c.posts[0].Files)
+	assert.Equal(t, []bson.ObjectId{newFileID}, c.events[0].Files)
+	assert.Nil(t, c.pages[0].Files)
+}
+
+func (suite *ArchiveTestSuite) TestMediaShortcodesRoundTrip() {
+	t := suite.T()
+
+	img1 := bson.NewObjectId()
+	img2 := bson.NewObjectId()
+	missingID := bson.NewObjectId()
+
+	body := "[[image:" + img1.Hex() + "]] [[image:" + img2.Hex() + ":small]] [[gallery:" + img1.Hex() + "," + missingID.Hex() + "," + img2.Hex() + "]]"
+
+	// write archive
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+	assert.Nil(t, writeDocs(zw, siteName, []*models.Site{{ID: "old", Description: body}}))
+	assert.Nil(t, writeDocs(zw, postsName, models.PostsList{{ID: bson.NewObjectId(), SiteID: "old", Body: body}}))
+	assert.Nil(t, writeDocs(zw, imagesName, models.ImagesList{{ID: img1, SiteID: "old", Path: "foo.jpg"}, {ID: img2, SiteID: "old", Path: "bar.jpg"}}))
+	assert.Nil(t, zw.Close())
+
+	// read archive
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+
+	c, err := readContent(zr)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	c.remap("new", "bob")
+
+	newImg1 := c.images[0].ID
+	newImg2 := c.images[1].ID
+
+	assert.NotEqual(t, img1, newImg1)
+	assert.NotEqual(t, img2, newImg2)
+
+	expected := "[[image:" + newImg1.Hex() + "]] [[image:" + newImg2.Hex() + ":small]] [[gallery:" + newImg1.Hex() + "," + missingID.Hex() + "," + newImg2.Hex() + "]]"
+
+	assert.Equal(t, expected, c.site.Description)
+	if assert.Len(t, c.posts, 1) {
+		assert.Equal(t, expected, c.posts[0].Body)
+	}
+}
+
+func (suite *ArchiveTestSuite) TestSize() {
+	t := suite.T()
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+	assert.Nil(t, writeEntry(zw, manifestName, []byte("{}")))
+	assert.Nil(t, writeEntry(zw, uploadsDir+"foo.jpg", bytes.Repeat([]byte{'a'}, 1000)))
+	assert.Nil(t, writeEntry(zw, uploadsDir+"bar/baz.pdf", bytes.Repeat([]byte{'b'}, 3000)))
+	assert.Nil(t, writeEntry(zw, uploadsDir+"../evil.sh", bytes.Repeat([]byte{'c'}, 5000)))
+	assert.Nil(t, zw.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+
+	total, largestUpload := Size(zr)
+	assert.Equal(t, uint64(9002), total)
+	assert.Equal(t, uint64(3000), largestUpload)
+
+	assert.Equal(t, uint64(4000), UploadsSize(zr))
+
+	// forged sizes must not overflow
+	zr.File[1].UncompressedSize64 = math.MaxUint64
+	total, largestUpload = Size(zr)
+	assert.Equal(t, uint64(math.MaxUint64), total)
+	assert.Equal(t, uint64(math.MaxUint64), largestUpload)
+	assert.Equal(t, uint64(math.MaxUint64), UploadsSize(zr))
+}
+
+func (suite *ArchiveTestSuite) TestReadManifestTooLarge() {
+	t := suite.T()
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+	assert.Nil(t, writeEntry(zw, manifestName, bytes.Repeat([]byte{' '}, maxManifestSize+1)))
+	assert.Nil(t, zw.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+
+	_, err = ReadManifest(zr)
+	assert.NotNil(t, err)
+}
+
+func (suite *ArchiveTestSuite) TestImportInvalidSiteID() {
+	t := suite.T()
+
+	for _, siteID := range []string{"..", "../..", "foo/bar", ""} {
+		var buf bytes.Buffer
+
+		zw := zip.NewWriter(&buf)
+		assert.Nil(t, writeEntry(zw, manifestName, []byte(`{"version":1}`)))
+		assert.Nil(t, writeDocs(zw, siteName, []*models.Site{{ID: siteID, UserID: "bob"}}))
+		assert.Nil(t, zw.Close())
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.Nil(t, err)
+
+		// rejected before any database access
+		_, err = Import(nil, zr, "", "")
+		assert.EqualError(t, err, fmt.Sprintf("Invalid site id: %q", siteID))
+
+		_, err = Import(nil, zr, "../evil", "")
+		assert.EqualError(t, err, `Invalid site id: "../evil"`)
+	}
+}
+
+func (suite *ArchiveTestSuite) TestUploadPath() {
+	t := suite.T()
+
+	assert.Equal(t, "foo.jpg", uploadPath("uploads/foo.jpg"))
+	assert.Equal(t, "sub/foo.jpg", uploadPath("uploads/sub/./foo.jpg"))
+
+	assert.Equal(t, "", uploadPath("posts.bson"))
+	assert.Equal(t, "", uploadPath("uploads/"))
+	assert.Equal(t, "", uploadPath("uploads/sub/"))
+	assert.Equal(t, "", uploadPath("uploads/../foo.jpg"))
+	assert.Equal(t, "", uploadPath("uploads/sub/../../../etc/passwd"))
+}
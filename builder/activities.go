@@ -122,8 +122,8 @@ func (builder *ActivitiesBuilder) NewActivityVars(activity *models.Activity) *Ac
 		result.Cover = builder.addImage(cover)
 	}
 
-	result.Summary = generateHTML(models.FormatHTML, activity.Summary)
-	result.Body = generateHTML(models.FormatHTML, activity.Body)
+	builder.generateHTML(models.FormatHTML, activity.Summary, &result.Summary)
+	builder.generateHTML(models.FormatHTML, activity.Body, &result.Body)
 
 	return result
 }
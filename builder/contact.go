@@ -1,8 +1,10 @@
 package builder
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/aymerick/kowa/core"
 	"github.com/aymerick/kowa/models"
 	"github.com/aymerick/raymond"
 	"github.com/nicksnyder/go-i18n/i18n"
@@ -18,6 +20,12 @@ type ContactContent struct {
 	HaveContact bool
 	Email       string
 	Address     raymond.SafeString
+	Location    *LocationVars
+
+	HaveForm       bool
+	FormAction     string // contact form action URL
+	FormHoneypot   string // name of the field that must stay empty
+	FormRedirectTo string // contact page URL, where visitor is redirected after submission
 
 	HaveSocial bool
 	Facebook   string
@@ -73,6 +81,10 @@ func (builder *ContactBuilder) Load() {
 	node.InNavBar = true
 	node.NavBarOrder = 20
 
+	if contactContent.HaveForm {
+		contactContent.FormRedirectTo = node.AbsoluteUrl
+	}
+
 	node.Content = contactContent
 
 	builder.addNode(node)
@@ -89,6 +101,21 @@ func (builder *ContactBuilder) NewContactContent() *ContactContent {
 	addrSafe := raymond.Escape(site.Address)
 	result.Address = raymond.SafeString(strings.Replace(addrSafe, "\n", "<br />\n", -1))
 
+	if location := site.FindLocation(); location != nil {
+		result.Location = NewLocationVars(location)
+
+		if result.Address == "" {
+			result.Address = result.Location.AddressHTML
+		}
+	}
+
+	if site.Email != "" {
+		// messages are delivered to site email
+		result.HaveForm = true
+		result.FormAction = core.PublicAPIUrl(fmt.Sprintf("/sites/%s/contact", site.ID))
+		result.FormHoneypot = core.PublicFormHoneypot
+	}
+
 	if result.Email != "" || result.Address != "" {
 		result.HaveContact = true
 	}
@@ -0,0 +1,153 @@
+package builder
+
+import (
+	"sort"
+
+	"github.com/nicksnyder/go-i18n/i18n"
+
+	"github.com/aymerick/kowa/models"
+)
+
+// DownloadsBuilder builds downloads page
+type DownloadsBuilder struct {
+	*NodeBuilderBase
+
+	// loaded documents
+	documentsVars []*FileVars
+
+	// documents by folder
+	folders []*DownloadsFolder
+}
+
+// DownloadsContent represents downloads node content
+type DownloadsContent struct {
+	Documents []*FileVars
+	Folders   []*DownloadsFolder
+}
+
+// DownloadsFolder represents documents of a media library folder
+type DownloadsFolder struct {
+	Name      string // empty for documents without folder
+	Documents []*FileVars
+}
+
+// DownloadsFoldersByName represents sortable downloads folders
+type DownloadsFoldersByName []*DownloadsFolder
+
+func init() {
+	RegisterNodeBuilder(kindDownloads, NewDownloadsBuilder)
+}
+
+// NewDownloadsBuilder instanciates a new NodeBuilder
+func NewDownloadsBuilder(siteBuilder *SiteBuilder) NodeBuilder {
+	return &DownloadsBuilder{
+		NodeBuilderBase: &NodeBuilderBase{
+			nodeKind:    kindDownloads,
+			siteBuilder: siteBuilder,
+		},
+	}
+}
+
+// Load is part of NodeBuilder interface
+func (builder *DownloadsBuilder) Load() {
+	// downloads page is optional in themes
+	if !builder.SiteBuilder().theme.HaveTemplate(kindDownloads) {
+		return
+	}
+
+	// fetch documents
+	documentsVars := builder.documents()
+	if len(documentsVars) == 0 {
+		return
+	}
+
+	// get page settings
+	title, tagline, cover, disabled := builder.pageSettings(models.PageKindDownloads)
+	if disabled {
+		return
+	}
+
+	T := i18n.MustTfunc(builder.siteLang())
+	slug := T("downloads")
+
+	if title == "" {
+		title = slug
+	}
+
+	// build node
+	node := builder.newNode()
+	node.fillURL(slug)
+
+	node.Title = title
+	node.Tagline = tagline
+	node.Cover = cover
+
+	node.Meta = &NodeMeta{Description: tagline}
+
+	node.InNavBar = true
+	node.NavBarOrder = 18
+
+	node.Content = &DownloadsContent{
+		Documents: documentsVars,
+		Folders:   builder.folders,
+	}
+
+	builder.addNode(node)
+}
+
+// Data is part of NodeBuilder interface
+func (builder *DownloadsBuilder) Data(name string) interface{} {
+	switch name {
+	case "documents":
+		return builder.documents()
+	}
+
+	return nil
+}
+
+// returns documents contents
+func (builder *DownloadsBuilder) documents() []*FileVars {
+	if len(builder.documentsVars) == 0 {
+		byFolder := make(map[string]*DownloadsFolder)
+
+		// fetch documents
+		for _, file := range *builder.site().FindDocuments() {
+			fileVars := builder.addFileVars(file)
+
+			builder.documentsVars = append(builder.documentsVars, fileVars)
+
+			folder := byFolder[file.Folder]
+			if folder == nil {
+				folder = &DownloadsFolder{Name: file.Folder}
+				byFolder[file.Folder] = folder
+
+				builder.folders = append(builder.folders, folder)
+			}
+
+			folder.Documents = append(folder.Documents, fileVars)
+		}
+
+		sort.Sort(DownloadsFoldersByName(builder.folders))
+	}
+
+	return builder.documentsVars
+}
+
+//
+// DownloadsFoldersByName
+//
+
+// Implements sort.Interface
+func (folders DownloadsFoldersByName) Len() int {
+	return len(folders)
+}
+
+// Implements sort.Interface
+func (folders DownloadsFoldersByName) Swap(i, j int) {
+	folders[i], folders[j] = folders[j], folders[i]
+}
+
+// Implements sort.Interface
+func (folders DownloadsFoldersByName) Less(i, j int) bool {
+	return folders[i].Name < folders[j].Name
+}
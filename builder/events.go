@@ -8,10 +8,17 @@ import (
 
 	"github.com/nicksnyder/go-i18n/i18n"
 
+	"github.com/aymerick/kowa/core"
 	"github.com/aymerick/kowa/models"
 	"github.com/aymerick/raymond"
 )
 
+const (
+	// recurring events occurrences are generated in that time range
+	recurrencePastRange   = 365 * 24 * time.Hour
+	recurrenceFutureRange = 365 * 24 * time.Hour
+)
+
 // EventsBuilder builds events pages
 type EventsBuilder struct {
 	*NodeBuilderBase
@@ -24,11 +31,15 @@ type EventsBuilder struct {
 type EventContent struct {
 	Model *models.Event
 
-	Cover *ImageVars
-	Title string
-	Place string
-	Body  raymond.SafeString
-	Url   string
+	Cover    *ImageVars
+	Title    string
+	Place    string
+	Location *LocationVars
+	Body     raymond.SafeString
+	Url      string
+	Files    []*FileVars // attached files
+
+	JSONLD raymond.SafeString // schema.org Event, as a JSON-LD script tag
 
 	Dates string
 
@@ -53,6 +64,13 @@ type EventContent struct {
 	EndMonthShort   string
 	EndYear         string
 	EndTime         string
+
+	RegistrationOpen     bool
+	RegistrationFull     bool
+	RegistrationUrl      string
+	RegistrationHoneypot string
+	RegistrationDeadline string
+	RegistrationFields   []*models.EventRegistrationField
 }
 
 // EventContentsByStartDate represents sortable event node contents
@@ -89,8 +107,15 @@ func (builder *EventsBuilder) Load() {
 
 // Build all events
 func (builder *EventsBuilder) loadEvents() {
+	now := time.Now()
+	from := now.Add(-recurrencePastRange)
+	to := now.Add(recurrenceFutureRange)
+
 	for _, event := range *builder.site().FindAllEvents() {
-		builder.loadEvent(event)
+		// expand recurring events
+		for _, occurrence := range event.Occurrences(from, to, builder.siteTZLocation()) {
+			builder.loadEvent(occurrence)
+		}
 	}
 }
 
@@ -233,16 +258,78 @@ func (builder *EventsBuilder) NewEventContent(event *models.Event, node *Node) *
 		})
 	}
 
+	if event.RegistrationEnabled {
+		result.RegistrationOpen = event.RegistrationOpen()
+		result.RegistrationFull = event.RegistrationFull()
+		result.RegistrationUrl = core.PublicAPIUrl(fmt.Sprintf("/events/%s/registrations", event.ID.Hex()))
+		result.RegistrationHoneypot = core.PublicFormHoneypot
+		result.RegistrationFields = event.RegistrationFields
+
+		if !event.RegistrationDeadline.IsZero() {
+			deadline := builder.siteTime(event.RegistrationDeadline)
+
+			result.RegistrationDeadline = T("event_format_datetime", map[string]interface{}{
+				"Year":    deadline.Format("2006"),
+				"Month":   T("month_" + deadline.Format("January")),
+				"Day":     deadline.Format("02"),
+				"Time":    deadline.Format(T("format_time")),
+				"Weekday": T("weekday_" + deadline.Format("Monday")),
+			})
+		}
+	}
+
+	if location := event.FindLocation(); location != nil {
+		result.Location = NewLocationVars(location)
+
+		if result.Place == "" {
+			result.Place = location.Name
+		}
+	}
+
 	cover := event.FindCover()
 	if cover != nil {
 		result.Cover = builder.addImage(cover)
 	}
 
-	result.Body = generateHTML(event.Format, event.Body)
+	result.Files = builder.addFilesVars(event.FindFiles())
+
+	builder.generateHTML(event.Format, event.Body, &result.Body)
+
+	result.JSONLD = builder.eventJSONLD(result, node)
 
 	return result
 }
 
+// eventJSONLD computes schema.org data for given event
+func (builder *EventsBuilder) eventJSONLD(eventContent *EventContent, node *Node) raymond.SafeString {
+	data := map[string]interface{}{
+		"@context":  "http://schema.org",
+		"@type":     "Event",
+		"name":      eventContent.Title,
+		"startDate": eventContent.StartDateRFC3339,
+		"url":       node.AbsoluteUrl,
+	}
+
+	if !eventContent.Model.EndDate.IsZero() {
+		data["endDate"] = eventContent.EndDateRFC3339
+	}
+
+	if eventContent.Location != nil {
+		data["location"] = eventContent.Location.jsonLD()
+	} else if eventContent.Place != "" {
+		data["location"] = map[string]interface{}{
+			"@type": "Place",
+			"name":  eventContent.Place,
+		}
+	}
+
+	if eventContent.Cover != nil {
+		data["image"] = eventContent.Cover.LargeAbsolute
+	}
+
+	return jsonLDScript(data)
+}
+
 // Build events list pages
 // @todo pagination
 func (builder *EventsBuilder) loadEventsLists() {
@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/aymerick/kowa/models"
+)
+
+// FileVars represents a downloadable file variables
+type FileVars struct {
+	Title       string
+	Description string
+	Name        string // uploaded file name
+	Url         string
+	Type        string // content type
+	Size        int64
+	HumanSize   string // eg: 1.2 MB
+}
+
+// Collect file, and returns the template vars for that file
+func (builder *SiteBuilder) addFileVars(file *models.File) *FileVars {
+	return &FileVars{
+		Title:       file.DisplayTitle(),
+		Description: file.Description,
+		Name:        file.Name,
+		Url:         builder.addFile(file),
+		Type:        file.Type,
+		Size:        file.Size,
+		HumanSize:   humanSize(file.Size),
+	}
+}
+
+// Collect files, and returns the template vars for those files
+func (builder *SiteBuilder) addFilesVars(files *models.FilesList) []*FileVars {
+	var result []*FileVars
+
+	for _, file := range *files {
+		result = append(result, builder.addFileVars(file))
+	}
+
+	return result
+}
+
+// Returns a human readable file size
+func humanSize(size int64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGT"[exp])
+}
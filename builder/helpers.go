@@ -1,17 +1,22 @@
 package builder
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 
+	"github.com/aymerick/raymond"
 	"github.com/nicksnyder/go-i18n/i18n"
+	"gopkg.in/mgo.v2/bson"
 )
 
 // Build FuncMap for template
 func (site *SiteBuilder) helpers() map[string]interface{} {
 	return map[string]interface{}{
-		"urlFor": site.UrlFor,
-		"t":      site.Translate,
+		"urlFor":   site.UrlFor,
+		"imageUrl": site.ImageUrl,
+		"picture":  site.Picture,
+		"t":        site.Translate,
 
 		"startsWith": StartsWith,
 		"mod":        Mod,
@@ -20,11 +25,17 @@ func (site *SiteBuilder) helpers() map[string]interface{} {
 }
 
 // UrlFor returns an URL to an internal page.
-func (site *SiteBuilder) UrlFor(dest string) string {
+//
+// Usage:
+//
+//	{{urlFor "posts"}}
+//	{{urlFor "page" Model.ID}}
+//	{{urlFor "post" "5565ad2c4e6f6c4a0c000004"}}
+func (site *SiteBuilder) UrlFor(dest string, param interface{}) string {
 	var result string
 
 	switch dest {
-	case kindActivities, kindMembers, kindContact, kindHomepage:
+	case kindActivities, kindMembers, kindContact, kindHomepage, kindDownloads:
 		// find uniq node
 		nodes := site.nodeBuilder(dest).Nodes()
 		if len(nodes) == 1 {
@@ -42,14 +53,92 @@ func (site *SiteBuilder) UrlFor(dest string) string {
 			}
 		}
 
+	case kindPage, kindPost:
+		var id bson.ObjectId
+
+		switch val := param.(type) {
+		case bson.ObjectId:
+			id = val
+		case string:
+			if bson.IsObjectIdHex(val) {
+				id = bson.ObjectIdHex(val)
+			}
+		}
+
+		if id == "" {
+			site.addError("Template helper urlFor", fmt.Errorf("Missing or invalid %s id: %v", dest, param))
+			break
+		}
+
+		if result = site.internalURL(dest, id); result == "" {
+			site.addError("Template helper urlFor", fmt.Errorf("Link to a missing %s: %s", dest, id.Hex()))
+		}
+
 	default:
-		// kindPage, kindPost
-		panic("Internal link kind not supported yet")
+		panic("Internal link kind not supported: " + dest)
+	}
+
+	return result
+}
+
+// ImageUrl returns the URL of an image derivative. The derivative is either defined by theme or a default one:
+// thumb, square, small, small_fill, portrait_fill, large. Use "original" to get original image URL.
+//
+// Usage:
+//
+//	{{imageUrl Cover "hero"}}
+//	{{imageUrl Cover "hero" absolute=true}}
+func (site *SiteBuilder) ImageUrl(vars *ImageVars, kind string, options *raymond.Options) string {
+	if vars == nil {
+		return ""
+	}
+
+	result, err := site.imageDerivativeURL(vars.img, kind, options.HashProp("absolute") == true)
+	if err != nil {
+		site.addError("Template helper imageUrl", err)
 	}
 
 	return result
 }
 
+// Picture returns a responsive <picture> element for an image, with given derivatives in srcset attributes and a WebP
+// source when available. Derivatives should have the same aspect ratio. Default derivatives are "small,large", and
+// default alt text is the image one.
+//
+// Usage:
+//
+//	{{picture Cover}}
+//	{{picture Cover derivatives="small,large,hero" sizes="(min-width: 768px) 50vw, 100vw" alt=Title class="img-responsive"}}
+func (site *SiteBuilder) Picture(vars *ImageVars, options *raymond.Options) raymond.SafeString {
+	if vars == nil {
+		return ""
+	}
+
+	kinds := options.HashStr("derivatives")
+	if kinds == "" {
+		kinds = "small,large"
+	}
+
+	var list []string
+	for _, kind := range strings.Split(kinds, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			list = append(list, kind)
+		}
+	}
+
+	alt := vars.Alt
+	if options.HashProp("alt") != nil {
+		alt = options.HashStr("alt")
+	}
+
+	result, err := site.pictureHTML(vars.img, list, options.HashStr("sizes"), alt, options.HashStr("class"))
+	if err != nil {
+		site.addError("Template helper picture", err)
+	}
+
+	return raymond.SafeString(result)
+}
+
 // Translate translates given sentence.
 func (site *SiteBuilder) Translate(sentence string) string {
 	T := i18n.MustTfunc(site.site.Lang)
@@ -74,9 +74,9 @@ func (builder *HomepageBuilder) NewHomepageContent(node *Node) *HomepageContent
 
 	result := &HomepageContent{}
 
-	result.Description = generateHTML(models.FormatHTML, site.Description)
-	result.MoreDesc = generateHTML(models.FormatHTML, site.MoreDesc)
-	result.JoinText = generateHTML(models.FormatHTML, site.JoinText)
+	builder.generateHTML(models.FormatHTML, site.Description, &result.Description)
+	builder.generateHTML(models.FormatHTML, site.MoreDesc, &result.MoreDesc)
+	builder.generateHTML(models.FormatHTML, site.JoinText, &result.JoinText)
 
 	logo := site.FindLogo()
 	if logo != nil {
@@ -1,11 +1,18 @@
 package builder
 
 import (
+	"bytes"
+	"fmt"
+	"html"
 	"path"
+	"strings"
 
 	"github.com/aymerick/kowa/models"
 )
 
+// WebP variants extension, cf. models
+const webpExt = ".webp"
+
 // ImageVars reprents an image variables
 type ImageVars struct {
 	Original             string
@@ -22,6 +29,18 @@ type ImageVars struct {
 	PortraitFillAbsolute string
 	Large                string
 	LargeAbsolute        string
+
+	Alt     string
+	Caption string
+	Credit  string
+
+	img *models.Image
+}
+
+// imageDerivative represents a derivative used by templates
+type imageDerivative struct {
+	image      *models.Image
+	derivative *models.Derivative
 }
 
 // NewImageVars instanciates a new ImageVars
@@ -49,5 +68,138 @@ func NewImageVars(img *models.Image, basePath string, baseURL string) *ImageVars
 
 		Large:         path.Join("/", basePath, imagesDir, img.LargePath()),
 		LargeAbsolute: baseURL + path.Join("/", imagesDir, img.LargePath()),
+
+		Alt:     img.Alt,
+		Caption: img.Caption,
+		Credit:  img.Credit,
+
+		img: img,
+	}
+}
+
+// Load derivatives defined by theme
+func (builder *SiteBuilder) initDerivatives() {
+	builder.derivatives = make(map[string]*models.Derivative)
+
+	for _, conf := range builder.theme.Conf.Derivatives {
+		derivative, err := models.NewDerivative(conf.Name, conf.Scale, conf.Width, conf.Height, conf.Anchor)
+		if err != nil {
+			builder.addError("Theme derivatives", err)
+			continue
+		}
+
+		builder.derivatives[conf.Name] = derivative
+	}
+}
+
+// Returns URL of given image derivative, and registers theme derivatives to generate
+func (builder *SiteBuilder) imageDerivativeURL(img *models.Image, kind string, absolute bool) (string, error) {
+	var imgPath string
+
+	if kind == "original" {
+		imgPath = img.Path
+	} else {
+		derivative := builder.derivativeForKind(kind)
+		if derivative == nil {
+			return "", fmt.Errorf("Unknown image derivative: %s", kind)
+		}
+
+		if builder.derivatives[kind] != nil {
+			builder.usedDerivatives[img.DerivativeFilePath(derivative)] = &imageDerivative{img, derivative}
+		}
+
+		imgPath = img.DerivativePath(derivative)
+	}
+
+	return builder.imageURL(imgPath, absolute), nil
+}
+
+// Returns theme or default derivative with given kind
+func (builder *SiteBuilder) derivativeForKind(kind string) *models.Derivative {
+	if derivative := builder.derivatives[kind]; derivative != nil {
+		return derivative
+	}
+
+	return models.DerivativeForKind(kind)
+}
+
+// Returns URL of given image path, relative to images directory
+func (builder *SiteBuilder) imageURL(imgPath string, absolute bool) string {
+	if absolute {
+		return builder.site.BaseUrl() + path.Join("/", imagesDir, imgPath)
+	}
+
+	return path.Join("/", builder.basePath(), imagesDir, imgPath)
+}
+
+// Returns a <picture> element with srcset attributes for given derivatives, and a WebP source if available
+func (builder *SiteBuilder) pictureHTML(img *models.Image, kinds []string, sizes string, alt string, class string) (string, error) {
+	var srcset, webpSrcset []string
+	var src string
+
+	for _, kind := range kinds {
+		derivative := builder.derivativeForKind(kind)
+		if derivative == nil {
+			return "", fmt.Errorf("Unknown image derivative: %s", kind)
+		}
+
+		width, err := img.DerivativeWidth(derivative)
+		if err != nil {
+			return "", err
+		}
+
+		// generate derivative and its WebP variant, if missing
+		builder.usedDerivatives[img.DerivativeFilePath(derivative)] = &imageDerivative{img, derivative}
+
+		src = builder.imageURL(img.DerivativePath(derivative), false)
+
+		srcset = append(srcset, fmt.Sprintf("%s %dw", src, width))
+		webpSrcset = append(webpSrcset, fmt.Sprintf("%s%s %dw", src, webpExt, width))
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString("<picture>")
+
+	if img.HaveWebP() {
+		buf.WriteString(`<source type="image/webp"`)
+		writeAttr(&buf, "srcset", strings.Join(webpSrcset, ", "))
+		writeAttr(&buf, "sizes", sizes)
+		buf.WriteString(">")
+	}
+
+	// largest derivative is the fallback
+	buf.WriteString("<img")
+	writeAttr(&buf, "src", src)
+	writeAttr(&buf, "srcset", strings.Join(srcset, ", "))
+	writeAttr(&buf, "sizes", sizes)
+	writeAttr(&buf, "alt", alt)
+	writeAttr(&buf, "class", class)
+	buf.WriteString("></picture>")
+
+	return buf.String(), nil
+}
+
+// Writes an HTML attribute, if value is not empty (except for alt attribute)
+func writeAttr(buf *bytes.Buffer, name string, value string) {
+	if (value == "") && (name != "alt") {
+		return
+	}
+
+	buf.WriteString(" " + name + `="` + html.EscapeString(value) + `"`)
+}
+
+// Generate derivatives used by templates, if not generated yet
+func (builder *SiteBuilder) genDerivatives() {
+	for filePath, used := range builder.usedDerivatives {
+		if !used.image.DerivativesReady() {
+			// derivatives are being generated, site will be rebuilt when done
+			delete(builder.usedDerivatives, filePath)
+			continue
+		}
+
+		if err := used.image.GenerateDerivative(used.derivative, false); err != nil {
+			builder.addError("Generate image derivatives", err)
+		}
 	}
 }
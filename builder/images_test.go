@@ -0,0 +1,195 @@
+package builder
+
+import (
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/models"
+)
+
+type ImagesTestSuite struct {
+	suite.Suite
+
+	uploadDir string
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestImagesTestSuite(t *testing.T) {
+	suite.Run(t, new(ImagesTestSuite))
+}
+
+// Called before each test
+func (suite *ImagesTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "kowa-builder-test")
+	if err != nil {
+		panic(err)
+	}
+
+	suite.uploadDir = dir
+
+	viper.Set("upload_dir", dir)
+	viper.Set("cwebp_path", "/usr/local/bin/cwebp")
+}
+
+// Called after each test
+func (suite *ImagesTestSuite) TearDownTest() {
+	viper.Set("upload_dir", "")
+	viper.Set("cwebp_path", "")
+
+	os.RemoveAll(suite.uploadDir)
+}
+
+// Returns a new site builder, with given theme derivatives
+func (suite *ImagesTestSuite) newSiteBuilder(derivatives ...*models.Derivative) *SiteBuilder {
+	result := &SiteBuilder{
+		site: &models.Site{ID: "site1", CustomURL: "http://www.example.com/blog"},
+
+		derivatives:     make(map[string]*models.Derivative),
+		usedDerivatives: make(map[string]*imageDerivative),
+		errorCollector:  NewErrorCollector(),
+	}
+
+	for _, derivative := range derivatives {
+		result.derivatives[derivative.Kind()] = derivative
+	}
+
+	return result
+}
+
+// Writes an uploaded image with given dimensions
+func (suite *ImagesTestSuite) newImage(fileName string, width int, height int) *models.Image {
+	result := &models.Image{ID: bson.NewObjectId(), SiteID: "site1", Path: fileName}
+
+	filePath := result.OriginalFilePath()
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		panic(err)
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	if filepath.Ext(fileName) == ".gif" {
+		err = gif.Encode(file, img, nil)
+	} else {
+		err = jpeg.Encode(file, img, nil)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+//
+// Tests
+//
+
+func (suite *ImagesTestSuite) TestImageDerivativeURL() {
+	t := suite.T()
+
+	hero, err := models.NewDerivative("hero", "fill", 1600, 600, "top")
+	assert.Nil(t, err)
+
+	// theme derivative overrides default one
+	small, err := models.NewDerivative("small", "fit", 400, 400, "")
+	assert.Nil(t, err)
+
+	builder := suite.newSiteBuilder(hero, small)
+	img := &models.Image{ID: bson.NewObjectId(), SiteID: "site1", Path: "party.jpg"}
+
+	tests := []struct {
+		kind     string
+		absolute bool
+		expected string
+	}{
+		{"original", false, "/blog/img/party.jpg"},
+		{"original", true, "http://www.example.com/blog/img/party.jpg"},
+		{"large", false, "/blog/img/party_l.jpg"},
+		{"hero", false, "/blog/img/party.hero-1600x600-fill-top.jpg"},
+		{"hero", true, "http://www.example.com/blog/img/party.hero-1600x600-fill-top.jpg"},
+		{"small", false, "/blog/img/party.small-400x400-fit.jpg"},
+	}
+
+	for _, test := range tests {
+		result, err := builder.imageDerivativeURL(img, test.kind, test.absolute)
+		assert.Nil(t, err, test.kind)
+		assert.Equal(t, test.expected, result, test.kind)
+	}
+
+	// only theme derivatives are generated by builder
+	assert.Len(t, builder.usedDerivatives, 2)
+	assert.NotNil(t, builder.usedDerivatives[img.DerivativeFilePath(hero)])
+	assert.NotNil(t, builder.usedDerivatives[img.DerivativeFilePath(small)])
+
+	_, err = builder.imageDerivativeURL(img, "unknown", false)
+	assert.NotNil(t, err)
+}
+
+func (suite *ImagesTestSuite) TestPictureHTML() {
+	t := suite.T()
+
+	hero, err := models.NewDerivative("hero", "fill", 1600, 600, "top")
+	assert.Nil(t, err)
+
+	builder := suite.newSiteBuilder(hero)
+
+	photo := suite.newImage("party.jpg", 1200, 800)
+	animated := suite.newImage("anim.gif", 1200, 800)
+
+	tests := []struct {
+		img      *models.Image
+		kinds    []string
+		sizes    string
+		alt      string
+		class    string
+		expected string
+	}{
+		{
+			photo, []string{"small", "large"}, "", "", "",
+			`<picture>` +
+				`<source type="image/webp" srcset="/blog/img/party_s.jpg.webp 300w, /blog/img/party_l.jpg.webp 1200w">` +
+				`<img src="/blog/img/party_l.jpg" srcset="/blog/img/party_s.jpg 300w, /blog/img/party_l.jpg 1200w" alt="">` +
+				`</picture>`,
+		},
+		{
+			photo, []string{"small_fill", "hero"}, "(min-width: 800px) 50vw, 100vw", `"Party" & co`, "cover",
+			`<picture>` +
+				`<source type="image/webp" srcset="/blog/img/party_sf.jpg.webp 300w, /blog/img/party.hero-1600x600-fill-top.jpg.webp 1600w" sizes="(min-width: 800px) 50vw, 100vw">` +
+				`<img src="/blog/img/party.hero-1600x600-fill-top.jpg" srcset="/blog/img/party_sf.jpg 300w, /blog/img/party.hero-1600x600-fill-top.jpg 1600w" sizes="(min-width: 800px) 50vw, 100vw" alt="&#34;Party&#34; &amp; co" class="cover">` +
+				`</picture>`,
+		},
+		{
+			animated, []string{"small"}, "", "Animation", "",
+			`<picture>` +
+				`<img src="/blog/img/anim_s.gif" srcset="/blog/img/anim_s.gif 300w" alt="Animation">` +
+				`</picture>`,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := builder.pictureHTML(test.img, test.kinds, test.sizes, test.alt, test.class)
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, result)
+	}
+
+	_, err = builder.pictureHTML(photo, []string{"unknown"}, "", "", "")
+	assert.NotNil(t, err)
+}
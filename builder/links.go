@@ -0,0 +1,78 @@
+package builder
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aymerick/raymond"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// internal links syntax in contents, eg: [[page:5565ad2c4e6f6c4a0c000004]]
+var internalLinkRegexp = regexp.MustCompile(`\[\[(page|post):([0-9a-fA-F]{24})\]\]`)
+
+// pendingHTML represents a content with internal links that must be resolved once all nodes are loaded
+type pendingHTML struct {
+	format string
+	input  string
+	output *raymond.SafeString
+}
+
+// Generate HTML for given content, and register it if internal links or media shortcodes must be resolved
+func (builder *SiteBuilder) generateHTML(format string, input string, output *raymond.SafeString) {
+	*output = generateHTML(format, input)
+
+	builder.addPendingMedia(input, output)
+
+	if internalLinkRegexp.MatchString(input) {
+		builder.pendingHTMLs = append(builder.pendingHTMLs, &pendingHTML{
+			format: format,
+			input:  input,
+			output: output,
+		})
+	}
+}
+
+// Resolve internal links in contents
+func (builder *SiteBuilder) resolveInternalLinks() {
+	errStep := "Resolve internal links"
+
+	for _, pending := range builder.pendingHTMLs {
+		input := internalLinkRegexp.ReplaceAllStringFunc(pending.input, func(link string) string {
+			matches := internalLinkRegexp.FindStringSubmatch(link)
+			kind, id := matches[1], bson.ObjectIdHex(matches[2])
+
+			result := builder.internalURL(kind, id)
+			if result == "" {
+				builder.addError(errStep, fmt.Errorf("Link to a missing %s: %s", kind, id.Hex()))
+			}
+
+			return result
+		})
+
+		*pending.output = generateHTML(pending.format, input)
+	}
+
+	builder.pendingHTMLs = nil
+}
+
+// Returns URL of given page or post, or an empty string if not found
+func (builder *SiteBuilder) internalURL(kind string, id bson.ObjectId) string {
+	switch kind {
+	case kindPage:
+		for _, node := range builder.nodeBuilder(kindPage).Nodes() {
+			if content, ok := node.Content.(*PageContent); ok && (content.Model.ID == id) {
+				return node.Url
+			}
+		}
+
+	case kindPost:
+		for _, node := range builder.nodeBuilder(kindPosts).Nodes() {
+			if content, ok := node.Content.(*PostContent); ok && (content.Model.ID == id) {
+				return node.Url
+			}
+		}
+	}
+
+	return ""
+}
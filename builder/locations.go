@@ -0,0 +1,96 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/raymond"
+)
+
+const (
+	mapZoom         = 16
+	staticMapWidth  = 600
+	staticMapHeight = 300
+
+	mapURLFormat       = "https://www.openstreetmap.org/?mlat=%f&mlon=%f#map=%d/%f/%f"
+	mapSearchURLFormat = "https://www.openstreetmap.org/search?query=%s"
+	staticMapURLFormat = "https://staticmap.openstreetmap.de/staticmap.php?center=%f,%f&zoom=%d&size=%dx%d&markers=%f,%f,red-pushpin"
+)
+
+// LocationVars represents location theme variables
+type LocationVars struct {
+	Name        string
+	Address     string
+	AddressHTML raymond.SafeString // Address with line breaks
+
+	HaveCoordinates bool
+	Latitude        float64
+	Longitude       float64
+
+	MapUrl       string // link to an online map
+	StaticMapUrl string // static map image, only when coordinates are known
+}
+
+// NewLocationVars instanciates a new LocationVars
+func NewLocationVars(location *models.Location) *LocationVars {
+	addrSafe := raymond.Escape(location.Address)
+
+	result := &LocationVars{
+		Name:        location.Name,
+		Address:     location.Address,
+		AddressHTML: raymond.SafeString(strings.Replace(addrSafe, "\n", "<br />\n", -1)),
+
+		HaveCoordinates: location.HaveCoordinates(),
+		Latitude:        location.Latitude,
+		Longitude:       location.Longitude,
+	}
+
+	if result.HaveCoordinates {
+		result.MapUrl = fmt.Sprintf(mapURLFormat, location.Latitude, location.Longitude, mapZoom, location.Latitude, location.Longitude)
+		result.StaticMapUrl = fmt.Sprintf(staticMapURLFormat, location.Latitude, location.Longitude, mapZoom, staticMapWidth, staticMapHeight, location.Latitude, location.Longitude)
+	} else if query := strings.TrimSpace(strings.Join([]string{location.Name, location.Address}, " ")); query != "" {
+		result.MapUrl = fmt.Sprintf(mapSearchURLFormat, url.QueryEscape(strings.Replace(query, "\n", " ", -1)))
+	}
+
+	return result
+}
+
+// jsonLD returns schema.org Place data
+func (vars *LocationVars) jsonLD() map[string]interface{} {
+	result := map[string]interface{}{
+		"@type": "Place",
+		"name":  vars.Name,
+	}
+
+	if vars.Address != "" {
+		result["address"] = strings.Replace(vars.Address, "\n", ", ", -1)
+	}
+
+	if vars.HaveCoordinates {
+		result["geo"] = map[string]interface{}{
+			"@type":     "GeoCoordinates",
+			"latitude":  vars.Latitude,
+			"longitude": vars.Longitude,
+		}
+	}
+
+	if vars.MapUrl != "" {
+		result["hasMap"] = vars.MapUrl
+	}
+
+	return result
+}
+
+// jsonLDScript returns a JSON-LD script tag with given data
+func jsonLDScript(data map[string]interface{}) raymond.SafeString {
+	// json.Marshal escapes '<' and '>' so data can't close the script tag
+	content, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+
+	return raymond.SafeString(fmt.Sprintf("<script type=\"application/ld+json\">%s</script>", content))
+}
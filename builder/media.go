@@ -0,0 +1,221 @@
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/aymerick/raymond"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/models"
+)
+
+const (
+	// theme partial used to render galleries
+	galleryPartial = "gallery"
+
+	// default derivatives for inline images and galleries
+	inlineImageDerivatives = "small,large"
+	galleryDerivative      = "small_fill"
+)
+
+// media shortcodes in contents, with an optional derivative kind, eg:
+//
+//	[[image:5565ad2c4e6f6c4a0c000004]]
+//	[[image:5565ad2c4e6f6c4a0c000004:small]]
+//	[[gallery:5565ad2c4e6f6c4a0c000004,5565ad2c4e6f6c4a0c000005]]
+//
+// A shortcode alone in its paragraph replaces that paragraph.
+var mediaShortcodeRegexp = regexp.MustCompile(`(<p>\s*)?\[\[(image|gallery):([0-9a-fA-F]{24}(?:,[0-9a-fA-F]{24})*)(?::([a-z0-9_]+))?\]\](\s*</p>)?`)
+
+// GalleryVars represents gallery partial variables
+type GalleryVars struct {
+	Images     []*ImageVars
+	Derivative string // derivative kind set in shortcode, if any
+}
+
+// Register content with media shortcodes to resolve
+func (builder *SiteBuilder) addPendingMedia(input string, output *raymond.SafeString) {
+	if mediaShortcodeRegexp.MatchString(input) {
+		builder.pendingMedia = append(builder.pendingMedia, output)
+	}
+}
+
+// Resolve media shortcodes in contents
+func (builder *SiteBuilder) resolveMedia() {
+	for _, output := range builder.pendingMedia {
+		result := mediaShortcodeRegexp.ReplaceAllStringFunc(string(*output), func(shortcode string) string {
+			matches := mediaShortcodeRegexp.FindStringSubmatch(shortcode)
+			before, kind, ids, derivative, after := matches[1], matches[2], matches[3], matches[4], matches[5]
+
+			markup := builder.mediaHTML(kind, strings.Split(ids, ","), derivative)
+
+			if (before != "") && (after != "") {
+				// shortcode is alone in its paragraph
+				return markup
+			}
+
+			return before + markup + after
+		})
+
+		*output = raymond.SafeString(result)
+	}
+
+	builder.pendingMedia = nil
+}
+
+// Returns HTML for given media shortcode
+func (builder *SiteBuilder) mediaHTML(kind string, ids []string, derivative string) string {
+	errStep := "Resolve media"
+
+	var images []*models.Image
+
+	for _, id := range ids {
+		img := builder.site.FindImage(bson.ObjectIdHex(id))
+		if img == nil {
+			builder.addError(errStep, fmt.Errorf("Reference to a missing image: %s", id))
+			continue
+		}
+
+		images = append(images, img)
+	}
+
+	if len(images) == 0 {
+		return ""
+	}
+
+	var result string
+	var err error
+
+	switch kind {
+	case "image":
+		result, err = builder.inlineImageHTML(images[0], derivative)
+	case "gallery":
+		result, err = builder.galleryHTML(images, derivative)
+	}
+
+	if err != nil {
+		builder.addError(errStep, err)
+	}
+
+	return result
+}
+
+// Returns a <figure> element for given inline image
+func (builder *SiteBuilder) inlineImageHTML(img *models.Image, derivative string) (string, error) {
+	vars := builder.addImage(img)
+
+	kinds := derivative
+	if kinds == "" {
+		kinds = inlineImageDerivatives
+	}
+
+	picture, err := builder.pictureHTML(img, strings.Split(kinds, ","), "", vars.Alt, "")
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(`<figure class="image">`)
+	buf.WriteString(picture)
+	writeFigcaption(&buf, vars)
+	buf.WriteString("</figure>")
+
+	return buf.String(), nil
+}
+
+// Returns gallery markup, rendered with theme partial if it exists
+func (builder *SiteBuilder) galleryHTML(images []*models.Image, derivative string) (string, error) {
+	vars := &GalleryVars{Derivative: derivative}
+
+	for _, img := range images {
+		vars.Images = append(vars.Images, builder.addImage(img))
+	}
+
+	if tpl := builder.galleryTemplate(); tpl != nil {
+		// inject @site private data
+		data := raymond.NewDataFrame()
+		data.Set("site", builder.siteVars)
+
+		return tpl.ExecWith(vars, data)
+	}
+
+	// default markup
+	if derivative == "" {
+		derivative = galleryDerivative
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(`<div class="gallery">`)
+
+	for _, imgVars := range vars.Images {
+		src, err := builder.imageDerivativeURL(imgVars.img, derivative, false)
+		if err != nil {
+			return "", err
+		}
+
+		buf.WriteString(`<figure><a`)
+		writeAttr(&buf, "href", imgVars.Large)
+		buf.WriteString("><img")
+		writeAttr(&buf, "src", src)
+		writeAttr(&buf, "alt", imgVars.Alt)
+		buf.WriteString("></a>")
+		writeFigcaption(&buf, imgVars)
+		buf.WriteString("</figure>")
+	}
+
+	buf.WriteString("</div>")
+
+	return buf.String(), nil
+}
+
+// Returns the gallery template, or nil if theme does not have a gallery partial
+func (builder *SiteBuilder) galleryTemplate() *raymond.Template {
+	if builder.galleryTpl == nil {
+		filePaths, err := builder.theme.Partials()
+		if err != nil {
+			builder.addError("Gallery setup", err)
+		}
+
+		for _, filePath := range filePaths {
+			if strings.TrimSuffix(path.Base(filePath), path.Ext(filePath)) == galleryPartial {
+				tpl := raymond.MustParse("{{> " + galleryPartial + "}}")
+				tpl.RegisterHelpers(builder.helpers())
+
+				if err := tpl.RegisterPartialFiles(filePaths...); err != nil {
+					builder.addError("Gallery setup", err)
+					break
+				}
+
+				builder.galleryTpl = tpl
+				break
+			}
+		}
+	}
+
+	return builder.galleryTpl
+}
+
+// Writes a <figcaption> element with image caption and credit, if any
+func writeFigcaption(buf *bytes.Buffer, vars *ImageVars) {
+	if (vars.Caption == "") && (vars.Credit == "") {
+		return
+	}
+
+	buf.WriteString("<figcaption>")
+	buf.WriteString(html.EscapeString(vars.Caption))
+
+	if vars.Credit != "" {
+		buf.WriteString(` <small class="credit">`)
+		buf.WriteString(html.EscapeString(vars.Credit))
+		buf.WriteString("</small>")
+	}
+
+	buf.WriteString("</figcaption>")
+}
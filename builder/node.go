@@ -20,10 +20,12 @@ type Node struct {
 	Tagline string
 	Cover   *ImageVars
 
-	Meta        *NodeMeta
-	BodyClass   string
-	InNavBar    bool
-	NavBarOrder int
+	Meta         *NodeMeta
+	BodyClass    string
+	InNavBar     bool
+	NavBarOrder  int
+	NavBarKey    string // eg: posts, or page id
+	NavBarParent string // eg: parent page id
 
 	Slug        string // eg: 2015/03/17/my_post
 	FilePath    string // eg: /2015/03/17/my_post/index.html
@@ -58,14 +60,20 @@ const (
 	kindPosts      = "posts"
 	kindEvent      = "event"
 	kindEvents     = "events"
+	kindRedirects  = "redirects"
+	kindDownloads  = "downloads"
 )
 
+// Default order of items in navigation bar
+const defaultNavBarOrder = 100
+
 // NewNode instnciantes a new Node
 func NewNode(builder NodeBuilder, kind string) *Node {
 	return &Node{
 		Kind:        kind,
 		BodyClass:   kind,
-		NavBarOrder: 100,
+		NavBarOrder: defaultNavBarOrder,
+		NavBarKey:   kind,
 
 		builder: builder,
 	}
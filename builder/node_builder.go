@@ -7,6 +7,7 @@ import (
 
 	"github.com/aymerick/kowa/core"
 	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/raymond"
 )
 
 // NodeBuilder is the interface for node builders
@@ -203,6 +204,21 @@ func (builder *NodeBuilderBase) addImage(img *models.Image) *ImageVars {
 	return builder.siteBuilder.addImage(img)
 }
 
+// Add files to copy
+func (builder *NodeBuilderBase) addFilesVars(files *models.FilesList) []*FileVars {
+	return builder.siteBuilder.addFilesVars(files)
+}
+
+// Add a file to copy
+func (builder *NodeBuilderBase) addFileVars(file *models.File) *FileVars {
+	return builder.siteBuilder.addFileVars(file)
+}
+
+// Generate HTML for given content
+func (builder *NodeBuilderBase) generateHTML(format string, input string, output *raymond.SafeString) {
+	builder.siteBuilder.generateHTML(format, input, output)
+}
+
 // Add a node generation error
 func (builder *NodeBuilderBase) addError(err error) {
 	builder.siteBuilder.addNodeBuilderError(builder.nodeKind, err)
@@ -1,15 +1,27 @@
 package builder
 
 import (
+	"path"
 	"time"
 
+	"github.com/aymerick/kowa/helpers"
 	"github.com/aymerick/kowa/models"
 	"github.com/aymerick/raymond"
+	"gopkg.in/mgo.v2/bson"
 )
 
 // PagesBuilder builds custom pages
 type PagesBuilder struct {
 	*NodeBuilderBase
+
+	// all site pages
+	pages map[bson.ObjectId]*models.Page
+
+	// computed slugs
+	slugs map[bson.ObjectId]string
+
+	// loaded pages contents
+	contents map[bson.ObjectId]*PageContent
 }
 
 // PageContent represents a page node content
@@ -20,6 +32,10 @@ type PageContent struct {
 	Cover *ImageVars
 	Body  raymond.SafeString
 	Url   string
+	Files []*FileVars // attached files
+
+	Parent   *PageContent
+	Children []*PageContent
 }
 
 func init() {
@@ -29,38 +45,94 @@ func init() {
 // NewPagesBuilder instanciates a new NodeBuilder
 func NewPagesBuilder(siteBuilder *SiteBuilder) NodeBuilder {
 	return &PagesBuilder{
-		&NodeBuilderBase{
+		NodeBuilderBase: &NodeBuilderBase{
 			nodeKind:    kindPage,
 			siteBuilder: siteBuilder,
 		},
+
+		pages:    make(map[bson.ObjectId]*models.Page),
+		slugs:    make(map[bson.ObjectId]string),
+		contents: make(map[bson.ObjectId]*PageContent),
 	}
 }
 
 // Load is part of NodeBuilder interface
 func (builder *PagesBuilder) Load() {
-	for _, page := range *builder.site().FindAllPages() {
-		builder.loadPage(page)
+	pages := *builder.site().FindAllPages()
+
+	for _, page := range pages {
+		builder.pages[page.ID] = page
+	}
+
+	for _, page := range pages {
+		builder.loadPage(page, map[bson.ObjectId]bool{})
+	}
+
+	// link pages contents together
+	for _, page := range pages {
+		content := builder.contents[page.ID]
+		if content == nil {
+			continue
+		}
+
+		if parent := builder.contents[page.ParentID]; (page.ParentID != "") && (parent != nil) {
+			content.Parent = parent
+			parent.Children = append(parent.Children, content)
+		}
 	}
 }
 
-// Build page
-func (builder *PagesBuilder) loadPage(page *models.Page) {
+// Build page, after its parent
+func (builder *PagesBuilder) loadPage(page *models.Page, visiting map[bson.ObjectId]bool) {
+	if _, loaded := builder.slugs[page.ID]; loaded || visiting[page.ID] {
+		return
+	}
+
+	visiting[page.ID] = true
+
+	// eg: about/history
+	slug := helpers.Pathify(page.Title)
+
+	if parent := builder.pages[page.ParentID]; (page.ParentID != "") && (parent != nil) {
+		builder.loadPage(parent, visiting)
+
+		if parentSlug := builder.slugs[parent.ID]; parentSlug != "" {
+			slug = path.Join(parentSlug, slug)
+		}
+	}
+
 	node := builder.newNode()
-	node.fillURL(page.Title)
 
 	pageContent := builder.NewPageContent(page, node)
-	if pageContent.Body != "" {
-		node.Title = page.Title
-		node.Tagline = page.Tagline
-		node.Cover = pageContent.Cover
+	if pageContent.Body == "" {
+		// page not generated, but its children still use its slug
+		builder.slugs[page.ID] = slug
+		return
+	}
+
+	node.fillURL(slug)
+	builder.slugs[page.ID] = node.Slug
 
-		node.Meta = &NodeMeta{Description: page.Tagline}
-		node.InNavBar = page.InNavBar
+	pageContent.Url = node.Url
 
-		node.Content = pageContent
+	node.Title = page.Title
+	node.Tagline = page.Tagline
+	node.Cover = pageContent.Cover
 
-		builder.addNode(node)
+	node.Meta = &NodeMeta{Description: page.Tagline}
+	node.InNavBar = page.InNavBar
+	node.NavBarOrder = defaultNavBarOrder + page.Order
+	node.NavBarKey = page.ID.Hex()
+
+	if page.ParentID != "" {
+		node.NavBarParent = page.ParentID.Hex()
 	}
+
+	node.Content = pageContent
+
+	builder.contents[page.ID] = pageContent
+
+	builder.addNode(node)
 }
 
 // NewPageContent instanciates a new PageContent
@@ -72,12 +144,17 @@ func (builder *PagesBuilder) NewPageContent(page *models.Page, node *Node) *Page
 		Url:  node.Url,
 	}
 
+	builder.generateHTML(page.Format, page.Body, &result.Body)
+	if result.Body == "" {
+		return result
+	}
+
 	cover := page.FindCover()
 	if cover != nil {
 		result.Cover = builder.addImage(cover)
 	}
 
-	result.Body = generateHTML(page.Format, page.Body)
+	result.Files = builder.addFilesVars(page.FindFiles())
 
 	return result
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/nicksnyder/go-i18n/i18n"
 
+	"github.com/aymerick/kowa/core"
 	"github.com/aymerick/kowa/models"
 	"github.com/aymerick/raymond"
 )
@@ -26,6 +27,7 @@ type PostContent struct {
 	Title string
 	Body  raymond.SafeString
 	Url   string
+	Files []*FileVars // attached files
 }
 
 // PostsContent represents the posts page content
@@ -33,6 +35,11 @@ type PostsContent struct {
 	Posts []*PostContent
 	// PrevPage string
 	// NextPage string
+
+	HaveNewsletter     bool
+	NewsletterAction   string // newsletter subscription form action URL
+	NewsletterHoneypot string // name of the field that must stay empty
+	NewsletterRedirect string // posts page URL, where visitor is redirected after subscription
 }
 
 func init() {
@@ -139,7 +146,9 @@ func (builder *PostsBuilder) NewPostContent(post *models.Post, node *Node) *Post
 		result.Cover = builder.addImage(cover)
 	}
 
-	result.Body = generateHTML(post.Format, post.Body)
+	result.Files = builder.addFilesVars(post.FindFiles())
+
+	builder.generateHTML(post.Format, post.Body, &result.Body)
 
 	return result
 }
@@ -176,9 +185,18 @@ func (builder *PostsBuilder) loadPostsLists() {
 	node.InNavBar = true
 	node.NavBarOrder = 5
 
-	node.Content = &PostsContent{
+	postsContent := &PostsContent{
 		Posts: builder.posts,
 	}
 
+	if site := builder.site(); site.Newsletter != "" {
+		postsContent.HaveNewsletter = true
+		postsContent.NewsletterAction = core.PublicAPIUrl(fmt.Sprintf("/sites/%s/subscribers", site.ID))
+		postsContent.NewsletterHoneypot = core.PublicFormHoneypot
+		postsContent.NewsletterRedirect = node.AbsoluteUrl
+	}
+
+	node.Content = postsContent
+
 	builder.addNode(node)
 }
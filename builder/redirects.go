@@ -0,0 +1,126 @@
+package builder
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path"
+
+	"github.com/aymerick/kowa/models"
+)
+
+// page generated at old URLs
+const redirectHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%[1]s</title>
+<link rel="canonical" href="%[1]s">
+<meta name="robots" content="noindex">
+<meta http-equiv="refresh" content="0; url=%[1]s">
+</head>
+<body>
+<a href="%[1]s">%[1]s</a>
+</body>
+</html>
+`
+
+// RedirectsBuilder builds redirection pages for old URLs
+type RedirectsBuilder struct {
+	*NodeBuilderBase
+
+	redirects []*models.Redirect
+}
+
+func init() {
+	RegisterNodeBuilder(kindRedirects, NewRedirectsBuilder)
+}
+
+// NewRedirectsBuilder instanciate a new NodeBuilder
+func NewRedirectsBuilder(siteBuilder *SiteBuilder) NodeBuilder {
+	return &RedirectsBuilder{
+		NodeBuilderBase: &NodeBuilderBase{
+			nodeKind:    kindRedirects,
+			siteBuilder: siteBuilder,
+		},
+	}
+}
+
+// Load is part of NodeBuilder interface
+func (builder *RedirectsBuilder) Load() {
+	builder.redirects = *builder.site().FindAllRedirects()
+}
+
+// Generate is part of NodeBuilder interface
+func (builder *RedirectsBuilder) Generate() map[string]bool {
+	result := make(map[string]bool)
+
+	if len(builder.redirects) == 0 {
+		return result
+	}
+
+	// never overwrite a generated node
+	nodePaths := make(map[string]bool)
+	for _, nodeBuilder := range builder.siteBuilder.nodeBuilders {
+		for _, node := range nodeBuilder.Nodes() {
+			nodePaths[node.FilePath] = true
+		}
+	}
+
+	for _, redirect := range builder.redirects {
+		filePath := redirectFilePath(redirect.Path)
+		if filePath == "" || nodePaths[filePath] {
+			continue
+		}
+
+		// target may have been unpublished
+		url := builder.siteBuilder.internalURL(redirect.Kind, redirect.TargetID)
+		if url == "" {
+			continue
+		}
+
+		if osFilePath := builder.generateRedirect(filePath, url); osFilePath != "" {
+			result[osFilePath] = true
+		}
+	}
+
+	return result
+}
+
+// Generate redirection page
+func (builder *RedirectsBuilder) generateRedirect(filePath string, url string) string {
+	osFilePath := builder.siteBuilder.filePath(filePath)
+
+	if err := builder.siteBuilder.ensureFileDir(osFilePath); err != nil {
+		builder.addError(err)
+		return ""
+	}
+
+	outputFile, err := os.Create(osFilePath)
+	if err != nil {
+		builder.addError(err)
+		return ""
+	}
+	defer outputFile.Close()
+
+	if _, err := fmt.Fprintf(outputFile, redirectHTML, html.EscapeString(url)); err != nil {
+		builder.addError(err)
+		return ""
+	}
+
+	return osFilePath
+}
+
+// Computes file path to generate for given redirect path, eg: /2015/03/my-post/ => /2015/03/my-post/index.html
+func redirectFilePath(redirectPath string) string {
+	result := path.Clean("/" + redirectPath)
+	if result == "/" {
+		return ""
+	}
+
+	if path.Ext(result) == "" {
+		result = path.Join(result, "index.html")
+	}
+
+	return result
+}
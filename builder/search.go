@@ -0,0 +1,204 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	searchIndexFilename = "search.json"
+
+	// max number of characters of an indexed body
+	maxSearchBodySize = 500
+
+	// max size in bytes of search index
+	maxSearchIndexSize = 512 * 1024
+)
+
+// SearchEntry represents an entry in search index
+type SearchEntry struct {
+	Kind  string `json:"k"`           // page | post | event | activity
+	Title string `json:"t"`           // title
+	URL   string `json:"u"`           // url
+	Date  string `json:"d,omitempty"` // eg: 2015-03-17
+	Body  string `json:"b,omitempty"` // stripped body
+
+	date time.Time
+}
+
+// search entries kinds, by display priority
+var searchKindsOrder = map[string]int{
+	kindPage:   0,
+	kindPost:   1,
+	kindEvent:  2,
+	"activity": 3,
+}
+
+// SearchEntries holds a sortable list of search entries: by kind priority, then most recent first
+type SearchEntries []*SearchEntry
+
+// Generate search index
+//
+// The index is a JSON array of entries, with short keys to keep it compact. Bodies are truncated, and when index is
+// too big the last entries in display order are dropped: lower priority kinds first, then oldest entries of a kind.
+func (builder *SiteBuilder) buildSearchIndex() {
+	errStep := "Build search index"
+
+	entries := builder.searchEntries()
+	sort.Sort(entries)
+
+	data, err := entries.indexJSON()
+	if err != nil {
+		builder.addError(errStep, err)
+		return
+	}
+
+	if err := ioutil.WriteFile(builder.filePath(searchIndexFilename), data, 0644); err != nil {
+		builder.addError(errStep, err)
+	}
+}
+
+// Collect search entries from all nodes
+func (builder *SiteBuilder) searchEntries() SearchEntries {
+	result := SearchEntries{}
+
+	events := make(map[bson.ObjectId]bool)
+
+	for _, nodeBuilder := range builder.nodeBuilders {
+		for _, node := range nodeBuilder.Nodes() {
+			switch content := node.Content.(type) {
+			case *PageContent:
+				result = append(result, newSearchEntry(kindPage, content.Model.Title, node.Url, string(content.Body), content.Date))
+
+			case *PostContent:
+				result = append(result, newSearchEntry(kindPost, content.Title, node.Url, string(content.Body), content.Model.PublishedAt))
+
+			case *EventContent:
+				// index recurring events only once
+				if !events[content.Model.ID] {
+					events[content.Model.ID] = true
+
+					result = append(result, newSearchEntry(kindEvent, content.Title, node.Url, string(content.Body), content.Model.StartDate))
+				}
+
+			case *ActivitiesContent:
+				for _, activity := range content.Activities {
+					body := string(activity.Summary) + " " + string(activity.Body)
+
+					result = append(result, newSearchEntry("activity", activity.Title, node.Url, body, time.Time{}))
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// Instanciates a new search entry
+func newSearchEntry(kind string, title string, url string, body string, date time.Time) *SearchEntry {
+	result := &SearchEntry{
+		Kind:  kind,
+		Title: title,
+		URL:   url,
+		Body:  truncateText(stripHTML(body), maxSearchBodySize),
+
+		date: date,
+	}
+
+	if !date.IsZero() {
+		result.Date = date.Format("2006-01-02")
+	}
+
+	return result
+}
+
+// Returns text content of given HTML, with collapsed spaces
+func stripHTML(input string) string {
+	var words []string
+
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.Join(words, " ")
+
+		case html.TextToken:
+			words = append(words, strings.Fields(string(tokenizer.Text()))...)
+		}
+	}
+}
+
+// Truncates text to given number of characters, on a word boundary
+func truncateText(text string, size int) string {
+	if utf8.RuneCountInString(text) <= size {
+		return text
+	}
+
+	runes := []rune(text)[:size]
+
+	result := string(runes)
+	if i := strings.LastIndex(result, " "); i > 0 {
+		result = result[:i]
+	}
+
+	return result + "…"
+}
+
+// Returns JSON index of entries, truncated to max index size
+func (entries SearchEntries) indexJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+
+	for i, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if buf.Len()+len(data)+2 > maxSearchIndexSize {
+			break
+		}
+
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		buf.Write(data)
+	}
+
+	buf.WriteString("]")
+
+	return buf.Bytes(), nil
+}
+
+// Implements sort.Interface
+func (entries SearchEntries) Len() int {
+	return len(entries)
+}
+
+// Implements sort.Interface
+func (entries SearchEntries) Swap(i, j int) {
+	entries[i], entries[j] = entries[j], entries[i]
+}
+
+// Implements sort.Interface
+func (entries SearchEntries) Less(i, j int) bool {
+	if searchKindsOrder[entries[i].Kind] != searchKindsOrder[entries[j].Kind] {
+		return searchKindsOrder[entries[i].Kind] < searchKindsOrder[entries[j].Kind]
+	}
+
+	if !entries[i].date.Equal(entries[j].date) {
+		return entries[i].date.After(entries[j].date)
+	}
+
+	return entries[i].Title < entries[j].Title
+}
@@ -0,0 +1,73 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *SearchTestSuite) TestIndexJSONMaxSize() {
+	t := suite.T()
+
+	body := strings.Repeat("lorem ipsum ", 100)
+	date := time.Date(2015, time.March, 17, 0, 0, 0, 0, time.UTC)
+
+	entries := SearchEntries{}
+	for i := 0; i < 500; i++ {
+		entries = append(entries, newSearchEntry("activity", fmt.Sprintf("Activity %d", i), "/activities/", body, time.Time{}))
+		entries = append(entries, newSearchEntry(kindPost, fmt.Sprintf("Post %d", i), "/posts/", body, date.AddDate(0, 0, i)))
+		entries = append(entries, newSearchEntry(kindPage, fmt.Sprintf("Page %d", i), "/pages/", body, time.Time{}))
+	}
+
+	sort.Sort(entries)
+
+	data, err := entries.indexJSON()
+	assert.Nil(t, err)
+	assert.True(t, len(data) <= maxSearchIndexSize)
+	assert.True(t, len(data) > maxSearchIndexSize-1024)
+
+	result := []*SearchEntry{}
+	assert.Nil(t, json.Unmarshal(data, &result))
+
+	// all pages, then most recent posts
+	count := map[string]int{}
+	for _, entry := range result {
+		count[entry.Kind]++
+	}
+
+	assert.Equal(t, 500, count[kindPage])
+	assert.Equal(t, 0, count["activity"])
+	assert.True(t, count[kindPost] > 0 && count[kindPost] < 500)
+
+	last := result[len(result)-1]
+	assert.Equal(t, kindPost, last.Kind)
+	assert.Equal(t, date.AddDate(0, 0, 500-count[kindPost]).Format("2006-01-02"), last.Date)
+}
+
+func (suite *SearchTestSuite) TestIndexJSONEmpty() {
+	t := suite.T()
+
+	data, err := SearchEntries{}.indexJSON()
+	assert.Nil(t, err)
+	assert.Equal(t, "[]", string(data))
+}
@@ -19,6 +19,8 @@ type SiteVars struct {
 	BaseUrl  string
 	BasePath string
 
+	SearchIndex string // URL of JSON search index
+
 	Facebook   string
 	Twitter    string
 	GooglePlus string
@@ -32,9 +34,14 @@ type SiteVars struct {
 
 // SiteNavBarItem represents an item in navigation bar
 type SiteNavBarItem struct {
-	Url   string // Item URL
-	Title string // Item title
-	Order int    // Item order
+	Url      string            // Item URL
+	Title    string            // Item title
+	Order    int               // Item order
+	External bool              // Item is a custom link
+	Children []*SiteNavBarItem // Sub items
+
+	key    string // eg: posts, page id or link id
+	parent string // parent item key
 }
 
 // NavBarItemsByOrder holds an ordered list of navigation bar items
@@ -61,6 +68,7 @@ func (vars *SiteVars) fill() {
 	vars.Name = name
 	vars.BaseUrl = site.BaseUrl()
 	vars.BasePath = vars.builder.basePath()
+	vars.SearchIndex = vars.BasePath + "/" + searchIndexFilename
 	vars.Tagline = site.Tagline
 	vars.NameInNavBar = site.NameInNavBar
 
@@ -86,19 +94,79 @@ func (vars *SiteVars) fill() {
 }
 
 func computeNavBarItems(builder *SiteBuilder) []*SiteNavBarItem {
-	result := []*SiteNavBarItem{}
+	items := []*SiteNavBarItem{}
 
+	// nodes
 	nodes := builder.navBarNodes()
 	for _, node := range nodes {
-		result = append(result, NewSiteNavBarItem(node.Url, node.Title, node.NavBarOrder))
+		item := NewSiteNavBarItem(node.Url, node.Title, node.NavBarOrder)
+		item.key = node.NavBarKey
+		item.parent = node.NavBarParent
+
+		items = append(items, item)
+	}
+
+	// custom links
+	for key, link := range builder.site.NavBarLinks {
+		item := NewSiteNavBarItem(link.URL, link.Title, defaultNavBarOrder)
+		item.External = true
+		item.key = key
+		item.parent = link.Parent
+
+		items = append(items, item)
+	}
+
+	// custom order
+	if len(builder.site.NavBarOrder) > 0 {
+		positions := make(map[string]int)
+		for i, key := range builder.site.NavBarOrder {
+			positions[key] = i + 1
+		}
+
+		for _, item := range items {
+			if pos, ok := positions[item.key]; ok {
+				item.Order = pos
+			} else {
+				// items not explicitely ordered are put at the end
+				item.Order += len(builder.site.NavBarOrder)
+			}
+		}
+	}
+
+	// build tree
+	itemsByKey := make(map[string]*SiteNavBarItem)
+	for _, item := range items {
+		itemsByKey[item.key] = item
+	}
+
+	result := []*SiteNavBarItem{}
+
+	for _, item := range items {
+		// custom links can't have children
+		if parent := itemsByKey[item.parent]; (parent != nil) && (parent != item) && !parent.External {
+			parent.Children = append(parent.Children, item)
+		} else {
+			result = append(result, item)
+		}
 	}
 
 	// sort
-	sort.Sort(NavBarItemsByOrder(result))
+	sortNavBarItems(result)
 
 	return result
 }
 
+// Sort navigation bar items, recursively
+func sortNavBarItems(items []*SiteNavBarItem) {
+	sort.Sort(NavBarItemsByOrder(items))
+
+	for _, item := range items {
+		if len(item.Children) > 0 {
+			sortNavBarItems(item.Children)
+		}
+	}
+}
+
 // NewSiteNavBarItem instanciates a new SiteNavBarItem
 func NewSiteNavBarItem(url string, title string, order int) *SiteNavBarItem {
 	return &SiteNavBarItem{
@@ -120,5 +188,9 @@ func (items NavBarItemsByOrder) Swap(i, j int) {
 
 // Implements sort.Interface
 func (items NavBarItemsByOrder) Less(i, j int) bool {
+	if items[i].Order == items[j].Order {
+		return items[i].Title < items[j].Title
+	}
+
 	return items[i].Order < items[j].Order
 }
@@ -36,7 +36,7 @@ const (
 	maxPastEvents = 5
 )
 
-var generatedPaths = []string{assetsDir, imagesDir, filesDir, faviconFilename}
+var generatedPaths = []string{assetsDir, imagesDir, filesDir, faviconFilename, searchIndexFilename}
 
 var registeredNodeBuilders = make(map[string]func(*SiteBuilder) NodeBuilder)
 
@@ -58,12 +58,27 @@ type SiteBuilder struct {
 	files          []*models.File
 	errorCollector *ErrorCollector
 
+	// theme derivatives, by name
+	derivatives map[string]*models.Derivative
+
+	// derivatives used by templates, by file path
+	usedDerivatives map[string]*imageDerivative
+
+	// contents with internal links to resolve
+	pendingHTMLs []*pendingHTML
+
+	// contents with media shortcodes to resolve
+	pendingMedia []*raymond.SafeString
+
 	// all nodes slugs
 	nodeSlugs map[string]bool
 
 	// cache for #layout method
 	masterLayout *raymond.Template
 
+	// cache for #galleryTemplate method
+	galleryTpl *raymond.Template
+
 	// internal vars
 	nodeBuilders map[string]NodeBuilder
 
@@ -76,12 +91,14 @@ func NewSiteBuilder(site *models.Site) *SiteBuilder {
 		site:  site,
 		theme: themes.Get(site.Theme),
 
-		nodeSlugs:      make(map[string]bool),
-		errorCollector: NewErrorCollector(),
-		nodeBuilders:   make(map[string]NodeBuilder),
+		nodeSlugs:       make(map[string]bool),
+		usedDerivatives: make(map[string]*imageDerivative),
+		errorCollector:  NewErrorCollector(),
+		nodeBuilders:    make(map[string]NodeBuilder),
 	}
 
 	result.initBuilders()
+	result.initDerivatives()
 
 	return result
 }
@@ -93,14 +110,26 @@ func (builder *SiteBuilder) Build() {
 		return
 	}
 
+	// resolve internal links in contents
+	builder.resolveInternalLinks()
+
 	// compute site variables
 	if builder.fillSiteVars(); builder.HaveError() {
 		return
 	}
 
+	// resolve media shortcodes in contents
+	builder.resolveMedia()
+
 	// sync nodes
 	builder.syncNodes()
 
+	// generate search index
+	builder.buildSearchIndex()
+
+	// generate derivatives used by templates
+	builder.genDerivatives()
+
 	// sync images
 	builder.syncFiles(builder.genImagesDir(), builder.imagesToSync)
 
@@ -266,15 +295,33 @@ func (builder *SiteBuilder) imagesToSync() ([]string, map[string]bool) {
 	sourceFiles := make(map[string]bool)
 
 	var files []string
+	addFile := func(filePath string) {
+		if !sourceFiles[path.Base(filePath)] {
+			files = append(files, filePath)
+
+			sourceFiles[path.Base(filePath)] = true
+		}
+	}
+
 	for _, image := range builder.images {
+		if !image.DerivativesReady() {
+			// skip images with pending derivatives, site will be rebuilt when they are generated
+			continue
+		}
+
 		for _, derivative := range models.Derivatives {
-			filePath := image.DerivativeFilePath(derivative)
+			addFile(image.DerivativeFilePath(derivative))
+		}
+	}
 
-			if !sourceFiles[path.Base(filePath)] {
-				files = append(files, filePath)
+	for filePath := range builder.usedDerivatives {
+		addFile(filePath)
+	}
 
-				sourceFiles[path.Base(filePath)] = true
-			}
+	// add generated WebP variants
+	for _, filePath := range files {
+		if _, err := os.Stat(filePath + webpExt); err == nil {
+			addFile(filePath + webpExt)
 		}
 	}
 
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/aymerick/kowa/models"
+)
+
+var cleanMediaCmd = &cobra.Command{
+	Use:   "clean_media [site_id]",
+	Short: "Clean unused media",
+	Long:  `Report images and files that are not used anywhere in given site, and delete them if --delete flag is set.`,
+	Run:   cleanMedia,
+}
+
+var cleanMediaDelete bool
+
+func initCleanMediaConf() {
+	cleanMediaCmd.Flags().BoolVar(&cleanMediaDelete, "delete", false, "Delete unused media instead of only reporting them")
+}
+
+func cleanMedia(cmd *cobra.Command, args []string) {
+	if len(args) < 1 {
+		cmd.Usage()
+		log.Fatalln("ERROR: No site id argument provided")
+	}
+
+	if viper.GetString("upload_dir") == "" {
+		cmd.Usage()
+		log.Fatalln("ERROR: The upload_dir setting is mandatory")
+	}
+
+	dbSession := models.NewDBSession()
+
+	// get site
+	site := dbSession.FindSite(args[0])
+	if site == nil {
+		cmd.Usage()
+		log.Fatalln("ERROR: Site not found:" + args[0])
+	}
+
+	images := *site.FindUnusedImages()
+	files := *site.FindUnusedFiles()
+
+	for _, image := range images {
+		log.Printf("Unused image: %s", image.Path)
+
+		if cleanMediaDelete {
+			if err := image.Delete(); err != nil {
+				log.Printf("ERROR: Failed to delete image: %s - %v", image.Path, err)
+			}
+		}
+	}
+
+	for _, file := range files {
+		log.Printf("Unused file: %s", file.Path)
+
+		if cleanMediaDelete {
+			if err := file.Delete(); err != nil {
+				log.Printf("ERROR: Failed to delete file: %s - %v", file.Path, err)
+			}
+		}
+	}
+
+	if cleanMediaDelete {
+		log.Printf("Deleted %d images and %d files", len(images), len(files))
+	} else {
+		log.Printf("Found %d unused images and %d unused files, use --delete flag to delete them", len(images), len(files))
+	}
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aymerick/kowa/archive"
+	"github.com/aymerick/kowa/models"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [site_id] [file]",
+	Short: "Export a site",
+	Long:  `Export a site with all its content and uploaded files to a zip archive.`,
+	Run:   exportSite,
+}
+
+func exportSite(cmd *cobra.Command, args []string) {
+	if len(args) < 2 {
+		cmd.Usage()
+		log.Fatalln("Missing arguments")
+	}
+
+	dbSession := models.NewDBSession()
+
+	site := dbSession.FindSite(args[0])
+	if site == nil {
+		log.Fatalln("Site not found: " + args[0])
+	}
+
+	f, err := os.Create(args[1])
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	if err := archive.Export(site, f); err != nil {
+		f.Close()
+		os.Remove(args[1])
+		log.Fatalf("ERROR: Failed to export site: %v", err)
+	}
+
+	if err := f.Close(); err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	log.Printf("Site %s exported to %s", site.ID, args[1])
+}
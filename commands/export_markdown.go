@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aymerick/kowa/markdown"
+	"github.com/aymerick/kowa/models"
+)
+
+var exportMarkdownCmd = &cobra.Command{
+	Use:   "export_markdown [site_id] [dir]",
+	Short: "Export a site as Markdown files",
+	Long: `Export posts, pages and events of a site as Markdown files with YAML front matter, and copy its images,
+so that the site can be built with a static site generator like Hugo or Jekyll.`,
+	Run: exportMarkdown,
+}
+
+func exportMarkdown(cmd *cobra.Command, args []string) {
+	if len(args) < 2 {
+		cmd.Usage()
+		log.Fatalln("Missing arguments")
+	}
+
+	site := models.NewDBSession().FindSite(args[0])
+	if site == nil {
+		log.Fatalln("Site not found: " + args[0])
+	}
+
+	if err := markdown.NewExporter(site, args[1]).Export(); err != nil {
+		log.Fatalf("ERROR: Failed to export site: %v", err)
+	}
+
+	log.Printf("Site %s exported to %s", site.ID, args[1])
+}
@@ -2,10 +2,13 @@ package commands
 
 import (
 	"log"
+	"runtime"
+	"sync/atomic"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/aymerick/kowa/derivatives"
 	"github.com/aymerick/kowa/models"
 )
 
@@ -27,17 +30,40 @@ func genDerivatives(cmd *cobra.Command, args []string) {
 		log.Fatalln("ERROR: The upload_dir setting is mandatory")
 	}
 
+	dbSession := models.NewDBSession()
+
 	// get site
-	site := models.NewDBSession().FindSite(args[0])
+	site := dbSession.FindSite(args[0])
 	if site == nil {
 		cmd.Usage()
 		log.Fatalln("ERROR: Site not found:" + args[0])
 	}
 
+	images := *site.FindAllImages()
+
 	// generate derivatives
-	for _, image := range *site.FindAllImages() {
-		if err := image.GenerateDerivatives(true); err != nil {
-			log.Printf("ERROR: Failed to generate image: %v", err)
+	var done, failed int32
+
+	pipeline := derivatives.NewPipeline(dbSession, runtime.NumCPU())
+	pipeline.OnDone = func(img *models.Image, err error) {
+		if err != nil {
+			atomic.AddInt32(&failed, 1)
 		}
+
+		log.Printf("[%d/%d] %s", atomic.AddInt32(&done, 1), len(images), img.Path)
+	}
+
+	pipeline.Start()
+
+	for _, image := range images {
+		if err := pipeline.Enqueue(image, true); err != nil {
+			log.Printf("ERROR: Failed to enqueue image: %v", err)
+		}
+	}
+
+	pipeline.Stop()
+
+	if failed > 0 {
+		log.Printf("ERROR: Failed to generate %d images", failed)
 	}
 }
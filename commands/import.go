@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"archive/zip"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aymerick/kowa/archive"
+	"github.com/aymerick/kowa/models"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [file] [site_id] [user_id]",
+	Short: "Import a site",
+	Long:  `Create a new site from a zip archive generated by the export command. Site id and owner default to the exported ones.`,
+	Run:   importSite,
+}
+
+func importSite(cmd *cobra.Command, args []string) {
+	if len(args) < 1 {
+		cmd.Usage()
+		log.Fatalln("Missing arguments")
+	}
+
+	var siteID, userID string
+
+	if len(args) > 1 {
+		siteID = args[1]
+	}
+
+	if len(args) > 2 {
+		userID = args[2]
+	}
+
+	zr, err := zip.OpenReader(args[0])
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+	defer zr.Close()
+
+	dbSession := models.NewDBSession()
+
+	site, err := archive.Import(dbSession, &zr.Reader, siteID, userID)
+	if err != nil {
+		log.Fatalf("ERROR: Failed to import site: %v", err)
+	}
+
+	log.Printf("Site %s imported", site.ID)
+
+	// build site
+	buildSite(site)
+}
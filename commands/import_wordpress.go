@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/kowa/wordpress"
+)
+
+var importWordpressCmd = &cobra.Command{
+	Use:   "import_wordpress [site_id] [export.xml] [media dir]",
+	Short: "Import a WordPress export",
+	Long: `Import posts, pages and images from a WordPress eXtended RSS (WXR) export file.
+
+Images are copied from the media dir, that is a local copy of the WordPress wp-content/uploads directory
+(default: the uploads directory next to the export file). Old URLs are recorded as redirects.`,
+	Run: importWordpress,
+}
+
+func importWordpress(cmd *cobra.Command, args []string) {
+	if len(args) < 2 {
+		cmd.Usage()
+		log.Fatalln("Missing arguments")
+	}
+
+	mediaDir := filepath.Join(filepath.Dir(args[1]), "uploads")
+	if len(args) > 2 {
+		mediaDir = args[2]
+	}
+
+	dbSession := models.NewDBSession()
+
+	site := dbSession.FindSite(args[0])
+	if site == nil {
+		log.Fatalln("Site not found: " + args[0])
+	}
+
+	f, err := os.Open(args[1])
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+	defer f.Close()
+
+	export, err := wordpress.Parse(f)
+	if err != nil {
+		log.Fatalf("ERROR: Failed to parse WordPress export: %v", err)
+	}
+
+	report, err := wordpress.NewImporter(dbSession, site, mediaDir).Import(export)
+
+	log.Printf("Imported %d posts, %d pages, %d images and %d redirects", report.Posts, report.Pages, report.Images, report.Redirects)
+
+	if err != nil {
+		log.Fatalf("ERROR: Failed to import WordPress export: %v", err)
+	}
+
+	// build site
+	buildSite(site)
+}
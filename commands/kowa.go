@@ -63,6 +63,9 @@ func initKowaConf() {
 	rootCmd.PersistentFlags().IntP("serve_output_port", "T", defaultServePort, "Port to serve built sites")
 	viper.BindPFlag("serve_output_port", rootCmd.PersistentFlags().Lookup("serve_output_port"))
 
+	rootCmd.PersistentFlags().String("cwebp_path", "", "Path to cwebp binary, used to generate WebP images (default is to look for cwebp in PATH)")
+	viper.BindPFlag("cwebp_path", rootCmd.PersistentFlags().Lookup("cwebp_path"))
+
 	// service
 	rootCmd.PersistentFlags().String("service_name", defaultServiceName, "Service name")
 	viper.BindPFlag("service_name", rootCmd.PersistentFlags().Lookup("service_name"))
@@ -73,6 +76,9 @@ func initKowaConf() {
 	rootCmd.PersistentFlags().String("service_url", defaultServiceURL, "Service URL")
 	viper.BindPFlag("service_url", rootCmd.PersistentFlags().Lookup("service_url"))
 
+	rootCmd.PersistentFlags().String("api_url", "", "Public API URL used by built sites (default is service_url + '/api')")
+	viper.BindPFlag("api_url", rootCmd.PersistentFlags().Lookup("api_url"))
+
 	rootCmd.PersistentFlags().String("service_copyright_notice", defaultServiceCopyright, "Service copyright notice")
 	viper.BindPFlag("service_copyright_notice", rootCmd.PersistentFlags().Lookup("service_copyright_notice"))
 
@@ -126,6 +132,13 @@ func addCommands() {
 	rootCmd.AddCommand(addUserCmd)
 	rootCmd.AddCommand(addSiteCmd)
 	rootCmd.AddCommand(fixImagesCmd)
+	rootCmd.AddCommand(cleanMediaCmd)
+	rootCmd.AddCommand(sendDigestCmd)
+	rootCmd.AddCommand(pruneAPITokensCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(exportMarkdownCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(importWordpressCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -137,6 +150,7 @@ func addCommands() {
 func InitConf() {
 	initKowaConf()
 	initServerConf()
+	initCleanMediaConf()
 }
 
 // Execute executes command
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aymerick/kowa/models"
+)
+
+const (
+	defaultAPITokensMaxIdleDays = 90
+)
+
+var pruneAPITokensCmd = &cobra.Command{
+	Use:   "prune_api_tokens [days]",
+	Short: "Prune stale API tokens",
+	Long:  `Delete personal API tokens that have not been used during given number of days (default: 90).`,
+	Run:   pruneAPITokens,
+}
+
+func pruneAPITokens(cmd *cobra.Command, args []string) {
+	days := defaultAPITokensMaxIdleDays
+
+	if len(args) > 0 {
+		var err error
+
+		if days, err = strconv.Atoi(args[0]); err != nil || days <= 0 {
+			cmd.Usage()
+			log.Fatalln("ERROR: Invalid number of days: " + args[0])
+		}
+	}
+
+	dbSession := models.NewDBSession()
+
+	removed, err := dbSession.RemoveStaleAPITokens(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		log.Fatalf("ERROR: %v", err)
+	}
+
+	log.Printf("Removed %d stale API tokens", removed)
+}
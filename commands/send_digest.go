@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/aymerick/kowa/mailers"
+	"github.com/aymerick/kowa/models"
+)
+
+const (
+	digestPeriod = 7 * 24 * time.Hour
+)
+
+var sendDigestCmd = &cobra.Command{
+	Use:   "send_digest [site_id]",
+	Short: "Send newsletter digest",
+	Long:  `Send a digest of posts published during last week to newsletter subscribers. Sends to all sites with digest newsletter if no site id is provided. Run that command weekly.`,
+	Run:   sendDigest,
+}
+
+func sendDigest(cmd *cobra.Command, args []string) {
+	dbSession := models.NewDBSession()
+
+	if viper.GetString("mail_tpl_dir") != "" {
+		// Set templates dir for mails
+		mailers.SetTemplatesDir(viper.GetString("mail_tpl_dir"))
+	}
+
+	var sites models.SitesList
+
+	if len(args) > 0 {
+		site := dbSession.FindSite(args[0])
+		if site == nil {
+			cmd.Usage()
+			log.Fatalln("ERROR: Site not found:" + args[0])
+		}
+
+		sites = models.SitesList{site}
+	} else {
+		sites = *dbSession.FindNewsletterSites(models.NewsletterDigest)
+	}
+
+	for _, site := range sites {
+		sendSiteDigest(site)
+	}
+}
+
+func sendSiteDigest(site *models.Site) {
+	now := time.Now()
+
+	since := site.DigestSentAt
+	if since.IsZero() || now.Sub(since) > digestPeriod {
+		since = now.Add(-digestPeriod)
+	}
+
+	posts := []*models.Post{}
+	for _, post := range *site.FindPublishedPosts() {
+		if post.PublishedAt.After(since) {
+			posts = append(posts, post)
+		}
+	}
+
+	if len(posts) == 0 {
+		log.Printf("No new post to send for site: %s", site.ID)
+		return
+	}
+
+	sent, err := mailers.NewDigestNewsletter(posts, site).Send(*site.FindConfirmedSubscribers())
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+	}
+
+	if sent > 0 {
+		site.SetDigestSentAt(now)
+	}
+}
@@ -14,6 +14,10 @@ import (
 const (
 	defaultPort = 35830
 
+	defaultUploadMaxSize     = 10   // MB
+	defaultSiteStorageQuota  = 500  // MB
+	defaultImageMaxDimension = 8000 // pixels
+
 	defaultSMTPFrom = "Kowa Server <kowa@localhost>"
 	defaultSMTPHost = "127.0.0.1"
 	defaultSMTPPort = 25
@@ -33,6 +37,22 @@ func initServerConf() {
 	serverCmd.Flags().String("secret_key", "", "Secret key used to sign tokens")
 	viper.BindPFlag("secret_key", serverCmd.Flags().Lookup("secret_key"))
 
+	serverCmd.Flags().Bool("two_factor_required", false, "Require all users to enable two-factor authentication")
+	viper.BindPFlag("two_factor_required", serverCmd.Flags().Lookup("two_factor_required"))
+
+	serverCmd.Flags().String("trusted_proxies", "", "Comma separated IP addresses or CIDR ranges of reverse proxies allowed to set the X-Forwarded-For header")
+	viper.BindPFlag("trusted_proxies", serverCmd.Flags().Lookup("trusted_proxies"))
+
+	// Uploads
+	serverCmd.Flags().Int("upload_max_size", defaultUploadMaxSize, "Maximum size of uploaded files, in megabytes (0 means unlimited)")
+	viper.BindPFlag("upload_max_size", serverCmd.Flags().Lookup("upload_max_size"))
+
+	serverCmd.Flags().Int("site_storage_quota", defaultSiteStorageQuota, "Maximum storage used by uploaded files of a site, in megabytes (0 means unlimited)")
+	viper.BindPFlag("site_storage_quota", serverCmd.Flags().Lookup("site_storage_quota"))
+
+	serverCmd.Flags().Int("image_max_dimension", defaultImageMaxDimension, "Maximum width and height of uploaded images, in pixels (0 means unlimited)")
+	viper.BindPFlag("image_max_dimension", serverCmd.Flags().Lookup("image_max_dimension"))
+
 	// Mail
 	serverCmd.Flags().String("mail_tpl_dir", "", "Mail templates directory. If not provided, default templates are used.")
 	viper.BindPFlag("mail_tpl_dir", serverCmd.Flags().Lookup("mail_tpl_dir"))
@@ -2,10 +2,20 @@
 // sources:
 // locales/en.json
 // locales/fr.json
+// mailers/templates/contact.html.hbs
+// mailers/templates/contact.txt.hbs
 // mailers/templates/layout.html.hbs
 // mailers/templates/layout.txt.hbs
+// mailers/templates/newsletter.html.hbs
+// mailers/templates/newsletter.txt.hbs
+// mailers/templates/password_reset.html.hbs
+// mailers/templates/password_reset.txt.hbs
+// mailers/templates/registration.html.hbs
+// mailers/templates/registration.txt.hbs
 // mailers/templates/signup.html.hbs
 // mailers/templates/signup.txt.hbs
+// mailers/templates/subscription.html.hbs
+// mailers/templates/subscription.txt.hbs
 // DO NOT EDIT!
 
 package core
@@ -74,7 +84,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _localesEnJson = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xac\x58\x4d\x6f\xe3\x38\x0c\xbd\xcf\xaf\x10\x7a\x2e\x82\x05\x76\x4f\xbd\x75\xa7\xed\xa1\x40\xa6\x01\xda\x45\x31\x58\x2c\x0c\xc5\x66\x6c\x4d\x6c\x29\xd0\x47\x82\xa0\xe8\x7f\x5f\x4a\xb2\xe3\x74\x62\x4a\xee\x60\x0e\xf3\x11\xf3\xbd\x47\x9a\xa2\x24\xd2\xff\x7e\x61\xec\x0d\xff\x30\x76\x25\xaa\xab\x1b\x76\xc5\x4b\x2b\xf6\xc2\x0a\x30\x57\xd7\xf1\xb9\xd5\x5c\x9a\x96\x5b\xa1\xa4\x07\xdc\x8e\x00\xb4\xbf\x5f\x5f\x08\x54\x95\x06\x43\xb2\x7b\xeb\x24\x75\xcd\xcb\x6d\x61\x55\x01\x7b\x90\x96\x52\xf8\x1b\x41\xcc\x2a\xd6\x83\x92\x42\x3b\x65\xb2\x3a\x11\x33\x29\x53\x2a\x69\x31\x1f\x84\xc0\xd7\xde\x4a\x50\xf7\xa0\x09\x62\xb4\x4d\xd2\x2a\x6e\xa1\xb0\xa2\x03\x53\x08\x69\x41\xef\x79\x4b\x88\xbc\xbd\x2d\x9e\x2d\xd7\xf6\x0e\x19\xef\xef\x6c\xa3\x55\xc7\x86\x67\x2f\x28\x80\xcf\xf0\xe5\xf0\xc9\xbd\xac\xe2\x6f\xda\x63\xd6\xd9\xc3\xb9\xba\xf7\xf8\xb3\x87\xf1\xd9\xb4\x17\xe8\xb8\xa0\xc4\xef\x83\x8d\xa0\xed\xec\xb1\x30\x02\x93\x22\x79\x07\x84\xc0\x77\xe5\x34\xf3\xa0\xac\x88\xe5\x75\x2b\x24\xa5\xf3\x0a\x6d\xa9\x3a\xf0\x6f\x75\xf4\x92\x12\x0e\x41\x76\xc1\x56\x2d\x70\x83\x06\xbe\xc5\xbf\x44\x84\x54\x60\x4a\x2d\xd6\xc0\x0e\x0d\xb7\x91\xe0\xc1\x4c\x18\xc6\xd7\xca\x59\x26\x24\xb3\x0d\x30\x5e\x75\x42\x0a\x83\xbe\xbc\x9f\x05\x11\x63\xaa\xe2\xef\x13\x95\x1e\x88\xc5\x46\xe9\x8e\xdb\xc2\xaf\xa5\x0f\x8f\x2e\x99\x57\x80\x6d\xc5\x8f\xb8\x74\xf8\x63\x89\x05\xdc\xc4\xff\xde\x0d\xcf\x92\x8b\xf8\xb3\xaf\x5f\xf4\x33\xad\xde\xeb\x26\xe2\xff\xf3\xe6\x8f\xbf\x56\x4b\x8a\xdd\xb6\xea\x50\x38\x2a\x87\x0f\xc1\xce\x9c\x61\x4a\x32\xa3\x4a\xc1\x5b\x5c\x5f\x7b\x50\x7a\x4b\x64\xb6\x55\xb5\x22\xc4\x82\x69\x92\xd4\x41\xb7\x06\x4d\x05\xb1\xec\xad\x29\x6a\x23\x76\x21\xc5\x69\x09\x44\xb1\x80\x9a\x96\xf2\x09\x2f\x1e\xb9\x74\x5c\x1f\x09\xa1\xc1\x9a\x10\x30\x8d\xd2\xd6\xcb\xd0\x12\x29\xfa\x03\xac\x35\xed\x7f\xb0\x66\xfd\x23\x90\x96\x48\xd1\x97\x5c\x97\x0d\x95\xc6\x60\xcb\xfa\x46\x18\x2d\x90\xa2\xdf\xee\x34\x79\xde\x45\x5b\xd6\x37\xc2\x68\x81\xf4\x7b\x53\x39\xf7\x96\x19\xef\xfc\x8b\xf4\x47\x47\x9e\xac\xc1\x94\xaf\x34\x47\x56\x9a\x4b\x56\xda\xa3\x6b\xc9\x32\xf7\xa6\x19\x9e\xa9\xc5\xf2\x96\xd4\x3a\xbb\xda\x19\xaa\x41\xe8\x8d\xf9\x95\x76\x35\xad\x90\xa2\x3f\xc3\xce\x86\xf3\x80\xa0\x8f\xf6\x6c\x0c\x08\xa5\x45\x52\xf4\xa7\xd2\x2a\x3a\x82\xc1\x9a\xf5\xff\x44\xb6\x59\x4f\x54\x8b\x15\xe9\xdf\xb0\x99\x4a\xa4\xe0\x64\xce\x46\x80\x48\x5a\x23\x45\xbf\x83\x32\x15\xc1\xc9\x9c\x8d\x00\x91\xb4\x06\x45\xd7\x80\xcd\xdb\x46\x91\x57\x0e\x02\x58\x04\x4c\x0a\xec\xb8\xb1\xe9\x7e\x7b\x85\x88\x64\xb3\x9d\x6a\xb2\x57\x74\x73\xed\x69\xf3\x9a\x89\xef\xc0\xf5\x67\x3a\x09\x23\x6a\xe9\x76\x85\xa8\x30\x33\xd8\xd3\xe2\xc3\x69\xe9\x97\x06\x7b\x35\x51\xe1\x9b\x89\x8d\x00\xed\x3b\xb7\x9e\x70\xcd\x76\xb1\xe3\x2b\x1b\xa5\xf0\x1f\x2e\xcf\x71\xd6\x37\x7c\x61\x36\x10\xd2\xf7\x13\xed\x91\xb5\x60\xb1\x81\xc6\xce\x4f\x56\x4c\xba\x70\x45\x13\x9d\xde\x18\x9c\x54\xb6\xe0\x7b\xec\x7d\xf9\xba\xa5\xde\x7e\x22\x44\xa4\xb1\x91\x96\xf1\x61\x95\x8a\xc5\x35\x5f\x1f\x29\x2c\x50\x4e\x49\x00\x3f\x1c\x30\x7c\x67\xff\xd3\xe2\xeb\x6b\xf0\x99\xe1\x1a\xa7\x9f\xc4\x8b\x82\xc5\x18\x3a\x61\x8c\x90\x75\x41\x2e\xc2\xea\x83\x8f\x0f\x79\xc6\xe2\x18\xfb\xea\x39\x4e\x12\x33\xc2\x07\x37\x63\xb3\x1e\x18\xa9\x1c\x86\xc1\xa5\x08\x43\xb1\x1f\xcd\x78\x59\x2a\x27\xc9\x03\xbf\x87\xb1\x01\xf6\x09\x65\x1f\xd3\x5c\xf9\x30\xf0\xdc\x46\xec\x0d\x6e\x06\xb6\xe8\x4d\x08\xfc\x47\xb7\x2c\xb3\x31\xa2\xe7\xb2\x15\x38\x24\xaf\x9d\xb5\x8a\xba\x76\xbf\x7a\x48\x18\x62\x22\x8c\xad\xc1\x37\xd1\x38\xfc\x0c\x61\xc7\x54\xf6\x61\xa7\x2b\x3e\x7a\x9d\xb1\x23\x03\x90\xf5\x9f\x11\xce\x36\xe5\x1c\xf9\xd9\x7b\x2a\x3a\xf1\xb3\x5a\xab\x81\x57\x47\xa6\xa1\xc6\x21\x0d\x34\xcc\xf2\xa3\x24\x14\xe1\xec\x45\x0a\x75\x71\x3e\xe2\xe5\x8f\xa7\x03\x30\x0f\x64\x1e\xb8\x58\xcc\xd1\x36\x6e\xfd\x03\xc8\xeb\xf0\xf6\x43\xe6\xfd\x4c\x8e\x83\xbb\x28\xe1\x1b\x0f\x13\xf9\x27\x96\x02\x8f\x31\xb9\xa5\x0e\xee\x97\x60\x0c\x5b\xf0\x87\xc2\xf9\x55\xd6\x17\xbe\xd2\x3e\xf0\x62\x31\x38\x5d\xc5\x23\xe8\x00\x7c\x9b\x1a\xde\x07\xf0\x70\xfe\x78\x3c\x75\xfc\x34\x20\xea\xc6\xce\x38\x7f\x62\x1c\xce\x80\xf6\xbb\x3c\x53\x79\x21\x8c\x01\x9b\xba\x09\x46\xd0\xef\xb8\x06\x4e\xd1\xcd\x2e\xdc\xf3\x18\x2f\x6b\xf7\xf2\xe2\x52\xb8\x7d\xb5\x2f\xc3\x99\x81\xe4\x6e\x8c\x8b\x44\x5d\xde\x17\xb9\x54\x9d\x96\x72\xe3\xb5\xb2\x0b\x89\x0e\xea\xd6\x7f\x00\xda\x8b\x3a\x86\x41\xa4\x47\xd5\x88\x63\x67\xb8\x49\xb9\x43\xfc\x34\x51\x2c\x49\xa1\xe5\x0c\x6a\x45\x4f\x49\xd1\x98\x14\x78\x71\xd4\x1a\x67\x7d\x23\xd5\xd0\xce\x07\x6b\x52\xe2\x15\xa8\x5d\xe0\x2d\x39\xaa\x4c\xf9\x1f\xed\xe9\x97\x68\x1c\x59\xe3\x2e\x4b\xd5\xa9\x04\x0c\xe6\xa4\xc8\x83\x16\xe4\x97\x4e\x91\xa5\xd2\xde\x7b\x63\x52\xe0\x99\x53\x5b\xcb\x5b\x72\x54\xa7\x69\xef\x27\x73\x5a\x84\x9c\xb2\x9f\xa9\x29\xfb\x8c\x9a\xf0\xee\x4e\x75\xff\xe5\xbf\xff\x03\x00\x00\xff\xff\xac\x1f\x83\xf8\x57\x18\x00\x00")
+var _localesEnJson = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xad\x5a\xdd\x8f\xdb\x36\x0c\x7f\xef\x5f\xa1\xdd\xcb\x5e\x0e\xc1\x80\xed\xe9\x5e\x8a\x5b\xaf\x07\xac\xd8\xb5\x87\x5d\xbb\xa2\x28\x0a\x43\xb1\x99\x44\x8d\x63\xb9\xb2\x7c\x69\x50\xe4\x7f\x1f\x29\xf9\x2b\x8d\x29\xcb\xb7\x7b\xe8\x47\x4c\xf2\x47\x8a\xa2\x28\x92\xf6\xe7\x17\x42\xfc\xc0\x3f\x42\x5c\xa8\xec\xe2\x4a\x5c\xc8\xd4\xaa\x47\x65\x15\x54\x17\x97\xfe\xb9\x35\xb2\xa8\x72\x69\x95\x2e\x88\xe1\xba\x67\x40\xfa\xf1\xf2\x0c\x20\xcb\x0c\x54\xac\x74\x43\x1d\x15\x5d\xca\x74\x9b\x58\x9d\xc0\x23\x14\x96\x43\xf8\x13\x99\x84\xd5\xa2\x61\x0a\x02\x95\xba\x9a\xc4\xf1\x3c\xa3\x30\xa9\x2e\x2c\xfa\x83\x01\x78\xd5\x50\x43\xa2\xc9\x52\x67\x87\x64\xa7\xaa\x4a\x15\x6b\x06\xe7\x3e\x07\x59\x81\xc0\xd5\x80\x11\x07\x5d\x1b\xb1\x43\x0f\xc9\x35\x2c\x22\xa0\xad\xd6\x49\xae\x59\xec\x4f\x03\x38\xa1\x2a\x5c\xaf\x16\xc4\x3e\x01\x0d\x3b\xa9\xf2\x64\x65\xf4\x8e\xc1\xbd\x45\xd2\x55\x0c\x86\x2a\x1e\x65\x8e\x84\x71\x98\xf7\x1b\xb4\xc9\x31\x92\x71\x0d\x6f\x94\x6d\x05\xec\x93\x66\x5d\xfc\xd2\x85\x81\x14\xd4\x23\x64\x42\x0a\x14\xe8\x1c\x41\x0b\xf3\x9e\xde\xc3\xb2\x52\x16\xc4\x8f\x1f\x8b\x07\xfc\xf7\xad\xdc\xc1\xf1\x18\x65\x80\x81\x32\x3f\x30\xaa\xff\x21\x1a\xc5\x96\xed\xd7\x87\xbf\x90\x67\x8f\x5b\x9c\x29\x34\xcb\xb6\x0c\x20\x2a\x28\x32\x30\x51\x4a\xab\x7a\xf9\x15\xd8\x80\xfc\x7c\xb2\x8a\x2f\xe2\x6d\xbf\xe4\x19\xe0\xc9\x5e\xd9\xcd\x4c\x4d\xf4\xd3\x0b\x1c\x8f\x61\x55\x05\x4a\xcc\x3e\x0f\x24\xc4\xfa\xe7\x11\x0c\x03\xe4\x69\xa3\x62\x99\xb4\x90\x58\x85\xde\xc1\x08\x45\x3d\x18\x78\x0c\x08\x2d\xcd\x4a\x63\x6f\x50\xe2\x78\xf4\xa1\xd3\x3e\x7b\xaf\x68\xf9\xb4\x8f\xf8\xe4\x75\x91\xf9\xdf\xbc\xc6\x49\x65\xb7\x43\x74\xd2\xf8\xb3\x86\xfe\x19\xa3\x45\xef\x8b\x5c\xcb\x8c\x4b\x7a\x37\x1d\x7d\x54\xdc\x05\x02\x23\xfa\xda\xd1\x18\xb1\xd2\x1e\x12\x3a\x48\x6e\x7f\x43\xb9\x88\x98\x26\x41\xac\x5c\xe7\xaa\xe0\x70\x3e\x42\x9e\xea\x1d\x90\x53\x7c\x74\x60\x98\x93\xd8\x42\x34\x91\x63\xe5\x16\xff\x52\x9e\x25\x83\x2a\x35\x6a\x09\x62\xbf\x91\xd6\x0b\xb8\x13\x8f\x07\x53\x2e\x75\x6d\x31\xeb\xb8\x53\x28\xb3\x9d\x2a\x54\x85\xba\x48\x0f\x13\x6d\xc1\x8b\xe9\x75\xe0\x42\x72\x82\xc9\x4a\x9b\x9d\xb4\x09\x85\x02\x99\xc7\x47\xdc\x47\x80\x6d\x26\x0f\xb8\xf3\xf8\xe3\x0e\x8f\xce\xc6\xff\xf7\xa6\x7d\x16\x8a\x81\x33\x5d\x4f\xd4\x33\x8e\xde\xe0\x06\xec\xff\xfd\xea\xb7\x3f\xee\xef\x38\xe9\x3c\xd7\xfb\xa4\xe6\x7c\x78\xeb\xe8\xa2\xae\x84\x2e\x44\xa5\x53\x25\x73\xdc\x5f\xbb\xd7\x66\xcb\x78\x36\xd7\x6b\xcd\x80\x39\xd2\xa8\xd0\x0e\x76\x4b\x30\x9c\x11\x77\x0d\x35\x24\xba\x51\xa5\x73\x71\x18\x02\xb9\x84\xe3\x1a\x87\x22\x87\x27\x6f\x64\x51\x4b\xc3\x5d\x25\x2d\x35\x00\x50\x6d\xb4\xb1\x04\xc3\x43\x84\xc4\x6f\x61\x69\x78\xfd\x2d\x75\x52\x3f\x32\xf2\x10\x21\xf1\x3b\x69\xd2\x0d\xe7\x46\x47\x9b\xd4\x8d\x6c\x3c\x40\x48\xfc\xba\x34\x6c\xbe\xf3\xb4\x49\xdd\xc8\xc6\x03\x84\xd7\x7d\x60\x8d\x3e\xc4\xac\xf9\x89\xe2\x6f\x6a\x36\xb3\x3a\xd2\x74\xa4\xd5\x05\x2f\x1f\xd6\xcc\x56\x4c\x8e\x14\xa1\x39\xe7\xe5\x83\xfb\x5c\xaf\xeb\x8a\x2b\x66\x1a\xe2\xf4\x4e\xd7\x6b\x1e\x21\x24\xfe\x00\xa5\x75\xf9\x80\x11\xef\xe9\x93\x36\x20\x2b\x0f\x12\x12\x7f\x97\x5a\xcd\x5b\xd0\x52\x27\xf5\xbf\x63\x4b\xc2\x77\x69\xd0\x85\x6f\xb1\x16\x0b\xb8\xa0\x23\x4f\x5a\x80\x9c\x3c\x46\x48\xfc\x06\x7b\x81\x80\x05\x1d\x79\xd2\x02\xe4\xe4\x31\x38\x71\x03\x58\xfb\xad\x34\x7b\xe5\x20\x83\xf0\x0c\xa3\x00\x58\xe3\x54\x39\x58\x2c\x1e\x9b\x62\x3d\x53\x6b\xa8\xec\xcc\x32\xfd\x6f\xaa\x41\x2d\x15\x4c\x33\xd5\x58\x65\x73\x2e\x69\x0c\x40\xfb\xea\xb8\x53\x1a\xa9\x87\x9a\xf0\xf9\x3d\xc7\x7b\x32\x6b\x9e\x8e\xd0\x4a\xa8\x5f\x22\x26\xaa\x3d\x9e\xb2\x08\x03\x32\x4b\x68\xab\xd9\xb6\x50\x66\x84\xdd\x34\x9d\x91\xa8\x75\x81\x7e\xf1\x35\x2c\x83\xfb\xa1\xe7\x18\xd9\x81\xc0\x76\x97\xb2\xaa\xb0\xb4\xca\xd0\xf2\x0a\xda\x36\x30\xcd\x55\xba\x4d\x96\xb5\xb5\x9a\xcb\xf3\x0f\x58\x7e\xeb\x02\xb0\xbd\xfe\x56\xe3\xde\x77\xfd\x75\x8b\x47\x35\x8f\x2f\xb5\xc9\x12\x6c\x78\x54\xda\x1a\x23\xd3\x54\xd7\x85\x5d\x88\x57\xa4\xc6\x55\xde\x5e\x95\x58\x02\x55\x7e\x58\xb1\xa7\x1b\xad\xb1\x8a\x3f\x85\x5c\xcc\x58\x01\x7c\x2f\x95\x2f\xe3\x43\x63\x07\xec\x30\xb6\xc2\xb1\x02\x8d\x1e\x04\x2d\x68\x83\x36\xcf\xd1\x84\xeb\x5c\x6b\x9b\xb4\x34\xb6\xa8\x25\x2e\xef\x90\x96\xf5\xe5\x0c\x2d\x05\xaa\x40\x61\x06\xfd\xaf\x15\x21\x8b\x4c\x65\xc5\xaf\xb6\xdd\x12\xf4\x5e\xb7\x19\x0e\xea\xd2\x31\xa5\x12\xab\x6a\xb9\x82\xfc\x20\xd4\xba\xa0\x9c\xd3\x4f\x28\xae\x4e\x0d\x14\x7b\x4d\x78\xe9\x46\x16\xec\x24\x6a\xd4\x5a\xff\x7f\xf2\x2e\x7b\x0c\x90\x41\xb8\x6e\xf0\xbe\x01\xb8\xc2\x40\x11\x0b\x47\xf8\x60\x72\xc1\x9d\xb8\x80\xbe\x89\x2d\xf0\x3a\x3b\xa6\x78\xf4\x70\x4e\xf2\xb0\xe3\x91\x3e\x2f\x72\xad\xde\x42\x11\x33\x2d\x3b\xdd\x56\x1f\xc5\xfd\xec\x4c\xe0\xc1\xdb\xc8\xaa\x89\xeb\xec\x52\x94\xbe\x25\xee\xc3\x82\x0e\x15\x86\x3a\x6f\x95\x0d\x8f\x5f\x71\xcb\x6c\x70\xf6\x1a\x9a\xb9\xde\xf3\xb3\x56\x97\x9d\xa3\x9a\xd6\x4f\x20\xcd\x9c\x8e\xd5\xc0\xba\xeb\xeb\x13\x99\x53\x96\x3e\x24\xfe\x21\xa0\x8f\x02\xe3\x43\x89\x07\xa4\x11\x10\xbd\x40\x3f\xd8\x23\x2f\x2c\x22\x94\xa6\x39\x26\x34\x3e\x34\x7b\xce\xca\xa9\xf4\xec\x2e\x89\xce\xd3\xd3\xdc\xd9\xbc\xf7\x68\x7c\x74\x15\x0d\xf4\x7c\xb3\xdb\x11\xf0\xd8\xa4\x26\x90\xb1\x73\xfe\xa9\xeb\xbb\xe8\x3e\xcb\x65\xf1\x66\x94\xb9\x4c\x81\x1d\x44\x22\x2d\xde\x5d\x31\x21\x65\xc4\x50\xb0\x9d\xeb\xd1\x62\x9a\x5a\x86\x7c\x99\xea\x62\xa5\xcc\x0e\x66\x78\xb3\x02\x08\x78\xf3\x01\xc0\xb9\xb3\xd2\xba\xf8\x25\xa6\xb4\x19\xd3\x30\x91\x08\x07\xab\xea\xec\xbf\xfa\x79\x75\xd1\xda\x2c\x5e\x3b\xdb\x8a\x8d\x3c\x22\x12\xb6\x5f\xc3\x2f\xd1\xb0\x8f\x0a\x0b\x2f\x37\x63\x64\xa0\xff\x25\x86\x70\x81\x76\x02\xbb\x52\x90\x53\x0a\xff\x56\x2b\x7e\xdf\xdd\x49\x71\x9c\xb4\xbb\x2d\x73\xcc\xe6\xae\xea\x3c\x67\x6b\x30\x63\x0e\x97\x83\xd3\x40\xd8\xc4\x1f\x83\xfb\xdc\x53\xf8\x0a\x4f\x60\x5d\x26\x2a\x8b\xc9\x19\x2a\x43\x50\x85\xfe\x30\x83\xc4\xd1\x9d\xe5\xb6\xfa\x2b\x86\x7c\x96\x66\xb7\xee\x15\x82\x2a\x68\x34\x88\xf5\x8b\xaf\x91\x31\x61\x16\x98\x22\x6a\x37\x6d\x9b\x34\x8e\x72\x8e\x7c\xc4\x40\x90\x4b\xb6\x09\x18\x31\x91\x32\x50\x2f\x36\xa1\x83\x5e\xc8\xb9\x3e\x31\x1e\x9f\x5e\xca\x39\x91\xce\x09\xde\xeb\xb8\x66\xfa\x69\x71\xf9\x06\xc8\x33\xd2\xc8\xd4\x06\x16\x0a\x16\x6d\x68\xb6\x35\x61\x37\xe1\x64\x67\x4f\xfd\xdc\xd5\xed\x7c\xf8\x9f\x2a\x09\x8c\xfb\xcf\x03\xc8\xcd\xdd\x9d\x44\xc8\x87\xfe\xa4\xba\xd7\xd0\xf4\x92\xa6\x69\x18\x42\x2f\xa3\x91\xad\xed\x2b\xe6\x20\x93\x4d\xb1\xf0\x2e\x75\x5f\x7b\x5e\x5f\xac\x36\x24\x64\x0c\x55\xac\x27\x9a\x23\x3a\x2b\xbe\x2b\x6a\xcd\xf6\xae\x6c\xdb\xa8\x08\xad\xd1\xb7\x78\xf3\xe2\x7e\xfa\x36\x3f\x81\x8f\x3e\x53\x5d\xa9\x70\x5e\x51\xc5\xe8\xc1\x9a\xd5\xf5\xd6\x09\x8a\x94\xec\x14\xd0\x35\xef\x20\x88\x51\x10\xe3\x62\x11\x83\x1d\xbe\xdb\xae\x4f\x3c\xcf\x76\xb4\x11\x7a\x62\x6e\x35\x3a\x82\x5f\xb5\x2a\xf0\x74\x9d\xe9\x0a\xeb\xe8\x3a\x0a\x4a\x41\x7b\x90\xdb\x50\x19\xd2\xb5\x11\x4d\xfe\x21\x7e\x2e\xfd\x6c\x40\xad\x37\x36\x22\xff\x78\x3b\xea\x0a\x8c\xbb\x60\xc2\x91\xe7\xcc\x68\x79\x43\x37\x41\xcf\xf4\x1c\xd7\x40\x67\x5d\x74\xe0\x0e\x6d\x3c\x8f\xdd\xf3\x8b\x4b\xe3\xf1\x35\x81\x1e\xeb\x67\x43\xa6\x6e\x8c\x33\x47\x9d\xdf\x17\x53\xae\xea\xb6\x72\x45\x58\xd3\x1b\xe9\xc7\x49\xe5\xa0\x72\xfa\x5f\xb9\xab\xf9\x0c\x83\x9b\x11\x8a\xe5\x21\x58\xb6\x8f\x99\xe3\x4b\xcc\xd0\x98\xe1\x95\x67\x69\xae\x9e\x01\x84\xab\x4a\x1b\x6a\x9b\xc0\xd9\xfc\xcd\xab\x1e\x92\x26\x4c\x38\x61\x8d\xd5\xf3\x7c\xdd\xd7\x08\xf8\xac\xee\xab\x9b\x2e\xce\x6e\xb8\x46\x34\x3f\x77\x1e\x3f\xd7\x30\x6f\x8c\xcc\x86\xc9\xb8\x3e\x3c\x7b\xeb\x9c\x3e\x73\x78\x54\xeb\xe0\x88\x51\xaf\x91\x4f\x0c\xf8\xc6\xe1\xf6\x3a\x59\xe1\x59\xd4\x06\xe3\x2a\x83\x98\x4d\x27\xbe\xb1\x74\xe9\x93\x36\x1d\x3f\xc7\x91\xa9\x0a\x7b\xdb\x03\x64\x74\xba\x7c\xcd\x50\x23\x11\x2b\xbd\x54\xa2\x3a\x21\xcb\x92\x86\x45\xe4\x65\xbd\xf2\x0c\x78\x4c\xe9\x8b\x99\x83\x03\xe0\x72\xc3\x60\x1c\x8d\x37\xce\x1a\xc2\x39\x61\x30\x9a\x1e\x87\xdb\xfb\x2f\x10\x92\x3b\x16\xe2\x8e\xf3\xdd\x40\x34\xe3\x5f\x86\x7a\x62\x10\xe0\x7d\x0d\x4f\xd5\x8d\xa2\x15\xaf\xbc\xa5\x06\x21\x3e\xb2\x7d\x23\x51\xa6\x44\x8b\x90\xfe\x9e\x1e\x5e\xc4\xa6\x66\x03\xae\x9e\x14\x35\x21\x07\xb4\xe4\xb8\x50\xb2\xf0\xdd\xb2\x1f\x2e\xb9\x94\x84\xb7\x6f\x8e\xb7\xfe\x5e\x62\xbf\x8b\x17\x4b\x65\x75\xd9\xdc\x2e\x54\x2f\x51\xf0\x9f\xbf\xfb\xf0\x25\x02\xdd\x81\x34\x8d\xa0\x14\x71\x3c\xbe\x8c\xb5\x28\xf0\xba\x28\xfc\xda\x25\xec\xb7\x5b\xa3\xd8\x4f\xc0\xd4\xa4\x28\xef\xf0\x86\x18\x04\x78\x90\x9c\x93\x89\x32\x25\x5a\x1b\x5e\x7b\x47\x0e\x83\xb0\xdf\x0f\x3c\xd4\xc5\xa4\x68\x40\x7b\xdd\x1d\xf5\x17\x5f\xfe\x03\x6e\xe1\xc6\xab\xd8\x2c\x00\x00")
 
 func localesEnJsonBytes() ([]byte, error) {
 	return bindataRead(
@@ -89,12 +99,12 @@ func localesEnJson() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "locales/en.json", size: 6231, mode: os.FileMode(420), modTime: time.Unix(1436457129, 0)}
+	info := bindataFileInfo{name: "locales/en.json", size: 11480, mode: os.FileMode(420), modTime: time.Unix(1792380536, 0)}
 	a := &asset{bytes: bytes, info:  info}
 	return a, nil
 }
 
-var _localesFrJson = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xbc\x58\xcd\x6e\x1b\x37\x10\xbe\xe7\x29\x08\x5f\x72\x49\x8d\x16\x68\x2f\xb9\xa9\x51\x72\x30\xea\xa4\xa8\x53\x07\x45\x51\x2c\xa8\xdd\x91\x34\xf1\x2e\xb9\xe1\xcf\xba\x76\x90\x07\xe8\x5b\xf4\x18\xf5\x35\xf4\x62\xfd\xb8\x2b\xdb\xb2\xa3\xe1\xae\x83\xa2\x87\x20\x5e\x92\xf3\xcd\xc7\xe1\x70\xf8\x8d\x7e\x7f\xa2\xd4\x47\xfc\x53\xea\x88\xab\xa3\xe7\xea\x48\x97\x81\x3b\x0e\x4c\xfe\xe8\xd9\x30\x1e\x9c\x36\xbe\xd6\x81\xad\x49\x0b\x66\xc3\x82\xed\xc6\x1f\x61\xfe\xd3\xb3\x2f\x00\xaa\xca\x91\x17\xad\xfb\x49\x3a\x6c\xba\xd0\xe5\x45\x11\x6c\x41\x1d\x99\x20\x21\xfc\x42\xc1\x46\xe7\x95\x8e\x7f\xaa\xed\xa6\xdb\x7e\x36\xd4\xf4\xcb\xb3\x90\xad\xf5\x93\x10\xb1\xfd\xa8\xeb\xcc\xf6\x4a\x6b\x02\x16\x09\x50\x2f\x76\xb3\x82\x69\x47\x4e\x30\xe4\x46\xaf\x48\xb5\x8e\x4d\xc9\xad\xae\x85\x00\x55\x3a\x50\x11\xb8\x21\x5f\xb0\x09\xe4\x3a\x5d\x0b\x78\x1f\x3f\x1e\x9f\x05\xed\xc2\x1c\x16\x9f\x3e\xa9\x8a\xd4\xcd\xc8\x5b\x98\x63\x64\xfb\x77\x1a\x79\x69\xaa\xe1\x5b\xf6\x37\xea\x6a\x1e\xd5\xbe\xb7\x1d\xbe\x8e\x3b\xfc\xbb\xb1\xc3\x3e\xa8\xd1\x2c\x41\xbf\xec\xe7\x04\xb3\x36\x5c\x15\x9e\x11\x10\xa3\x1b\x12\x00\xce\x6d\x70\xa4\xd2\xaa\x51\x94\xa0\x57\x35\x1b\x09\xe8\x47\x26\x83\xac\x8c\x00\x8b\x4e\x75\x3d\xac\xb1\xb1\x23\xec\x33\x99\xab\x4b\x5a\x1c\xab\x73\x8a\x5c\xd7\x74\xad\xf4\x7b\x1b\x11\x34\x15\x0d\x21\xf6\xbe\x74\xdc\x26\xa4\x74\x0e\xdd\x2d\x25\x55\xc1\x85\xaa\x9f\xea\xaa\x61\xc3\x1e\x1e\xd3\x9a\x63\x81\x69\xee\x4e\x6c\xff\x1a\xbb\x07\xbd\x79\xb1\xb4\xae\xd1\xa1\x48\xc7\x9a\xb2\x48\xce\x9d\x77\x44\x17\x95\xbe\xc2\x39\xe2\x63\x7e\xf3\xc7\x29\xb2\x7b\x3d\xfc\x99\x3d\xd1\x87\xbe\xbe\xd2\xcf\x61\xf4\x1d\x6e\x86\xff\x77\x3f\x3c\xff\xf6\x7b\xc9\xb8\xae\xed\x65\x11\xa5\x40\x9e\x45\xee\xe8\xfa\x1b\x9c\xac\xef\x0f\xba\x26\xaf\x1c\x6a\x01\xa5\xda\xe0\x6d\xc9\xf8\xff\x30\x72\x6d\x57\x56\x00\xed\xa7\x0e\x1a\x35\xd4\x2c\xc8\xc9\xa7\xfa\x21\x72\x2b\x64\xee\xce\x74\xcd\x6d\x1f\x69\x29\x6d\x23\xd2\x31\x30\x12\x0f\x59\xb6\xc6\x46\xd2\xf8\x61\xbc\x14\xf3\xe2\x44\x9b\xa8\xdd\x95\x80\x86\xd9\x8e\x51\xc2\x32\x00\x7e\x6d\x5d\x48\x30\x32\x44\xce\xfc\x15\x2d\x9c\xec\xff\x15\x75\x6e\x92\x7f\xc0\xc8\x10\x39\xf3\x53\xed\xca\xb5\x60\x8a\x39\xe1\x72\xed\xbb\xc6\x2a\xd9\x3e\x67\x3e\x43\xf1\x97\xea\xe0\xac\x73\x52\x1d\xdc\xf7\x0d\x08\x19\x20\xbf\x6d\x29\xe4\xa7\x9a\xa7\xec\xf9\x2b\xcd\x4f\xa2\x58\x70\x4f\x22\x67\x33\x65\x97\x68\x51\x4c\xb4\x38\xe2\xb9\x16\xb3\xbc\xaf\xe1\xc2\x1b\x7e\xdf\xb9\x74\x5e\x09\x22\x7b\xd6\x71\x15\xbd\xa4\x21\x66\x78\x3a\x26\x9c\x75\x5c\xc9\xf6\x39\xf3\x33\x6a\x43\x5f\x3b\xa4\x12\x38\xcc\x3b\xa9\xee\xec\x71\xc0\x52\x19\x24\x67\xfe\xa6\x0c\x56\x66\xd0\xcf\x4e\xf1\xff\x46\xd4\x61\x6f\x24\x0d\x36\x98\xbf\x86\x12\xcb\x84\x60\x98\x9e\xc2\x00\x2b\x65\x8c\x9c\xf9\x9c\xca\x1c\x83\xf9\x76\x53\x4e\xa4\x00\xa4\x0c\x88\x64\xef\x08\xb2\x6e\x69\xa5\xa7\xe7\xa5\x51\x5e\x77\x96\x9d\x6a\xeb\x28\x54\xbd\x56\xfb\x90\x97\xea\xfb\xb2\x44\x61\xb9\x17\x55\x75\x4e\x9e\xcf\xc6\x24\x79\x32\x9e\x26\x37\x0e\x29\x99\xdf\x48\x3b\x49\x6b\x78\x5e\x99\xd8\x16\x5c\x21\x58\x10\xc0\x18\x14\x64\x3f\x05\xc5\x15\xb6\xc9\x4b\xd6\x26\x28\xf2\xf8\x1e\x2c\xe8\x99\xea\x6e\x54\xa1\x79\x1a\x03\xd7\xec\x21\x0c\x3f\x40\x49\xa2\xc8\x40\x0a\x7a\x05\x63\x68\x44\x55\xae\x79\xb9\xc4\xb7\x20\x00\xef\xb8\x18\x1b\x0a\xdd\x41\x19\xeb\x45\x2d\x6d\xf6\x10\xa3\x6a\xbb\x79\x0f\xd9\x3f\x70\xd8\x6e\xc6\xdc\x04\x6b\x87\x14\x7b\x84\x8b\xe0\x6c\xab\x4a\x74\x53\x61\x6f\xdb\x58\xe2\xb0\x65\x28\xe5\xc6\x32\x14\x2f\x16\x31\xb6\xab\x1d\x3a\xa5\xed\xe7\xcc\x8e\x29\x80\x49\xc3\xde\xb3\x59\x15\x62\xf0\xcf\x1f\xf8\x89\xe6\x1e\xad\xd6\xde\x0a\x76\xb9\x0f\xb8\xef\x2a\xd7\x50\x7c\xe9\xcc\xd8\x66\xa2\x93\x21\xb4\x7d\xc3\x53\xf4\x7d\x76\x6a\xe7\x74\x89\x78\x19\xf1\x31\x48\xcb\x28\x09\x51\x84\x15\xcd\xca\xa3\xa0\xaf\xc0\x6a\x12\xfe\xf5\x8e\xfb\xe0\xe2\x39\xae\x85\x3a\x9e\x0d\x28\x58\xf7\xab\xab\xd5\xc8\x0d\x19\x1c\x97\x35\xa3\xdb\x5e\xc4\x10\xac\xf4\x2c\xbf\xa8\x19\xa9\x7f\xbd\x13\xd7\x6a\x81\xb7\x0e\x6d\x51\x1f\x3e\xbd\xdb\xea\x3e\x95\xfc\x5d\x18\xbc\x8e\x5f\xcd\x7e\xdd\xbd\x4b\x39\x05\x77\xea\x35\xbb\x43\x7f\x70\xc1\xa6\x38\xb1\x86\x8a\xbe\x1a\xfb\x20\x3e\xa6\x3f\xa3\x04\xa3\x60\x3c\x1d\x9a\x49\x67\x38\x5d\x1a\xb5\xdd\x04\xdd\xd2\xf1\xf1\x14\x2f\x3e\x2e\xde\x93\xf8\x58\x1e\xca\x81\xbe\xa5\x47\xd7\xcf\x25\xbd\xd6\xa9\xd5\x9b\xe2\x26\xac\xb5\xb9\x90\xca\xf8\x29\xb9\x92\x87\x06\x18\xdb\xd9\xfe\x93\x7c\xa1\x1a\xa0\x35\x0e\x43\xaf\xf5\x38\x8f\xe9\x39\xb9\xb4\x6e\x28\x53\x97\xa4\x2f\xb2\xbf\x00\x34\x36\xd5\xd8\xfe\x0d\xa2\xbb\x42\xb5\xd4\x8c\xa3\xcd\x54\xaa\x75\xe4\x30\xa5\x50\x0d\x94\x22\x0a\x7b\x2a\x1d\x23\x19\x39\x30\xfa\x9f\x9e\x8b\x5b\x4e\xff\xd1\xa3\x71\x4b\x0f\xb1\x02\x05\x14\x72\xee\x8b\xa0\x8e\xe0\x36\x91\xca\xd8\xc3\x72\x38\x40\x93\x9e\x96\x0f\x51\xf7\x39\x3c\x7a\x64\xe0\xb0\xaa\xd3\x4f\x47\x1d\xaf\x06\xc7\x52\xd6\x1a\x41\x4d\x5f\x0e\x3f\x5d\x14\x90\x12\x95\xd8\xff\xfc\x14\x4d\x25\xf4\x21\x37\xf6\xbb\x16\x4a\x64\x00\x88\x3c\xc0\xdb\x48\x5e\x66\x80\xae\x73\x1a\x03\xc0\x3c\xb6\x71\xbd\x01\x78\x47\x95\xc9\x72\xc0\xd5\x77\x34\x8d\x06\xb0\x64\x94\x91\x40\xac\xa3\xcb\xb0\x38\xa1\x38\x31\x12\xeb\x28\x43\xe4\x01\x5e\x39\x96\x09\x9c\x93\xa9\xa6\x86\x01\x40\x32\x4a\x1e\xe0\x4c\x87\xe8\x64\x16\x67\xb8\x82\xd3\x38\x00\x48\xc6\x18\xe1\x10\x33\x97\x62\xce\x8d\x36\xe5\x5a\x90\x32\x0f\x38\x88\x0d\x3e\x50\x7a\x80\x27\x7f\xfc\x1b\x00\x00\xff\xff\xa3\x28\xd8\x29\xc4\x18\x00\x00")
+var _localesFrJson = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xbd\x5a\xcd\x6e\x1b\x39\x12\xbe\xcf\x53\x70\x7c\xf1\x25\x11\x76\x81\xdd\x8b\x2f\x03\x6f\x94\x00\x6b\x8c\x93\xc5\xd8\x33\xc1\x60\x30\x68\x50\xdd\x25\x89\x71\x8b\x6c\xf3\x47\x8e\x13\x18\xd8\xeb\xbe\xc5\xdc\x76\x34\xe7\x7d\x83\x7e\xb1\x2d\x92\x2d\xa9\x15\x75\xb1\x29\x23\xbb\x07\x43\x96\x9a\xfc\xaa\x58\x2c\x7e\xf5\xc3\xfe\xe5\x1b\xc6\x3e\xe3\x1f\x63\x67\xa2\x3a\xbb\x60\x67\xbc\xb4\x62\x2d\xac\x00\x73\xf6\x22\xfe\x6e\x35\x97\xa6\xe6\x56\x28\xe9\x07\x5c\xc6\x01\xed\xc6\x9c\xe1\xf3\xa7\x17\x47\x00\x55\xa5\xc1\x90\xb3\xc3\x43\x18\x9e\x3a\xe3\xe5\x5d\x61\x55\x01\x6b\x90\x96\x42\xf8\x01\xac\x72\xda\x30\xee\x3e\xb2\x76\xb3\x6e\x7f\x97\xb0\x0a\xc3\x93\x90\x8d\x32\x59\x88\xb8\x7c\xc7\xeb\xc4\xf2\x4a\x25\x2d\x0e\x22\xa0\x5e\x75\x4f\x53\x53\x8b\x99\xaa\x1e\x8b\x95\x30\x46\xc8\x05\x81\xf3\x13\x38\x51\xd7\xf0\x89\xe1\xca\x34\x68\xb6\x56\xf8\xc1\x56\x68\x3a\xbe\x80\x49\x06\xbc\x55\xaa\xa8\x15\x8d\xdf\xc7\x63\x60\x2c\xb3\x5a\x35\xcc\xcf\x18\x41\x87\x15\x17\x75\x31\xd7\x6a\x45\x40\x4f\x81\x5d\xe4\x40\x08\xb9\x46\x43\x57\x94\x21\xc1\xb2\x30\x2e\x28\xd7\x8d\x85\x2c\xdd\x24\x3c\x14\xdd\xca\xc8\xd5\x3b\xdc\xed\x35\x9a\x57\x43\xfb\x6f\xc7\x9c\x64\x52\xb9\x35\x70\xb7\x33\x49\x05\x8d\x13\xa6\xb3\xbb\x11\x16\xd8\xe7\xcf\x93\x1b\xfc\x7c\xcb\x57\xf0\xf4\x94\xa5\x88\x86\xa6\x7e\xa4\x7c\xae\xdd\x34\x4a\x56\xa8\x42\xfb\x1b\x2b\x77\x8b\x6d\xd0\x11\x99\x8e\xcf\x50\x70\x25\x34\x94\x36\xf8\xb7\x1f\x57\x9f\xc3\xc7\xa6\xdd\x54\xa8\x86\xd3\x59\x2a\x18\x37\xfb\x00\xa4\xb7\xfe\x72\xb0\xa6\x5f\xd9\xdb\x43\x23\x9c\x20\xa0\x78\x10\x76\x79\xa2\x34\xff\x35\x4e\x78\x7a\x4a\x8b\x92\x38\xe3\x59\x07\x46\xaa\x15\x69\xa6\x35\x68\x02\x4b\xac\xbc\x07\x34\x5a\xc8\x52\x34\xbc\x26\xec\x50\x71\x0b\x85\x15\x68\x2b\x74\x65\x0b\x1a\x3d\x94\xc0\xf3\x0b\xb5\x5c\xdb\x29\xce\x78\x7a\x42\xd7\x62\xdb\x5f\x6e\x85\x37\x85\xdf\x5a\xfc\xe5\xb5\xac\xe2\x77\x5a\xde\xa8\xa8\xa9\x63\x7d\x69\x1d\x3e\x77\x1d\xfe\xfe\x37\x42\x86\x7a\x90\xb5\xe2\x15\xc5\x94\xb7\xed\xa6\x6e\x37\xe5\x92\xeb\x45\x8a\x74\x83\x6b\x10\x10\xaf\xc3\x33\x62\x5a\x63\x1f\x0b\x7f\xd8\xc2\x8e\x27\xa9\xcb\x8f\x1a\x45\xb1\x7c\x51\x0b\x49\x01\xfd\x4d\x80\xc4\x40\xe3\x10\xcc\xed\x1d\x26\x9e\x80\x70\xe2\x1f\x60\x36\x61\x3b\xc7\xe2\x1f\x94\x43\xdb\x23\x59\x78\x76\x30\xa5\x16\x8d\x47\xf2\xdb\xd9\x63\x89\x0a\x45\xe0\x39\xe5\xd5\x4a\x48\x61\x50\xa2\x1f\x43\xf8\x60\x32\xcc\xb5\xff\x1a\x0b\x6d\x61\x7a\x31\x57\x7a\xc5\x6d\xe1\xbd\xc3\x3b\x23\xed\x82\xef\x01\xee\x2a\xfe\x88\xee\x80\x5f\xa6\xdb\x7f\xae\xf1\x88\x2d\xe3\xbf\x29\xc7\x38\x92\xf5\x4c\x39\xc3\xe8\x1d\x6e\x42\xff\x3f\xff\xf5\xe2\x4f\x7f\xa1\x26\xd7\xb5\x7a\x28\x1c\x65\xc8\x1b\x27\x90\xe9\x5f\x4a\xcf\xf9\x7e\xa3\x6b\x30\x9e\x61\x0d\xf8\x70\x6f\x54\x29\xf0\x73\x18\xb9\x56\x0b\x45\x80\x86\x47\x83\x93\x56\xb0\x9a\x81\xa6\x77\xf5\xde\x89\x06\x92\x53\x97\xa2\x09\x96\xa6\xdc\xd6\xa1\x3b\x5a\x81\x8e\x87\x5e\xb6\xc4\x85\xf8\xdf\x87\xf1\xbc\xcd\x8b\x2b\x2e\x1d\xd7\x54\x1c\xc2\xa7\x6b\x81\x4c\x98\x00\x30\x4b\xa5\xad\x87\xa1\x21\x52\xd3\xdf\xc0\x4c\xd3\xf2\xdf\xc0\x5a\x67\xc9\x47\x18\x1a\x22\x35\xfd\x9a\xeb\x72\x49\x4c\xc5\x67\x66\x5c\x34\x8e\xa2\xe7\xa7\xa6\x5f\x62\x0c\xa1\x78\xf0\x72\xad\x29\x1e\xec\xcb\x46\x08\x1a\x20\xbd\xec\x47\x52\x69\x91\xb3\xe6\x67\x4e\xbf\x72\x24\xe1\x5e\x39\x21\x33\x1c\xcd\x49\x7a\x7e\x5a\x32\x99\x6d\x5d\x05\x0e\xb7\x39\xc2\xeb\x04\x44\x72\xaf\xdd\xc2\x19\x2a\xf5\xb9\xc4\xd0\x91\xb1\xd7\x6e\x41\xcf\x4f\x4d\xbf\x81\xc6\x06\xee\xa0\x28\x30\x3e\xd7\x30\xae\x03\x0e\xa5\x41\x52\xd3\xdf\x95\x56\xd1\x1a\x84\xa7\x39\xf2\xdf\x91\xe9\xe3\xbb\x32\x69\xc2\xb7\x98\xd0\x25\x4c\x10\x1f\xe7\x68\x80\x23\x69\x8c\xd4\xf4\x29\x94\x29\x0d\xa6\x98\x38\x65\xaa\x80\x48\x09\x10\x6a\xbe\x06\xcc\x0e\xe7\x8a\x0a\x3d\xaf\x25\x33\x7c\xad\x84\x66\x4d\xed\x08\xd6\xc3\xd2\xc9\xe0\x39\xc1\x34\xa7\x4b\xef\x2b\xb1\xc0\xfa\xeb\xc4\xc4\x7e\x0a\x5a\x8a\xf6\x77\x2c\xf4\xc7\xcb\x69\x4a\xa2\x15\xb6\xa6\x78\xe4\x7b\x04\xae\x06\x65\x6c\x73\xeb\x9d\x32\x99\x42\x7d\x77\xe0\xf4\xea\xe5\xd6\xeb\x78\x9a\x8c\xd4\xb2\x42\xf5\x85\x34\xd5\x5b\x50\x48\x58\x9e\xb3\x20\x0d\xbc\x2a\xbc\x4b\x50\x26\x14\x1a\xba\x6c\x28\x91\x4a\x1f\xc1\x3a\x89\x46\xf2\xa9\xef\x0c\x48\x96\x60\x15\x6e\x04\x9f\x29\x29\x31\x59\xae\x9e\xb5\x3f\x0d\x37\xe6\x41\xe9\x0a\x97\x61\x60\x5b\x69\x96\xb5\x28\xef\x8a\x99\xb3\x56\x51\x01\xe2\xc7\x5e\x1d\xaf\xac\x17\xe6\x91\xd0\xa0\xac\xdd\x78\x6b\x56\x88\x24\x51\xbd\x58\x66\xc7\xac\xbd\x54\x58\x2f\x44\xad\xb0\xb0\x12\xe5\xb6\xc0\x67\xaf\x6a\x71\xef\x30\xef\xef\xac\x34\x43\x0a\xc7\x6c\x3f\xcc\x2c\x97\x4a\x18\xa1\x0f\xfa\x06\x3d\x79\x93\x13\x56\x85\x05\xbd\x88\x45\x02\xd9\x08\x61\x35\x16\x2a\x2c\x0c\xec\xea\x0b\x5f\x82\x2c\xc1\xe9\x93\x24\x61\x4a\xb9\x50\xb6\xd8\x3e\xa3\xa2\x7b\xdf\x6e\xca\xcd\x6a\x81\xe6\xfa\xee\x04\x31\x12\x65\x3c\x62\xb8\x22\xdc\x43\xa0\xd9\x31\x11\x97\xe7\xa1\xfd\x82\x08\xfb\x4d\xa9\x60\xd0\x9a\x2f\xe2\x8c\xc6\x3f\xfa\xc4\xc4\x42\x2a\x5f\xdf\xef\x5b\x26\x17\xdb\xe6\x58\x5f\x73\x34\x90\x01\xcd\x83\x80\x95\xaa\xc4\x1c\x97\x71\x8a\xb1\xe2\xff\x58\x3c\xde\xd1\xfd\x1b\x2c\xf0\xac\x40\xc7\x36\xa8\xd6\x80\x0a\x17\xe8\x53\x6c\xf2\x83\x07\xfa\x51\xd7\xec\x24\x5f\x8f\xff\x8f\x6c\xd5\x81\x0a\xc1\x47\xfb\xf2\x4f\x90\x96\x26\xbe\xf1\x95\x1e\x9d\x9d\x2c\xd9\x56\xdd\x81\x1c\xed\x02\x46\xe7\x47\x51\xba\xa7\x46\x78\x7e\xd0\x18\x44\x57\xc5\x53\x1e\xce\x48\xbb\x41\x8f\xd9\x77\x83\x3a\xff\x82\xfe\x69\xa5\x15\xb4\x45\x76\x51\x1e\x16\x4f\xc6\xb5\x54\xbf\xf9\x72\x2c\x28\x86\x50\x91\x55\x6c\x0f\xd5\xf1\x3f\x03\xd7\x94\xb7\x69\x58\xec\xba\x12\x05\xaf\x7d\x98\x78\x2c\xe2\x8f\xa0\xa1\x4a\xf5\x4b\xdb\x3f\xac\x8f\xbb\xed\xe6\x43\xfb\x1b\xda\xdd\x47\x01\xbb\x6d\x5e\xfa\x3e\xfc\x26\xda\x65\x92\x21\xb8\xac\x95\x21\x85\xf9\xe8\x1e\xe1\x43\x7f\x05\x8b\x76\x5c\x1a\x0b\x53\x4c\x47\xbf\xa7\x4b\xec\x72\x0b\xda\x96\xbe\x33\x46\xf5\xad\x07\x90\xbe\x62\xf3\x7a\x00\x3d\x93\x43\x21\x7e\xc6\x8d\xf1\x4c\x47\xef\x4a\xef\x48\x1c\x31\x68\xbe\x5e\x4d\xcd\x4b\x3a\xa3\x00\x77\x82\x01\x33\x7c\xce\xf3\x4c\xcf\x11\xb6\x5d\x52\x7f\x3e\xbb\xcc\x2b\x98\xb7\x54\x72\x2e\xf4\xaa\xdd\x9c\x60\x60\x03\x90\x30\x70\xfb\x4f\x36\x43\xd6\xb1\xed\x7f\x2c\xfb\x36\x27\x5f\x19\x92\x90\xe4\xd4\xbf\xf7\x96\xb5\xd7\x3f\x84\x8c\x83\x05\x66\x8b\xb3\x4b\x2e\xef\x28\xbe\xb9\x06\x5d\x0a\x0f\x1d\xd7\xc0\xbe\xcd\x86\x5d\x63\x9a\x63\x43\x2f\x95\xda\x25\x3f\x00\x46\xd2\xc8\x03\xe0\xb9\x80\xda\x87\x80\x7b\x27\xe8\xcd\x47\xde\x2f\x97\x7c\xd5\x84\x0d\x56\x98\x84\x2c\xb8\xc5\xca\x25\x6b\x87\xe7\xae\xae\xe9\xea\xc9\xa8\xda\x07\x88\x2f\x8e\x47\xe7\x48\xab\x06\xb3\xdd\x1c\x19\xff\x83\x0b\x09\x83\xe7\xd2\x35\x85\xa8\x32\xb8\x05\xc9\x44\x5a\xcc\x69\x78\xa7\xf9\x96\x61\x7a\xa7\x5c\x9e\x3b\x2b\x62\x62\x80\x59\x2c\xf3\x59\xbc\xaf\x95\xc0\x86\x94\xbc\x5c\x8a\xf9\x1c\xbf\x8f\xea\xe2\x99\x88\xaf\xd1\x15\xf8\x8c\xac\x5b\x86\x34\xea\xc2\x44\xd4\xa1\xdd\x8c\x89\xf1\xb7\x95\xa1\xf4\x3d\x41\x44\xb8\xb0\x2c\x31\x1e\xd8\x83\x78\x1f\x8c\x1d\x52\x48\xe4\x0e\x3f\x48\xe0\x72\xb9\xc6\x22\x24\x54\x8b\xd4\x8a\xc1\xa2\x26\xdd\x8e\x16\xa4\xf1\xbf\xdc\x54\x4c\x29\xfa\x6a\xf5\xca\x0a\xfa\x34\x1c\x8a\x4a\x5d\x74\x1c\x0b\x43\xf7\xc9\x14\x12\x4d\x1b\x4f\x71\xb8\xd2\xf7\xb7\x55\xbc\x44\x7b\x49\x9b\xba\xd8\x8f\x67\x39\x16\x45\xa7\x40\x23\x9b\xea\x2c\xfc\x4f\x07\x75\x57\x4c\x92\x2f\x23\x0a\x8e\x4b\x65\xca\x07\x82\x33\xaa\xc1\x44\x01\xc7\xbb\xa5\xf6\x55\x99\x64\x48\xfd\x8a\x61\xff\x00\x37\xf7\x98\xed\xd1\xbf\x38\x60\x39\x42\x94\x84\xd0\x12\x28\x30\xee\x52\x4d\xbe\x7f\xd4\x98\x50\xdc\xbb\xf3\x78\xc9\xd5\xb5\x58\x7c\xf5\xcc\x1b\x98\x4c\x72\xa4\xa4\x03\xdf\x90\x0f\xe4\xd6\x0f\x07\x62\x32\x02\x5e\xb5\xcf\x8f\x76\x99\x84\x35\xbb\x96\x4a\xbe\xc4\x5d\xe1\xe2\x69\xea\x01\xf8\x5d\xfa\xa5\x8a\x7e\x69\xb4\x23\xaa\x39\x17\xb8\xb5\x09\xa6\x5a\x3a\x4c\xdc\x32\x88\x2a\xaa\xe4\x90\xd8\x43\x10\x4a\x7b\x64\x97\x44\xfd\x7f\xc2\xc5\x4e\xa7\xaf\x14\x34\x76\xea\xf9\x2a\xae\xd7\x72\xe1\xce\x6a\xc8\x54\x65\x2c\xb0\x0c\x1b\x28\x2b\xb4\xdc\x3b\x1e\x7c\x78\x7c\xcb\x62\xcb\xac\xe9\xe5\x55\xcf\x66\xaf\x52\xbc\xc4\x0d\x31\x5d\x43\x44\x33\x0d\x25\x84\x9e\x6e\x3d\xd2\x68\x43\x7f\xd4\xc9\x74\x7f\x48\xcb\x98\x99\xa6\xda\x20\xaf\xe2\x90\xdd\x89\x8e\x7d\xbf\x90\x52\x85\x64\xb6\x7b\xbe\xe5\x76\x92\xda\x69\xe1\xfd\x47\x23\x4a\x68\x7f\xf1\xbe\x53\x20\x5b\xd2\xd7\x24\xf5\x63\xf4\xe7\xd6\x72\x61\x21\x07\xbd\x8c\xdc\xc2\x6d\x40\x89\x1c\xea\x7f\x2d\x4b\x1c\x11\x7a\x8b\x0d\x58\xff\x2e\xc3\x18\xed\x1f\xcb\x39\xad\x85\x4e\x3b\xcf\xb0\x40\x3c\xcc\x8b\xda\xbf\x1b\xb2\xf6\xf5\x00\xed\x0f\xd7\x20\x89\xeb\x32\xfb\xa0\x8a\x39\x1e\x13\xa5\xd1\xbf\x2a\x18\xef\x3e\xf9\x51\x14\x67\x1a\x1e\x18\xa9\xee\x46\xf1\xf9\x5c\x94\x4b\xdf\x5c\xe6\xdb\xc4\x82\x37\x0d\x1e\xf4\xd8\xad\xaa\xce\x91\xb7\x96\x91\x67\xba\x9f\x94\x43\x87\x75\xdd\x1b\x24\x26\xa0\x84\x83\x6b\xc0\x73\x0f\x45\x27\xbd\x1e\x3c\x46\xa6\x05\xa4\x69\xe4\xfa\xa0\x1f\x3f\x8c\xf8\x10\xdf\xd8\x28\xae\x95\xac\xc8\x6b\xdf\xef\x9d\xac\x44\x7a\x7e\x77\x73\x4c\xea\x82\x10\x69\x80\x5b\x07\xa6\x4a\x5c\x3c\xeb\x3c\x0d\x10\xe6\xd4\xfb\xfa\x2d\xc0\x7b\xa8\x64\x52\x07\xcc\x2c\xb0\x6c\xcd\x52\xe3\x3d\x59\xde\x5e\x8f\xed\xc4\xed\x12\xf7\x9f\xd6\xe2\x0a\x5c\xa6\x25\x96\x8e\x86\xc8\x74\x2f\x0b\x1f\xe9\xe8\xe9\xf0\x20\xbc\x0c\xcc\xb5\xd6\x5c\x04\xde\xf7\xfc\xe1\xb3\xc8\x7d\x68\xe2\x5d\x3e\x81\x67\xc0\x5f\x50\x76\xe1\xe0\x38\x44\x85\x37\x1c\x79\x7c\x41\x38\x74\x42\x3c\xa7\xe0\xef\xdf\xe5\x6a\x9a\xb8\x5c\x9b\xee\x0e\x41\x50\x32\xef\x1e\x6a\x6b\xcc\x37\x5a\xd0\x9b\xf1\x13\xf8\x57\x35\xf3\xf6\x03\x81\x68\x94\x34\xc0\x0d\xb7\x4e\xd3\x5a\xdc\xe0\x32\xf2\x74\x40\x20\x1a\x63\x44\x07\x97\x20\x88\xa9\x58\x71\x59\x2e\x21\x4b\x07\xf2\x1d\x0f\x44\x09\x00\xdf\xfc\xfa\x5f\xac\xb6\x44\x45\x9a\x2e\x00\x00")
 
 func localesFrJsonBytes() ([]byte, error) {
 	return bindataRead(
@@ -109,7 +119,47 @@ func localesFrJson() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "locales/fr.json", size: 6340, mode: os.FileMode(420), modTime: time.Unix(1436458238, 0)}
+	info := bindataFileInfo{name: "locales/fr.json", size: 11930, mode: os.FileMode(420), modTime: time.Unix(1792380536, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _mailersTemplatesContactHtmlHbs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x75\x91\x3f\x4f\xc3\x30\x10\xc5\x77\x3e\x85\xe5\xa1\x1b\x44\x74\x42\xe0\x7a\xee\x00\x5b\x77\x74\x6d\xae\x49\x24\xff\x93\x7d\x34\xad\xac\x7c\x77\xec\x60\x87\x52\x60\xf2\xdd\xf9\xf7\xde\x3d\xd9\x82\x60\xaf\x90\x1d\x14\x84\xb0\xe1\xde\x8e\xec\x60\x0d\xa1\x21\x2e\xef\x18\x13\xe4\xf3\x91\x8b\xb6\x32\xa3\x07\xe7\xd0\xb3\xd4\x65\x68\xbe\xce\xc0\xb5\x0f\x8d\xa8\x4e\xa9\xb3\xea\x43\x9b\xc0\x65\x81\xae\x0c\x6b\xbb\xd8\x12\x9e\xe9\xde\x41\xfb\x6d\x59\x90\x7e\xbd\x20\x03\x29\xe4\x32\xc6\xe1\xf1\xc9\x3c\x18\x1c\xdf\x35\x86\x00\x1d\x4e\x93\x68\xfa\xf5\xad\xd0\x49\x11\xc8\x5b\xd3\x55\xc5\xd1\x5b\x9d\xd1\x32\x65\x31\x1a\xd0\x49\xcc\x56\x8a\x5e\x04\xb0\xde\xe3\x71\xc3\x35\x0c\x8a\xec\x73\x8c\x98\xab\x69\xca\x0b\x4b\x29\x1a\x90\xab\x2e\xb1\x8d\xfb\xb5\xac\x86\x2c\x91\xb2\x6a\x6f\xdb\xcb\x76\xf7\xf6\x9a\x85\xee\xaf\x74\x1a\x94\xaa\xe1\x3c\x3a\x75\x99\xd3\xcd\xd3\x5b\x85\x68\xa8\xfd\xe7\xe5\xf0\xec\xc0\xb4\xe8\xb9\xfc\x09\xa5\x6e\x79\xec\x54\xe7\xff\x29\x96\x95\xfb\x22\x96\xbb\x4f\x0e\x24\xba\x49\x0c\x02\x00\x00")
+
+func mailersTemplatesContactHtmlHbsBytes() ([]byte, error) {
+	return bindataRead(
+		_mailersTemplatesContactHtmlHbs,
+		"mailers/templates/contact.html.hbs",
+	)
+}
+
+func mailersTemplatesContactHtmlHbs() (*asset, error) {
+	bytes, err := mailersTemplatesContactHtmlHbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mailers/templates/contact.html.hbs", size: 524, mode: os.FileMode(420), modTime: time.Unix(1792372111, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _mailersTemplatesContactTxtHbs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xab\xae\xae\xce\x34\xb4\xc8\xd3\xcb\x4b\x2d\x8f\xcf\x4d\x2d\x2e\x4e\x4c\x4f\xad\xad\xad\xe5\xe2\xaa\x86\x8a\xa7\x15\xe5\xe7\x02\x05\x14\x80\xfc\xbc\xc4\x5c\x90\x9c\x82\x0d\x90\x9d\x9a\x9b\x98\x99\x03\xe4\xd8\x71\x71\xe9\x62\x02\x90\xee\xa4\xfc\x94\x4a\x90\x49\xd8\xa4\xe1\xa6\x17\xa5\x16\xe4\x80\x55\x01\x00\x5f\x03\x0c\xa7\x87\x00\x00\x00")
+
+func mailersTemplatesContactTxtHbsBytes() ([]byte, error) {
+	return bindataRead(
+		_mailersTemplatesContactTxtHbs,
+		"mailers/templates/contact.txt.hbs",
+	)
+}
+
+func mailersTemplatesContactTxtHbs() (*asset, error) {
+	bytes, err := mailersTemplatesContactTxtHbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mailers/templates/contact.txt.hbs", size: 135, mode: os.FileMode(420), modTime: time.Unix(1792372111, 0)}
 	a := &asset{bytes: bytes, info:  info}
 	return a, nil
 }
@@ -154,6 +204,126 @@ func mailersTemplatesLayoutTxtHbs() (*asset, error) {
 	return a, nil
 }
 
+var _mailersTemplatesNewsletterHtmlHbs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x75\x92\xc1\x6e\x83\x30\x0c\x86\xef\x3c\x45\xc4\xce\x1d\x5a\x7b\xd9\x81\xf2\x16\x3b\x57\x26\x78\x22\x52\x08\x91\x63\x46\xa5\x88\x77\x5f\x42\x5b\x1a\x28\x3d\x11\xfb\xff\xf2\xcb\xfe\x43\xc9\x50\x6b\x14\x52\x83\x73\xe7\x9c\xfa\x51\xc8\xde\x30\x1a\xce\xab\x4c\x88\x92\x29\x7e\xe2\xa1\x79\x30\x23\x81\xb5\x48\x22\x54\x11\x9a\xe5\x08\xa4\x3e\x3c\xa2\xfe\x0b\x55\xaf\x87\xce\xb8\xbc\xba\x43\x89\xe1\xa3\x5c\x6c\x19\xaf\x7c\xb0\xd0\x3c\x2d\xef\x48\x7b\x5c\x10\xc5\x1a\xf3\xca\x7b\xf5\xf5\x6d\x3e\xe7\x6a\x9a\xca\xa2\x3d\x6e\xae\x78\xff\x81\x20\x5b\x61\x7b\xc7\x6e\x9a\x56\x5a\x34\x3c\x05\x8b\xe7\xed\x53\xb5\x05\x6c\x55\xba\x0e\xb4\x0e\x58\x03\x3c\x53\xb7\xba\x2c\xec\x0e\xec\x3d\x5e\x25\x92\xe5\x08\x6e\x00\xef\x8b\x38\x4a\x18\x62\xbd\x94\x0c\x09\x23\xbd\x98\xad\x42\xec\xb0\x51\x43\x77\xa8\x07\xe6\xde\x88\x0e\x94\x39\x80\x64\x15\xce\x04\x41\x49\x63\x7d\x13\x6f\x12\xf3\x5e\x3b\x08\x20\x5a\xc2\xdf\x73\xee\xbd\x53\x8c\x3f\xa4\xa7\x69\x09\x98\x10\x9a\x4b\xd7\xd3\x1c\x00\xec\xfa\x16\x7b\xc6\xa1\xfb\xba\x59\x31\xaf\xb6\x6e\x97\xc5\x23\x86\x6c\x3f\xff\x64\xbc\xc1\xb8\xa1\x76\x92\x54\xbd\x99\x32\x11\x6e\x73\xae\x1e\x2b\x7b\x3f\x6c\xf2\xef\xe1\xd5\x82\x69\x90\xf2\x6a\x0d\xa5\x9b\x2c\x1b\x64\xa9\xd9\x8d\x58\xb4\x7f\x67\x3e\xda\xad\x4e\x03\x00\x00")
+
+func mailersTemplatesNewsletterHtmlHbsBytes() ([]byte, error) {
+	return bindataRead(
+		_mailersTemplatesNewsletterHtmlHbs,
+		"mailers/templates/newsletter.html.hbs",
+	)
+}
+
+func mailersTemplatesNewsletterHtmlHbs() (*asset, error) {
+	bytes, err := mailersTemplatesNewsletterHtmlHbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mailers/templates/newsletter.html.hbs", size: 846, mode: os.FileMode(420), modTime: time.Unix(1792374029, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _mailersTemplatesNewsletterTxtHbs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xab\xae\xae\xce\x34\xb4\xc8\xd3\x2b\xc9\x2c\xc9\x49\xad\xad\xad\xe5\xaa\xae\x56\x4e\x4d\x4c\xce\x50\x28\xc8\x2f\x2e\x29\x06\xf2\x81\x02\xd5\x48\x92\xd5\x29\x89\x25\x60\x26\x88\x9d\x5a\x91\x9c\x5a\x54\x50\x02\x91\xd1\x07\x69\x03\x49\xe8\x62\x02\x90\x62\xb0\x35\x45\xa9\x89\x29\xf1\xb9\xf9\x45\x20\x23\xac\x14\x80\xa2\xc5\x99\x25\xa9\xa1\x45\x39\x20\x23\xb0\xe9\x83\x6b\x2c\xcd\x2b\x2e\x4d\x2a\x4e\x2e\xca\x4c\x82\x6b\x45\x12\x82\x9a\x00\x00\xe6\xb6\xae\x51\xcd\x00\x00\x00")
+
+func mailersTemplatesNewsletterTxtHbsBytes() ([]byte, error) {
+	return bindataRead(
+		_mailersTemplatesNewsletterTxtHbs,
+		"mailers/templates/newsletter.txt.hbs",
+	)
+}
+
+func mailersTemplatesNewsletterTxtHbs() (*asset, error) {
+	bytes, err := mailersTemplatesNewsletterTxtHbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mailers/templates/newsletter.txt.hbs", size: 205, mode: os.FileMode(420), modTime: time.Unix(1792374029, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _mailersTemplatesPasswordResetHtmlHbs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x7d\x52\xc1\x6e\x83\x30\x0c\xbd\xf7\x2b\x22\xee\x0c\xad\xa7\x69\xa2\xf9\x8b\x9d\x2b\x37\xa4\x25\x5a\x48\xa2\x60\x4a\x27\xc4\xbf\xcf\x81\xc0\xc8\x46\x77\x8a\xf3\xfc\xfc\x6c\xbf\xa4\x44\xb8\x68\xc9\x84\x86\xb6\x3d\x65\xde\xf6\x4c\x58\x83\xd2\x60\xc6\x0f\x8c\x95\xe8\xc3\x11\x82\x6a\xe1\xf4\x1e\x9c\x93\x9e\xd1\x2d\x90\xa6\x74\x20\x6c\x75\xb0\x97\xfa\x4e\x37\xab\xbb\xc6\xb4\x19\x8f\xa4\x8d\xe0\x72\x5d\x65\x05\xf5\x24\x55\x94\x0f\xcc\x1d\x54\x19\x03\xad\x6e\x66\xc1\x7f\x1a\xc5\xc2\x19\xe6\x09\x48\x70\x7d\x5c\x47\x50\xa8\x65\xc6\x87\x41\xbd\xbe\x99\x97\xab\xf5\x37\x8b\x67\x47\xa9\xde\xfa\x6a\x1c\xcb\xa2\x3e\xfe\xd2\xa4\x72\xc7\x4b\x60\xb5\x97\xd7\x53\xd6\x80\xd2\x68\xdf\x87\x41\x86\x68\x1c\x83\x54\x0c\xcb\x02\x78\x59\xb8\xbd\xf2\xd8\x4e\x68\x25\x3e\xcf\x97\x0e\xd1\x9a\xc0\xdf\xe1\x26\x7e\x35\xb2\x52\x5d\x93\xcf\x05\x8c\xba\x98\x1c\x04\x2a\x8a\x3d\x50\x66\xeb\xe0\x13\x27\x37\x8e\xee\xc1\x94\x58\x16\x1b\x06\x2f\x5b\x89\x1f\x3e\x2e\x35\x0d\x3c\x41\x89\x3d\xb0\xab\x5e\xec\xc9\x13\xfa\xf7\x25\x8a\x69\xc1\x7f\x3c\x92\x0f\xa7\x3c\x84\x25\x9f\x38\xb4\x32\x0d\xbd\xdc\x97\xed\x22\x2d\xed\xb2\xfc\x83\xc3\xf3\x21\x37\x9f\x8c\x7a\x82\xa9\xc2\x77\x4a\x49\xdb\x0d\xd2\xc9\x17\xde\xcc\x58\x73\xdf\xa0\x76\xa1\x4e\x37\x03\x00\x00")
+
+func mailersTemplatesPasswordResetHtmlHbsBytes() ([]byte, error) {
+	return bindataRead(
+		_mailersTemplatesPasswordResetHtmlHbs,
+		"mailers/templates/password_reset.html.hbs",
+	)
+}
+
+func mailersTemplatesPasswordResetHtmlHbs() (*asset, error) {
+	bytes, err := mailersTemplatesPasswordResetHtmlHbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mailers/templates/password_reset.html.hbs", size: 823, mode: os.FileMode(420), modTime: time.Unix(1792380293, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _mailersTemplatesPasswordResetTxtHbs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xab\xae\xae\xce\x34\xb4\xc8\xd3\x4b\xcb\x2f\x4a\xcf\x2f\x89\x2f\x48\x2c\x2e\x2e\xcf\x2f\x4a\xa9\xad\xad\xe5\xe2\xaa\xae\xae\x4e\xcd\x4d\xcc\xcc\x81\x71\xc0\x0a\x93\x73\x32\x93\xb3\xe3\x93\x4a\x4b\x4a\xf2\xf3\xc0\x12\xba\x98\x00\xae\xb8\x28\xb5\x38\xb5\x24\x3e\x27\x33\x2f\x1b\xa4\x14\x9b\x4a\xb8\xd2\xd4\x8a\x82\xcc\xa2\xc4\x92\x4c\xa8\xa9\x30\xe1\x3c\xa0\xa3\x2a\xf3\x4b\x41\x62\x00\x32\x05\xc1\xf3\xab\x00\x00\x00")
+
+func mailersTemplatesPasswordResetTxtHbsBytes() ([]byte, error) {
+	return bindataRead(
+		_mailersTemplatesPasswordResetTxtHbs,
+		"mailers/templates/password_reset.txt.hbs",
+	)
+}
+
+func mailersTemplatesPasswordResetTxtHbs() (*asset, error) {
+	bytes, err := mailersTemplatesPasswordResetTxtHbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mailers/templates/password_reset.txt.hbs", size: 171, mode: os.FileMode(420), modTime: time.Unix(1792380293, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _mailersTemplatesRegistrationHtmlHbs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x75\x53\xb1\x72\x83\x30\x0c\xdd\xf3\x15\x3e\x3a\xa7\x5c\x33\x75\x20\x4c\xfd\x80\x2e\x99\x73\x0a\x28\xc1\x57\x63\x38\x5b\x24\xe9\xf9\xf8\xf7\xca\x80\x29\x04\x98\xb0\x9e\x9e\xde\x93\x65\x91\x10\x5c\x14\x8a\x4c\x81\xb5\xc7\xc8\x54\x0f\x91\x55\x9a\x50\x53\x94\xee\x84\x48\xc8\xf8\x8f\x3f\xe4\x81\xf3\x30\x50\xd7\x68\x04\x47\x9e\xd4\xa5\x3d\x61\xaa\x43\x0f\x54\x77\x8e\x2a\xd5\x94\xda\x46\xe9\x40\x9a\x08\x86\x70\x94\xcd\xd8\x93\x55\x09\x9f\xb4\xaf\x21\x8f\x04\x28\x79\xd3\x01\xff\x37\x1a\x0a\x7b\x38\x9d\x81\x0c\x17\x87\xb1\x05\x49\x0a\xa3\xd4\x39\xf9\xf1\xa9\xdf\xa9\x00\xfd\x63\xdb\x36\x89\x8b\xc3\x8b\x14\x57\xd5\x81\x66\xf0\x26\x2d\xeb\x62\xee\xa9\xf5\x1a\xf3\x05\x61\xcc\x92\xa9\xf4\x2d\x48\xe4\x40\xe8\x8b\x07\x54\x38\x87\x77\xee\xf5\xab\x83\x17\xc5\xce\xbd\xc9\xab\xe8\x18\xdf\x0a\xb2\x35\x0a\x3b\x5c\x8c\x88\xd3\x17\x9f\xba\xa7\x2f\x8c\xb6\x64\x9c\x8b\xe5\x75\x81\x6f\x5c\x72\xf0\xb0\x88\xe7\xdf\xaa\xd9\x98\xc5\xec\xc5\x4b\xcc\x65\x53\xee\x2f\x0d\x51\xa5\x45\x09\x52\xef\x21\x23\xc9\x67\x03\x9c\x99\xee\xc0\xc6\x2e\x4c\x76\x62\x0d\xe6\x04\x88\xc2\xe0\xf5\x18\x39\x67\x25\xe1\xc9\xa8\xb6\x1d\x1f\xf8\x2e\x19\x3b\x7b\xdc\x77\x0b\xab\xc2\xf1\x9a\x32\xa3\xcb\x35\x8a\xbb\xbb\xad\x4d\x26\xb1\x25\x28\x15\x5c\x75\x45\x61\x40\x3d\xde\x0d\x6a\x2e\x15\x36\x75\xb7\xdd\xc9\xe4\x37\xc0\x67\x0d\x3a\xf7\x0b\x3f\x27\x4d\xdb\x9c\xb7\x17\x78\x3d\x63\xcc\xfd\x01\x17\x2c\x43\x35\xd9\x03\x00\x00")
+
+func mailersTemplatesRegistrationHtmlHbsBytes() ([]byte, error) {
+	return bindataRead(
+		_mailersTemplatesRegistrationHtmlHbs,
+		"mailers/templates/registration.html.hbs",
+	)
+}
+
+func mailersTemplatesRegistrationHtmlHbs() (*asset, error) {
+	bytes, err := mailersTemplatesRegistrationHtmlHbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mailers/templates/registration.html.hbs", size: 985, mode: os.FileMode(420), modTime: time.Unix(1792371787, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _mailersTemplatesRegistrationTxtHbs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xab\xae\xae\xce\x34\xb4\xc8\xd3\x2b\xc9\x48\xcc\xcb\x2e\xae\xad\xad\xe5\xe2\xaa\x86\x0a\x15\xa5\xa6\x67\x16\x97\xa4\x16\xa5\xa6\xa0\x08\xa7\x24\x96\xa4\x02\x05\x14\x80\xfc\xd4\xb2\xd4\xbc\x12\x17\x08\x1f\x28\xaf\x9c\x99\xa6\x00\x16\x0a\xc8\x49\x4c\x4e\x05\x0b\x41\xb4\x14\x40\xf8\x08\x3d\x50\x05\x20\x15\xfa\x99\x69\xc8\xa6\x17\xa7\xa6\xc6\x57\xe6\x97\x82\x6d\xd4\xc5\x04\x20\x75\xc5\x99\x25\xa9\xa1\x45\x39\x20\x25\xd8\x54\xc0\x8d\xca\xcb\x2f\x81\x19\x05\x00\xa4\x9f\x8b\x0a\xe6\x00\x00\x00")
+
+func mailersTemplatesRegistrationTxtHbsBytes() ([]byte, error) {
+	return bindataRead(
+		_mailersTemplatesRegistrationTxtHbs,
+		"mailers/templates/registration.txt.hbs",
+	)
+}
+
+func mailersTemplatesRegistrationTxtHbs() (*asset, error) {
+	bytes, err := mailersTemplatesRegistrationTxtHbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mailers/templates/registration.txt.hbs", size: 230, mode: os.FileMode(420), modTime: time.Unix(1792371787, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
 var _mailersTemplatesSignupHtmlHbs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x74\x52\xc1\x6e\xc3\x20\x0c\xbd\xf7\x2b\x50\xee\x5d\xb4\x9e\xa6\x89\xf2\x17\x3b\x47\x2e\xf1\x56\x34\x02\x88\x38\x6d\x25\x94\x7f\x1f\x24\x24\x23\x5b\x7a\xc2\x7e\x7e\x7e\x0f\x1b\x38\xc1\x45\x23\x93\x1a\xfa\xfe\x5c\x79\x7b\x67\xd2\x1a\x42\x43\x95\x38\x30\xc6\xc9\xa7\x23\x05\xed\xc2\xb9\x7b\x70\x0e\x3d\x8b\x59\x22\x4d\xe5\x44\x28\x75\xe8\x8e\xfa\x16\x33\xab\x87\xce\xf4\x95\xc8\xa4\x42\x70\x49\x57\x59\x19\x3d\xa3\x2a\xe1\x83\x8e\x0e\xda\x8a\x81\x56\x5f\x66\xc1\x7f\x8d\x72\xe3\x0c\x8b\x0d\x18\xe1\xeb\x69\xbd\x82\x22\x8d\x95\x08\x41\xbd\xbe\x99\x17\x6b\xb0\xe9\xac\xc7\xa6\x27\x74\xe3\xc8\xeb\xeb\xe9\x8f\x62\x6c\x76\x82\x03\xbb\x7a\xfc\x3c\x57\x1d\x28\x4d\xf6\x3d\x04\x4c\xd1\x38\x26\xa1\x1c\xf2\x1a\x04\xaf\xdd\x5e\x7b\x36\x93\x5a\xc9\xef\xe6\x32\x10\x59\x93\xf8\x3b\xdc\xcd\xb6\x3a\x6c\xd5\xd0\x1d\xe7\x06\x16\x5d\xcc\x11\x24\xa9\x18\x7b\x88\x95\x72\x7f\x4f\xf6\x58\xec\x73\x0f\x8e\x85\x65\xb0\x10\x92\xf4\x0d\x92\xfc\x87\xcf\x93\x4d\xb7\xce\x38\x36\x20\xa5\x1d\x0c\xcd\x93\xee\x99\xd4\x7b\x2e\x11\xfd\xff\x1c\xf5\x34\xe7\x16\xe6\xf5\xf2\x76\x87\xe7\x9a\xc5\xc7\xc0\x87\x03\xd3\xa6\x2f\xb0\x25\x95\x86\xab\xd1\xa1\x14\x9b\x19\x6b\xed\x27\x00\x00\xff\xff\x86\x87\x70\x60\xeb\x02\x00\x00")
 
 func mailersTemplatesSignupHtmlHbsBytes() ([]byte, error) {
@@ -194,6 +364,46 @@ func mailersTemplatesSignupTxtHbs() (*asset, error) {
 	return a, nil
 }
 
+var _mailersTemplatesSubscriptionHtmlHbs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x75\x52\x4d\x6f\x83\x30\x0c\xbd\xf7\x57\x44\xdc\x3b\xb4\x9e\xa6\x89\xf2\x2f\x76\x46\x26\xb8\x23\x5a\xbe\x94\x98\xb6\x13\xe2\xbf\x2f\x21\xa4\x83\x8d\x9e\xe2\x3c\x3f\xbf\x67\x3b\xa9\x08\x5a\x89\x8c\x4b\xf0\xfe\x5c\x38\x73\x63\xdc\x68\x42\x4d\x45\x7d\x60\xac\x22\x17\x8f\x18\x74\x99\x73\x73\x60\x2d\x3a\x16\x6e\x91\x34\xa7\x23\x61\xad\x43\x37\x94\xd7\x70\x33\x72\x50\xda\x17\xf5\x42\x5a\x09\xe6\xeb\x43\x96\x07\xcf\xa0\x4a\x78\xa7\xa3\x85\xae\x60\x20\xc5\xa7\xce\xf8\xaf\xd1\x52\x98\xe0\x7a\x03\x06\xb8\x3f\x3d\x5a\x10\x24\xb1\xa8\xc7\x51\xbc\xbe\xe9\x17\xa3\xb1\x51\xc6\x61\xe3\x09\xed\x34\x55\x65\x7f\xfa\xa3\x18\x8a\x6d\x5d\x01\xeb\x1d\x5e\xce\x85\x02\x21\xc9\xbc\x8f\x23\xc6\x68\x9a\xa2\xd0\x12\x56\x25\xd4\x55\x69\xf7\xca\x17\x33\x2e\x05\xff\x6a\xda\x81\xc8\xe8\xc8\xdf\xe1\x6e\xb6\xa5\xb0\x13\x83\x3a\xa6\x02\x16\x5c\xf4\x11\x38\x89\x10\x3b\x08\x99\xf5\xfe\x9e\xec\x71\xb5\xcf\x3d\x38\x24\xf2\x60\xe3\x18\xde\xf7\x22\x9c\x82\x68\xf0\xe1\x96\xd9\x52\xdf\x29\xd3\xf8\xa1\xf5\xdc\x09\x1b\x19\x69\xde\x3d\xab\x72\xcf\x2b\xa0\xff\x1f\xa5\x9c\xa7\xdd\xdd\xb7\x57\x20\x65\xf6\xd7\x86\x9a\x6f\x33\x44\xcb\x84\xcf\xab\xdb\x4a\xe5\x77\x3f\x3c\xef\x64\xf5\xa9\xf0\x6e\x41\x77\xf1\xfb\x6c\x49\xeb\x36\xb7\xed\x65\x5e\x62\x3c\x72\x3f\xa4\xa8\x4a\x59\x27\x03\x00\x00")
+
+func mailersTemplatesSubscriptionHtmlHbsBytes() ([]byte, error) {
+	return bindataRead(
+		_mailersTemplatesSubscriptionHtmlHbs,
+		"mailers/templates/subscription.html.hbs",
+	)
+}
+
+func mailersTemplatesSubscriptionHtmlHbs() (*asset, error) {
+	bytes, err := mailersTemplatesSubscriptionHtmlHbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mailers/templates/subscription.html.hbs", size: 807, mode: os.FileMode(420), modTime: time.Unix(1792374029, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
+var _mailersTemplatesSubscriptionTxtHbs = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xab\xae\xae\xce\x34\xb4\xc8\xd3\xcb\xcf\x4b\x8d\xcf\xcd\x2f\x4a\x8d\x2f\x2e\x49\x2d\xa8\xad\xad\xe5\xe2\xaa\xae\xae\x4e\xcd\x4d\xcc\xcc\x81\x71\xc0\xca\x92\x73\x32\x93\xb3\xe3\x93\x4a\x4b\x4a\xf2\xf3\xc0\x12\xba\x98\x00\xa1\x38\x3f\x2f\x2d\xb3\x28\x37\x3e\x27\x33\x2f\x1b\xa4\x18\x9b\x5a\xb8\xe2\xbc\xfc\x92\xf8\xca\xfc\x52\x90\x3a\x00\x3b\xfa\x6a\x73\x94\x00\x00\x00")
+
+func mailersTemplatesSubscriptionTxtHbsBytes() ([]byte, error) {
+	return bindataRead(
+		_mailersTemplatesSubscriptionTxtHbs,
+		"mailers/templates/subscription.txt.hbs",
+	)
+}
+
+func mailersTemplatesSubscriptionTxtHbs() (*asset, error) {
+	bytes, err := mailersTemplatesSubscriptionTxtHbsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "mailers/templates/subscription.txt.hbs", size: 148, mode: os.FileMode(420), modTime: time.Unix(1792374029, 0)}
+	a := &asset{bytes: bytes, info:  info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -248,10 +458,20 @@ func AssetNames() []string {
 var _bindata = map[string]func() (*asset, error){
 	"locales/en.json": localesEnJson,
 	"locales/fr.json": localesFrJson,
+	"mailers/templates/contact.html.hbs": mailersTemplatesContactHtmlHbs,
+	"mailers/templates/contact.txt.hbs": mailersTemplatesContactTxtHbs,
 	"mailers/templates/layout.html.hbs": mailersTemplatesLayoutHtmlHbs,
 	"mailers/templates/layout.txt.hbs": mailersTemplatesLayoutTxtHbs,
+	"mailers/templates/newsletter.html.hbs": mailersTemplatesNewsletterHtmlHbs,
+	"mailers/templates/newsletter.txt.hbs": mailersTemplatesNewsletterTxtHbs,
+	"mailers/templates/password_reset.html.hbs": mailersTemplatesPasswordResetHtmlHbs,
+	"mailers/templates/password_reset.txt.hbs": mailersTemplatesPasswordResetTxtHbs,
+	"mailers/templates/registration.html.hbs": mailersTemplatesRegistrationHtmlHbs,
+	"mailers/templates/registration.txt.hbs": mailersTemplatesRegistrationTxtHbs,
 	"mailers/templates/signup.html.hbs": mailersTemplatesSignupHtmlHbs,
 	"mailers/templates/signup.txt.hbs": mailersTemplatesSignupTxtHbs,
+	"mailers/templates/subscription.html.hbs": mailersTemplatesSubscriptionHtmlHbs,
+	"mailers/templates/subscription.txt.hbs": mailersTemplatesSubscriptionTxtHbs,
 }
 
 // AssetDir returns the file names below a certain
@@ -302,14 +522,34 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	}},
 	"mailers": &bintree{nil, map[string]*bintree{
 		"templates": &bintree{nil, map[string]*bintree{
+			"contact.html.hbs": &bintree{mailersTemplatesContactHtmlHbs, map[string]*bintree{
+			}},
+			"contact.txt.hbs": &bintree{mailersTemplatesContactTxtHbs, map[string]*bintree{
+			}},
 			"layout.html.hbs": &bintree{mailersTemplatesLayoutHtmlHbs, map[string]*bintree{
 			}},
 			"layout.txt.hbs": &bintree{mailersTemplatesLayoutTxtHbs, map[string]*bintree{
 			}},
+			"newsletter.html.hbs": &bintree{mailersTemplatesNewsletterHtmlHbs, map[string]*bintree{
+			}},
+			"newsletter.txt.hbs": &bintree{mailersTemplatesNewsletterTxtHbs, map[string]*bintree{
+			}},
+			"password_reset.html.hbs": &bintree{mailersTemplatesPasswordResetHtmlHbs, map[string]*bintree{
+			}},
+			"password_reset.txt.hbs": &bintree{mailersTemplatesPasswordResetTxtHbs, map[string]*bintree{
+			}},
+			"registration.html.hbs": &bintree{mailersTemplatesRegistrationHtmlHbs, map[string]*bintree{
+			}},
+			"registration.txt.hbs": &bintree{mailersTemplatesRegistrationTxtHbs, map[string]*bintree{
+			}},
 			"signup.html.hbs": &bintree{mailersTemplatesSignupHtmlHbs, map[string]*bintree{
 			}},
 			"signup.txt.hbs": &bintree{mailersTemplatesSignupTxtHbs, map[string]*bintree{
 			}},
+			"subscription.html.hbs": &bintree{mailersTemplatesSubscriptionHtmlHbs, map[string]*bintree{
+			}},
+			"subscription.txt.hbs": &bintree{mailersTemplatesSubscriptionTxtHbs, map[string]*bintree{
+			}},
 		}},
 	}},
 }}
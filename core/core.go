@@ -3,7 +3,10 @@ package core
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path"
+	"strings"
+	"sync"
 
 	"github.com/aymerick/kowa/helpers"
 	"github.com/spf13/viper"
@@ -19,10 +22,19 @@ const (
 	// DefaultTheme is default theme
 	DefaultTheme = "willy"
 
+	// PublicFormHoneypot is the name of the public forms field that must stay empty, bots usually fill it
+	PublicFormHoneypot = "website"
+
 	uploadURLPath  = "/upload"
 	defaultBaseURL = "http://127.0.0.1"
 )
 
+var (
+	// cwebp binary path found in PATH, looked up only once
+	cwebpLookPath     string
+	cwebpLookPathOnce sync.Once
+)
+
 // DefaultDomain returns default domain, or an empty string if no domain found in settings.
 func DefaultDomain() string {
 	domains := viper.GetStringSlice("service_domains")
@@ -63,6 +75,31 @@ func BaseUrlForCustomDomain(domain string) string {
 	return fmt.Sprintf("http://%s", domain)
 }
 
+// PublicAPIUrl computes the url of given public API path, as used by built sites.
+func PublicAPIUrl(apiPath string) string {
+	base := viper.GetString("api_url")
+	if base == "" {
+		base = strings.TrimSuffix(viper.GetString("service_url"), "/") + "/api"
+	}
+
+	return strings.TrimSuffix(base, "/") + path.Join("/public", apiPath)
+}
+
+// CWebPPath returns the path to cwebp binary, used to encode WebP images, or an empty string if not available
+func CWebPPath() string {
+	if result := viper.GetString("cwebp_path"); result != "" {
+		return result
+	}
+
+	cwebpLookPathOnce.Do(func() {
+		if result, err := exec.LookPath("cwebp"); err == nil {
+			cwebpLookPath = result
+		}
+	})
+
+	return cwebpLookPath
+}
+
 // UploadDir returns the main upload directory path
 func UploadDir() string {
 	dir := viper.GetString("upload_dir")
@@ -0,0 +1,173 @@
+// Package derivatives generates images derivatives in background.
+package derivatives
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/models"
+)
+
+const jobsQueueLen = 1000
+
+// ErrStopped is returned when enqueuing an image after pipeline was stopped
+var ErrStopped = errors.New("Derivatives pipeline is stopped")
+
+// Pipeline generates images derivatives with a pool of workers
+type Pipeline struct {
+	dbSession *models.DBSession
+	workersNb int
+
+	jobs chan *job
+	wg   *sync.WaitGroup
+
+	// protects jobs channel closing
+	mutex   sync.RWMutex
+	stopped bool
+
+	// OnDone is called by workers when an image is processed
+	OnDone func(img *models.Image, err error)
+
+	// image operations, replaced in tests
+	findImage func(imageID bson.ObjectId) (img *models.Image, release func())
+	generate  func(img *models.Image, force bool) error
+	setStatus func(img *models.Image, status string) error
+}
+
+type job struct {
+	imageID bson.ObjectId
+	force   bool
+}
+
+// NewPipeline instanciates a new Pipeline
+func NewPipeline(dbSession *models.DBSession, workersNb int) *Pipeline {
+	if workersNb < 1 {
+		workersNb = 1
+	}
+
+	result := &Pipeline{
+		dbSession: dbSession,
+		workersNb: workersNb,
+
+		jobs: make(chan *job, jobsQueueLen),
+		wg:   &sync.WaitGroup{},
+
+		generate:  (*models.Image).GenerateDerivatives,
+		setStatus: (*models.Image).SetStatus,
+	}
+
+	result.findImage = result.dbFindImage
+
+	return result
+}
+
+// Start starts workers
+func (pipeline *Pipeline) Start() {
+	for i := 0; i < pipeline.workersNb; i++ {
+		pipeline.wg.Add(1)
+
+		go func() {
+			defer pipeline.wg.Done()
+
+			for job := range pipeline.jobs {
+				pipeline.process(job)
+			}
+		}()
+	}
+
+	log.Printf("[derivatives] Started %d workers", pipeline.workersNb)
+}
+
+// Stop waits for all enqueued images to be processed, then stops workers
+func (pipeline *Pipeline) Stop() {
+	pipeline.mutex.Lock()
+	if !pipeline.stopped {
+		pipeline.stopped = true
+		close(pipeline.jobs)
+	}
+	pipeline.mutex.Unlock()
+
+	pipeline.wg.Wait()
+
+	log.Printf("[derivatives] All workers stopped")
+}
+
+// Enqueue marks image derivatives as pending, and enqueues their generation
+//
+// If force is true, existing derivatives are regenerated. Returns ErrStopped if pipeline was stopped: image stays
+// pending and is enqueued again by EnqueuePending on next start.
+func (pipeline *Pipeline) Enqueue(img *models.Image, force bool) error {
+	if img.Status != models.ImageStatusPending {
+		if err := pipeline.setStatus(img, models.ImageStatusPending); err != nil {
+			return err
+		}
+	}
+
+	pipeline.mutex.RLock()
+	defer pipeline.mutex.RUnlock()
+
+	if pipeline.stopped {
+		return ErrStopped
+	}
+
+	pipeline.jobs <- &job{imageID: img.ID, force: force}
+
+	return nil
+}
+
+// EnqueuePending enqueues all images with pending derivatives, ie. images that were not processed before last
+// server stop or crash
+func (pipeline *Pipeline) EnqueuePending() {
+	dbSession := pipeline.dbSession.Copy()
+	defer dbSession.Close()
+
+	images := *dbSession.FindPendingImages()
+	if len(images) > 0 {
+		log.Printf("[derivatives] Resuming %d pending images", len(images))
+	}
+
+	for _, img := range images {
+		if err := pipeline.Enqueue(img, true); err != nil {
+			log.Printf("[derivatives] Failed to enqueue image derivatives: %s - %v", img.Path, err)
+			return
+		}
+	}
+}
+
+// Generate derivatives of an image
+func (pipeline *Pipeline) process(job *job) {
+	img, release := pipeline.findImage(job.imageID)
+	defer release()
+
+	if img == nil {
+		// image was deleted
+		return
+	}
+
+	status := models.ImageStatusReady
+
+	err := pipeline.generate(img, job.force)
+	if err != nil {
+		log.Printf("[derivatives] Failed to generate image derivatives: %s - %v", img.Path, err)
+
+		status = models.ImageStatusFailed
+	}
+
+	if errStatus := pipeline.setStatus(img, status); errStatus != nil {
+		log.Printf("[derivatives] Failed to update image status: %s - %v", img.Path, errStatus)
+	}
+
+	if pipeline.OnDone != nil {
+		pipeline.OnDone(img, err)
+	}
+}
+
+// Fetches image with a dedicated database session, that must be released once image is processed
+func (pipeline *Pipeline) dbFindImage(imageID bson.ObjectId) (*models.Image, func()) {
+	dbSession := pipeline.dbSession.Copy()
+
+	return dbSession.FindImage(imageID), dbSession.Close
+}
@@ -0,0 +1,154 @@
+package derivatives
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/models"
+)
+
+type PipelineTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestPipelineTestSuite(t *testing.T) {
+	suite.Run(t, new(PipelineTestSuite))
+}
+
+// testImages is an in-memory images store
+type testImages struct {
+	mutex    sync.Mutex
+	images   map[bson.ObjectId]*models.Image
+	statuses map[bson.ObjectId][]string
+	failing  map[bson.ObjectId]bool
+	done     map[bson.ObjectId]error
+}
+
+func newTestImages() *testImages {
+	return &testImages{
+		images:   make(map[bson.ObjectId]*models.Image),
+		statuses: make(map[bson.ObjectId][]string),
+		failing:  make(map[bson.ObjectId]bool),
+		done:     make(map[bson.ObjectId]error),
+	}
+}
+
+func (store *testImages) add(status string, failing bool) *models.Image {
+	img := &models.Image{ID: bson.NewObjectId(), Status: status}
+
+	store.images[img.ID] = img
+	store.failing[img.ID] = failing
+
+	return img
+}
+
+// newTestPipeline returns a pipeline working on given images store
+func newTestPipeline(store *testImages) *Pipeline {
+	pipeline := NewPipeline(nil, 2)
+
+	pipeline.findImage = func(imageID bson.ObjectId) (*models.Image, func()) {
+		store.mutex.Lock()
+		defer store.mutex.Unlock()
+
+		return store.images[imageID], func() {}
+	}
+
+	pipeline.generate = func(img *models.Image, force bool) error {
+		store.mutex.Lock()
+		defer store.mutex.Unlock()
+
+		if store.failing[img.ID] {
+			return errors.New("Failed to generate derivatives")
+		}
+
+		return nil
+	}
+
+	pipeline.setStatus = func(img *models.Image, status string) error {
+		store.mutex.Lock()
+		defer store.mutex.Unlock()
+
+		img.Status = status
+		store.statuses[img.ID] = append(store.statuses[img.ID], status)
+
+		return nil
+	}
+
+	pipeline.OnDone = func(img *models.Image, err error) {
+		store.mutex.Lock()
+		defer store.mutex.Unlock()
+
+		store.done[img.ID] = err
+	}
+
+	return pipeline
+}
+
+//
+// Tests
+//
+
+func (suite *PipelineTestSuite) TestStatus() {
+	t := suite.T()
+
+	store := newTestImages()
+
+	ready := store.add(models.ImageStatusReady, false)
+	failed := store.add("", true)
+	pending := store.add(models.ImageStatusPending, false)
+	deleted := &models.Image{ID: bson.NewObjectId(), Status: models.ImageStatusReady}
+
+	pipeline := newTestPipeline(store)
+	pipeline.Start()
+
+	for _, img := range []*models.Image{ready, failed, pending, deleted} {
+		assert.Nil(t, pipeline.Enqueue(img, true))
+	}
+
+	pipeline.Stop()
+
+	assert.Equal(t, []string{models.ImageStatusPending, models.ImageStatusReady}, store.statuses[ready.ID])
+	assert.Equal(t, []string{models.ImageStatusPending, models.ImageStatusFailed}, store.statuses[failed.ID])
+	assert.Equal(t, []string{models.ImageStatusReady}, store.statuses[pending.ID])
+
+	assert.Equal(t, models.ImageStatusReady, ready.Status)
+	assert.Equal(t, models.ImageStatusFailed, failed.Status)
+	assert.Equal(t, models.ImageStatusReady, pending.Status)
+
+	// OnDone
+	if assert.Len(t, store.done, 3) {
+		assert.Nil(t, store.done[ready.ID])
+		assert.NotNil(t, store.done[failed.ID])
+		assert.Nil(t, store.done[pending.ID])
+	}
+
+	_, found := store.done[deleted.ID]
+	assert.False(t, found)
+}
+
+func (suite *PipelineTestSuite) TestEnqueueStopped() {
+	t := suite.T()
+
+	store := newTestImages()
+	img := store.add(models.ImageStatusReady, false)
+
+	pipeline := newTestPipeline(store)
+	pipeline.Start()
+	pipeline.Stop()
+
+	// stopping twice is harmless
+	pipeline.Stop()
+
+	assert.Equal(t, ErrStopped, pipeline.Enqueue(img, true))
+
+	// image stays pending, to be resumed on next start
+	assert.Equal(t, models.ImageStatusPending, img.Status)
+	assert.Len(t, store.done, 0)
+}
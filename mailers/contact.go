@@ -0,0 +1,90 @@
+package mailers
+
+import (
+	"strings"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/raymond"
+)
+
+// ContactMailer implements the mailer that delivers contact form messages to site owner
+type ContactMailer struct {
+	*BaseMailer
+
+	site    *models.Site
+	message *models.Message
+
+	// Template variables
+	Name     string
+	Email    string
+	Body     string
+	BodyHTML raymond.SafeString
+	SiteName string
+	SiteUrl  string
+}
+
+// NewContactMailer instanciates a new ContactMailer
+func NewContactMailer(message *models.Message, site *models.Site) *ContactMailer {
+	result := &ContactMailer{
+		BaseMailer: newBaseMailerForLang("contact", site.Lang),
+
+		site:    site,
+		message: message,
+
+		// Template variables
+		Name:     message.Name,
+		Email:    message.Email,
+		Body:     message.Body,
+		SiteName: site.Name,
+		SiteUrl:  site.BaseUrl(),
+	}
+
+	bodySafe := raymond.Escape(message.Body)
+	result.BodyHTML = raymond.SafeString(strings.Replace(bodySafe, "\n", "<br />\n", -1))
+
+	result.I18n = result.computeI18n()
+
+	return result
+}
+
+// Send triggers mail sending
+func (mailer *ContactMailer) Send() error {
+	return NewSender(mailer).Send()
+}
+
+// computeI18n computes translations
+func (mailer *ContactMailer) computeI18n() map[string]string {
+	return map[string]string{
+		"new_message": mailer.T("contact_email_new_message", core.P{"SiteName": mailer.SiteName}),
+		"from":        mailer.T("contact_email_from"),
+		"reply":       mailer.T("contact_email_reply"),
+	}
+}
+
+//
+// Mailer interface
+//
+
+// To is part of Mailer interface
+func (mailer *ContactMailer) To() string {
+	return mailer.site.Email
+}
+
+// Subject is part of Mailer interface
+func (mailer *ContactMailer) Subject() string {
+	if mailer.message.Subject != "" {
+		return mailer.T("contact_email_subject_with_subject", core.P{"SiteName": mailer.SiteName, "Subject": mailer.message.Subject})
+	}
+
+	return mailer.T("contact_email_subject", core.P{"SiteName": mailer.SiteName})
+}
+
+//
+// ReplyToMailer interface
+//
+
+// ReplyTo is part of ReplyToMailer interface
+func (mailer *ContactMailer) ReplyTo() string {
+	return mailer.message.MailAddress()
+}
@@ -0,0 +1,95 @@
+package mailers
+
+import (
+	"bytes"
+	"net/mail"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/helpers"
+	"github.com/aymerick/kowa/models"
+)
+
+type ContactTestSuite struct {
+	suite.Suite
+}
+
+// called before all tests
+func (suite *ContactTestSuite) SetupSuite() {
+	core.LoadLocales()
+
+	if os.Getenv("KOWA_TEST_EMBED_ASSETS") != "true" {
+		SetTemplatesDir(path.Join(helpers.WorkingDir(), "templates"))
+	}
+
+	viper.Set("smtp_from", "test@test.com")
+	viper.Set("service_name", "My Service")
+	viper.Set("service_url", "http://www.myservice.bar")
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestContactTestSuite(t *testing.T) {
+	suite.Run(t, new(ContactTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *ContactTestSuite) TestContact() {
+	t := suite.T()
+
+	site := &models.Site{
+		ID:    "my_site",
+		Name:  "My Site",
+		Lang:  "en",
+		Email: "owner@mysite.com",
+	}
+
+	message := &models.Message{
+		Name:    "Jean-Claude Trucmush",
+		Email:   "trucmush@wanadoo.fr",
+		Subject: "Hello",
+		Body:    "First line\n<b>Second</b> line",
+	}
+
+	sender := NewSender(NewContactMailer(message, site))
+	sender.SetNoop(true)
+
+	email := sender.newEmail()
+	assert.NotNil(t, email)
+
+	// check mail generation
+	rawMail, errGen := email.Bytes()
+	assert.Nil(t, errGen)
+
+	// parse generated mail
+	msg, errRead := mail.ReadMessage(bytes.NewBuffer(rawMail))
+	assert.Nil(t, errRead)
+
+	// check headers
+	expectedHeaders := map[string]string{
+		"To":       "owner@mysite.com",
+		"Reply-To": "\"Jean-Claude Trucmush\" <trucmush@wanadoo.fr>",
+		"Subject":  "[My Site] Hello",
+	}
+
+	for header, expected := range expectedHeaders {
+		val := msg.Header.Get(header)
+		assert.Equal(t, expected, val)
+	}
+
+	textStr := string(email.Text)
+	assert.Regexp(t, `You received a new message from your website My Site\.`, textStr)
+	assert.Regexp(t, `<b>Second</b> line`, textStr)
+
+	htmlStr := string(email.HTML)
+	assert.Regexp(t, `First line<br ?/>\s*&lt;b&gt;Second&lt;/b&gt; line`, htmlStr)
+}
@@ -14,6 +14,16 @@ type Mailer interface {
 	Subject() string
 }
 
+// ReplyToMailer is implemented by mailers that set a Reply-To header
+type ReplyToMailer interface {
+	ReplyTo() string
+}
+
+// UnsubscribeMailer is implemented by mailers that set a List-Unsubscribe header
+type UnsubscribeMailer interface {
+	UnsubscribeURL() string
+}
+
 // BaseMailer is a base for all mailers
 type BaseMailer struct {
 	kind string
@@ -32,11 +42,18 @@ type BaseMailer struct {
 
 // NewBaseMailer instanciates a new BaseMailer
 func NewBaseMailer(kind string, user *models.User) *BaseMailer {
+	result := newBaseMailerForLang(kind, user.Lang)
+	result.user = user
+
+	return result
+}
+
+// newBaseMailerForLang instanciates a new BaseMailer that is not sent to a user
+func newBaseMailerForLang(kind string, lang string) *BaseMailer {
 	return &BaseMailer{
 		kind: kind,
-		user: user,
 
-		T: core.MustTfunc(user.Lang),
+		T: core.MustTfunc(lang),
 
 		// Template variables
 		ServiceName:            viper.GetString("service_name"),
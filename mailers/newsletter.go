@@ -0,0 +1,236 @@
+package mailers
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/russross/blackfriday"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/kowa/token"
+)
+
+const (
+	newsletterBatchSize   = 50
+	newsletterBatchPause  = 10 * time.Second
+	newsletterMaxFailures = 10 // consecutive failures before giving up, SMTP server is probably down
+	newsletterExcerptLen  = 300
+)
+
+// Newsletter delivers posts to all confirmed subscribers of a site
+type Newsletter struct {
+	site   *models.Site
+	posts  []*models.Post
+	digest bool
+
+	batchSize  int
+	batchPause time.Duration
+	noop       bool
+}
+
+// NewsletterPost represents a post in a newsletter email
+type NewsletterPost struct {
+	Title   string
+	Date    string
+	Excerpt string
+}
+
+// NewsletterMailer implements the mailer that sends posts to a site subscriber
+type NewsletterMailer struct {
+	*BaseMailer
+
+	subscriber *models.Subscriber
+	digest     bool
+
+	// Template variables
+	Posts          []*NewsletterPost
+	SiteName       string
+	SiteUrl        string
+	UnsubscribeUrl string
+}
+
+// NewPostNewsletter instanciates a new Newsletter that announces a freshly published post
+func NewPostNewsletter(post *models.Post, site *models.Site) *Newsletter {
+	return newNewsletter(site, []*models.Post{post}, false)
+}
+
+// NewDigestNewsletter instanciates a new Newsletter that sends a digest of published posts
+func NewDigestNewsletter(posts []*models.Post, site *models.Site) *Newsletter {
+	return newNewsletter(site, posts, true)
+}
+
+func newNewsletter(site *models.Site, posts []*models.Post, digest bool) *Newsletter {
+	return &Newsletter{
+		site:   site,
+		posts:  posts,
+		digest: digest,
+
+		batchSize:  newsletterBatchSize,
+		batchPause: newsletterBatchPause,
+	}
+}
+
+// SetNoop sets newsletter in NOOP mode
+func (newsletter *Newsletter) SetNoop(isNoop bool) {
+	newsletter.noop = isNoop
+}
+
+// SetBatch sets the number of emails sent in a row, and the pause between two batches
+func (newsletter *Newsletter) SetBatch(size int, pause time.Duration) {
+	newsletter.batchSize = size
+	newsletter.batchPause = pause
+}
+
+// Mailer returns the mailer for given subscriber
+func (newsletter *Newsletter) Mailer(subscriber *models.Subscriber) *NewsletterMailer {
+	result := &NewsletterMailer{
+		BaseMailer: newBaseMailerForLang("newsletter", newsletter.site.Lang),
+
+		subscriber: subscriber,
+		digest:     newsletter.digest,
+
+		// Template variables
+		SiteName:       newsletter.site.Name,
+		SiteUrl:        newsletter.site.BaseUrl(),
+		UnsubscribeUrl: token.UnsubscribeURL(subscriber),
+	}
+
+	for _, post := range newsletter.posts {
+		result.Posts = append(result.Posts, &NewsletterPost{
+			Title:   post.Title,
+			Date:    result.formatDate(post, newsletter.site),
+			Excerpt: postExcerpt(post),
+		})
+	}
+
+	result.I18n = result.computeI18n()
+
+	return result
+}
+
+// Send sends newsletter to given subscribers, by batches
+//
+// A failure on one subscriber does not prevent delivery to others, so that a single bouncing address
+// can't block the whole newsletter. Returns the number of successfully sent emails.
+func (newsletter *Newsletter) Send(subscribers models.SubscribersList) (int, error) {
+	if len(newsletter.posts) == 0 {
+		return 0, nil
+	}
+
+	sent, failed, consecutive := 0, 0, 0
+
+	for i, subscriber := range subscribers {
+		if (i > 0) && (newsletter.batchSize > 0) && (i%newsletter.batchSize == 0) && !newsletter.noop {
+			// don't flood SMTP server
+			time.Sleep(newsletter.batchPause)
+		}
+
+		sender := NewSender(newsletter.Mailer(subscriber))
+		sender.SetNoop(newsletter.noop)
+
+		if err := sender.Send(); err != nil {
+			failed++
+			consecutive++
+
+			if consecutive >= newsletterMaxFailures {
+				return sent, fmt.Errorf("Newsletter aborted after %d consecutive failures: %v", consecutive, err)
+			}
+
+			continue
+		}
+
+		sent++
+		consecutive = 0
+	}
+
+	log.Printf("Newsletter for site %s sent to %d subscribers", newsletter.site.ID, sent)
+
+	if failed > 0 {
+		return sent, fmt.Errorf("Failed to send newsletter to %d subscribers", failed)
+	}
+
+	return sent, nil
+}
+
+// formatDate formats post publication date in site timezone
+func (mailer *NewsletterMailer) formatDate(post *models.Post, site *models.Site) string {
+	publishedAt := post.PublishedAt.In(site.TZLocation())
+
+	return mailer.T("post_format_date", core.P{
+		"Year":  publishedAt.Year(),
+		"Month": mailer.T("month_" + publishedAt.Format("January")),
+		"Day":   publishedAt.Day(),
+	})
+}
+
+// computeI18n computes translations
+func (mailer *NewsletterMailer) computeI18n() map[string]string {
+	result := map[string]string{
+		"read_more":   mailer.T("newsletter_email_read_more"),
+		"unsubscribe": mailer.T("newsletter_email_unsubscribe", core.P{"SiteName": mailer.SiteName}),
+	}
+
+	if mailer.digest {
+		result["title"] = mailer.T("newsletter_email_digest_title", core.P{"SiteName": mailer.SiteName})
+	} else {
+		result["title"] = mailer.T("newsletter_email_post_title", core.P{"SiteName": mailer.SiteName})
+	}
+
+	return result
+}
+
+// postExcerpt returns the beginning of post body, as plain text
+func postExcerpt(post *models.Post) string {
+	body := post.Body
+	if post.Format == models.FormatMarkdown {
+		body = string(blackfriday.MarkdownCommon([]byte(body)))
+	}
+
+	result := html.UnescapeString(bluemonday.StrictPolicy().Sanitize(body))
+	result = strings.Join(strings.Fields(result), " ")
+
+	if runes := []rune(result); len(runes) > newsletterExcerptLen {
+		result = string(runes[:newsletterExcerptLen])
+
+		// don't cut words
+		if i := strings.LastIndex(result, " "); i > 0 {
+			result = result[:i]
+		}
+
+		result += "…"
+	}
+
+	return result
+}
+
+//
+// Mailer interface
+//
+
+// To is part of Mailer interface
+func (mailer *NewsletterMailer) To() string {
+	return mailer.subscriber.Email
+}
+
+// Subject is part of Mailer interface
+func (mailer *NewsletterMailer) Subject() string {
+	if mailer.digest {
+		return mailer.T("newsletter_email_digest_subject", core.P{"SiteName": mailer.SiteName})
+	}
+
+	return mailer.T("newsletter_email_post_subject", core.P{"SiteName": mailer.SiteName, "Title": mailer.Posts[0].Title})
+}
+
+//
+// UnsubscribeMailer interface
+//
+
+// UnsubscribeURL is part of UnsubscribeMailer interface
+func (mailer *NewsletterMailer) UnsubscribeURL() string {
+	return mailer.UnsubscribeUrl
+}
@@ -0,0 +1,161 @@
+package mailers
+
+import (
+	"bytes"
+	"net/mail"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/helpers"
+	"github.com/aymerick/kowa/models"
+)
+
+type NewsletterTestSuite struct {
+	suite.Suite
+}
+
+// called before all tests
+func (suite *NewsletterTestSuite) SetupSuite() {
+	core.LoadLocales()
+
+	if os.Getenv("KOWA_TEST_EMBED_ASSETS") != "true" {
+		SetTemplatesDir(path.Join(helpers.WorkingDir(), "templates"))
+	}
+
+	viper.Set("secret_key", "my_so_secure_key")
+	viper.Set("smtp_from", "test@test.com")
+	viper.Set("service_name", "My Service")
+	viper.Set("service_url", "http://www.myservice.bar")
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestNewsletterTestSuite(t *testing.T) {
+	suite.Run(t, new(NewsletterTestSuite))
+}
+
+func newsletterTestSite() *models.Site {
+	return &models.Site{
+		ID:   "my_site",
+		Name: "My Site",
+		Lang: "en",
+		TZ:   "Europe/Paris",
+	}
+}
+
+func newsletterTestPost(title string, body string) *models.Post {
+	return &models.Post{
+		ID:          bson.NewObjectId(),
+		Published:   true,
+		PublishedAt: time.Date(2015, time.July, 14, 18, 0, 0, 0, time.UTC),
+		Title:       title,
+		Body:        body,
+		Format:      models.FormatMarkdown,
+	}
+}
+
+func newsletterTestSubscriber(email string) *models.Subscriber {
+	return &models.Subscriber{
+		ID:        bson.NewObjectId(),
+		SiteID:    "my_site",
+		Email:     email,
+		Confirmed: true,
+	}
+}
+
+//
+// Tests
+//
+
+func (suite *NewsletterTestSuite) TestPostNewsletter() {
+	t := suite.T()
+
+	post := newsletterTestPost("Summer Party", "We had a **great** party\n\non the beach.")
+	newsletter := NewPostNewsletter(post, newsletterTestSite())
+
+	sender := NewSender(newsletter.Mailer(newsletterTestSubscriber("trucmush@wanadoo.fr")))
+	sender.SetNoop(true)
+
+	email := sender.newEmail()
+	assert.NotNil(t, email)
+
+	// check mail generation
+	rawMail, errGen := email.Bytes()
+	assert.Nil(t, errGen)
+
+	// parse generated mail
+	msg, errRead := mail.ReadMessage(bytes.NewBuffer(rawMail))
+	assert.Nil(t, errRead)
+
+	// check headers
+	expectedHeaders := map[string]string{
+		"To":      "trucmush@wanadoo.fr",
+		"From":    "test@test.com",
+		"Subject": "[My Site] Summer Party",
+	}
+
+	for header, expected := range expectedHeaders {
+		val := msg.Header.Get(header)
+		assert.Equal(t, expected, val)
+	}
+
+	assert.Regexp(t, `^<http://www\.myservice\.bar/api/public/subscribers/unsubscribe\?token=.+>$`, msg.Header.Get("List-Unsubscribe"))
+	assert.Equal(t, "List-Unsubscribe=One-Click", msg.Header.Get("List-Unsubscribe-Post"))
+
+	textStr := string(email.Text)
+	assert.Regexp(t, `New post on My Site`, textStr)
+	assert.Regexp(t, `2015 July 14`, textStr)
+	assert.Regexp(t, `We had a great party on the beach\.`, textStr)
+
+	htmlStr := string(email.HTML)
+	assert.Regexp(t, `<h3[^>]*>Summer Party</h3>`, htmlStr)
+	assert.Regexp(t, `Unsubscribe from My Site news`, htmlStr)
+}
+
+func (suite *NewsletterTestSuite) TestDigestNewsletter() {
+	t := suite.T()
+
+	posts := []*models.Post{
+		newsletterTestPost("First post", "Hello"),
+		newsletterTestPost("Second post", strings.Repeat("word ", 100)),
+	}
+
+	mailer := NewDigestNewsletter(posts, newsletterTestSite()).Mailer(newsletterTestSubscriber("trucmush@wanadoo.fr"))
+
+	assert.Equal(t, "[My Site] Latest news", mailer.Subject())
+	assert.Len(t, mailer.Posts, 2)
+	assert.True(t, strings.HasSuffix(mailer.Posts[1].Excerpt, "word…"))
+	assert.True(t, len(mailer.Posts[1].Excerpt) < 310)
+}
+
+func (suite *NewsletterTestSuite) TestNewsletterSend() {
+	t := suite.T()
+
+	newsletter := NewPostNewsletter(newsletterTestPost("Summer Party", "Hello"), newsletterTestSite())
+	newsletter.SetNoop(true)
+	newsletter.SetBatch(2, time.Hour)
+
+	subscribers := models.SubscribersList{
+		newsletterTestSubscriber("one@wanadoo.fr"),
+		newsletterTestSubscriber("two@wanadoo.fr"),
+		newsletterTestSubscriber("three@wanadoo.fr"),
+	}
+
+	sent, err := newsletter.Send(subscribers)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, sent)
+
+	// nothing to send
+	sent, err = NewDigestNewsletter(nil, newsletterTestSite()).Send(subscribers)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, sent)
+}
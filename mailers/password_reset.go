@@ -0,0 +1,62 @@
+package mailers
+
+import (
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/kowa/token"
+)
+
+// PasswordResetMailer implements the password reset mailer
+type PasswordResetMailer struct {
+	*BaseMailer
+
+	// Template variables
+	Email    string
+	ResetUrl string
+}
+
+// NewPasswordResetMailer instanciates a new PasswordResetMailer
+func NewPasswordResetMailer(user *models.User) *PasswordResetMailer {
+	result := &PasswordResetMailer{
+		BaseMailer: NewBaseMailer("password_reset", user),
+
+		// Template variables
+		Email:    user.Email,
+		ResetUrl: token.PasswordResetURL(user),
+	}
+
+	result.I18n = result.computeI18n()
+
+	return result
+}
+
+// Send triggers mail sending
+func (mailer *PasswordResetMailer) Send() error {
+	return NewSender(mailer).Send()
+}
+
+// computeI18n computes translations
+func (mailer *PasswordResetMailer) computeI18n() map[string]string {
+	return map[string]string{
+		"forgot_password": mailer.T("password_reset_email_forgot_password"),
+		"click_button":    mailer.T("password_reset_email_click_button", core.P{"ServiceName": mailer.ServiceName}),
+		"reset_password":  mailer.T("password_reset_email_reset_password"),
+		"reset_link":      mailer.T("password_reset_email_reset_link", core.P{"ResetUrl": mailer.ResetUrl}),
+		"expiration":      mailer.T("password_reset_email_expiration"),
+		"not_you":         mailer.T("password_reset_email_not_you"),
+	}
+}
+
+//
+// Mailer interface
+//
+
+// To is part of Mailer interface
+func (mailer *PasswordResetMailer) To() string {
+	return mailer.user.MailAddress()
+}
+
+// Subject is part of Mailer interface
+func (mailer *PasswordResetMailer) Subject() string {
+	return mailer.T("password_reset_email_subject", core.P{"ServiceName": mailer.ServiceName})
+}
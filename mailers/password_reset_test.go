@@ -0,0 +1,96 @@
+package mailers
+
+import (
+	"bytes"
+	"net/mail"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/helpers"
+	"github.com/aymerick/kowa/models"
+)
+
+type PasswordResetTestSuite struct {
+	suite.Suite
+}
+
+// called before all tests
+func (suite *PasswordResetTestSuite) SetupSuite() {
+	core.LoadLocales()
+
+	if os.Getenv("KOWA_TEST_EMBED_ASSETS") != "true" {
+		SetTemplatesDir(path.Join(helpers.WorkingDir(), "templates"))
+	}
+
+	viper.Set("secret_key", "my_so_secure_key")
+	viper.Set("smtp_from", "test@test.com")
+	viper.Set("service_name", "My Service")
+	viper.Set("service_url", "http://www.myservice.bar")
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestPasswordResetTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordResetTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *PasswordResetTestSuite) TestPasswordReset() {
+	t := suite.T()
+
+	user := &models.User{
+		ID:        "trucmush",
+		Email:     "trucmush@wanadoo.fr",
+		FirstName: "Jean-Claude",
+		LastName:  "Trucmush",
+		CreatedAt: time.Now(),
+		Lang:      "en",
+	}
+
+	sender := NewSender(NewPasswordResetMailer(user))
+	sender.SetNoop(true)
+
+	email := sender.newEmail()
+	assert.NotNil(t, email)
+
+	errSend := sender.Send()
+	assert.Nil(t, errSend)
+
+	// check mail generation
+	rawMail, errGen := email.Bytes()
+	assert.Nil(t, errGen)
+
+	// parse generated mail
+	msg, errRead := mail.ReadMessage(bytes.NewBuffer(rawMail))
+	assert.Nil(t, errRead)
+
+	// check headers
+	expectedHeaders := map[string]string{
+		"To":      "Jean-Claude Trucmush <trucmush@wanadoo.fr>",
+		"From":    "test@test.com",
+		"Subject": "Reset your My Service password.",
+	}
+
+	for header, expected := range expectedHeaders {
+		val := msg.Header.Get(header)
+		assert.Equal(t, expected, val)
+	}
+
+	textStr := string(email.Text)
+	assert.Regexp(t, `Forgot your password\?`, textStr)
+	assert.Regexp(t, `Reset Your Password: http://www\.myservice\.bar/password/reset\?token=`, textStr)
+	assert.Regexp(t, `This link expires in one hour\.`, textStr)
+
+	htmlStr := string(email.HTML)
+	assert.Regexp(t, `<a href="http://www\.myservice\.bar/password/reset\?token=[^"]+"[^>]*>Reset password</a>`, htmlStr)
+}
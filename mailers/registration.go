@@ -0,0 +1,89 @@
+package mailers
+
+import (
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/models"
+)
+
+// RegistrationMailer implements the event registration confirmation mailer
+type RegistrationMailer struct {
+	*BaseMailer
+
+	registration *models.Registration
+
+	// Template variables
+	Name       string
+	Email      string
+	EventTitle string
+	EventDate  string
+	EventPlace string
+	SiteName   string
+	SiteUrl    string
+}
+
+// NewRegistrationMailer instanciates a new RegistrationMailer
+func NewRegistrationMailer(registration *models.Registration, event *models.Event, site *models.Site) *RegistrationMailer {
+	result := &RegistrationMailer{
+		BaseMailer: newBaseMailerForLang("registration", site.Lang),
+
+		registration: registration,
+
+		// Template variables
+		Name:       registration.Name,
+		Email:      registration.Email,
+		EventTitle: event.Title,
+		EventPlace: event.Place,
+		SiteName:   site.Name,
+		SiteUrl:    site.BaseUrl(),
+	}
+
+	result.EventDate = result.formatDate(event, site)
+	result.I18n = result.computeI18n()
+
+	return result
+}
+
+// Send triggers mail sending
+func (mailer *RegistrationMailer) Send() error {
+	return NewSender(mailer).Send()
+}
+
+// formatDate formats event start date in site timezone
+func (mailer *RegistrationMailer) formatDate(event *models.Event, site *models.Site) string {
+	startDate := event.StartDate.In(site.TZLocation())
+
+	return mailer.T("event_format_datetime", core.P{
+		"Year":    startDate.Format("2006"),
+		"Month":   mailer.T("month_" + startDate.Format("January")),
+		"Day":     startDate.Format("02"),
+		"Time":    startDate.Format(mailer.T("format_time")),
+		"Weekday": mailer.T("weekday_" + startDate.Format("Monday")),
+	})
+}
+
+// computeI18n computes translations
+func (mailer *RegistrationMailer) computeI18n() map[string]string {
+	return map[string]string{
+		"thanks":     mailer.T("registration_email_thanks", core.P{"Name": mailer.Name}),
+		"registered": mailer.T("registration_email_registered", core.P{"EventTitle": mailer.EventTitle}),
+		"date":       mailer.T("registration_email_date"),
+		"place":      mailer.T("registration_email_place"),
+		"see_you":    mailer.T("registration_email_see_you", core.P{"SiteName": mailer.SiteName}),
+		"visit_site": mailer.T("registration_email_visit_site"),
+		"not_you":    mailer.T("registration_email_not_you"),
+	}
+}
+
+//
+// Mailer interface
+//
+
+// To is part of Mailer interface
+func (mailer *RegistrationMailer) To() string {
+	return mailer.registration.MailAddress()
+}
+
+// Subject is part of Mailer interface
+func (mailer *RegistrationMailer) Subject() string {
+	return mailer.T("registration_email_subject", core.P{"EventTitle": mailer.EventTitle})
+}
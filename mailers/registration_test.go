@@ -0,0 +1,107 @@
+package mailers
+
+import (
+	"bytes"
+	"net/mail"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/helpers"
+	"github.com/aymerick/kowa/models"
+)
+
+type RegistrationTestSuite struct {
+	suite.Suite
+}
+
+// called before all tests
+func (suite *RegistrationTestSuite) SetupSuite() {
+	core.LoadLocales()
+
+	if os.Getenv("KOWA_TEST_EMBED_ASSETS") != "true" {
+		SetTemplatesDir(path.Join(helpers.WorkingDir(), "templates"))
+	}
+
+	viper.Set("smtp_from", "test@test.com")
+	viper.Set("service_name", "My Service")
+	viper.Set("service_url", "http://www.myservice.bar")
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestRegistrationTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistrationTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *RegistrationTestSuite) TestRegistration() {
+	t := suite.T()
+
+	site := &models.Site{
+		ID:   "my_site",
+		Name: "My Site",
+		Lang: "en",
+		TZ:   "Europe/Paris",
+	}
+
+	event := &models.Event{
+		Title:     "Summer Party",
+		Place:     "The Beach",
+		StartDate: time.Date(2015, time.July, 14, 18, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2015, time.July, 14, 23, 0, 0, 0, time.UTC),
+	}
+
+	registration := &models.Registration{
+		Name:  "Jean-Claude Trucmush",
+		Email: "trucmush@wanadoo.fr",
+	}
+
+	sender := NewSender(NewRegistrationMailer(registration, event, site))
+	sender.SetNoop(true)
+
+	email := sender.newEmail()
+	assert.NotNil(t, email)
+
+	errSend := sender.Send()
+	assert.Nil(t, errSend)
+
+	// check mail generation
+	rawMail, errGen := email.Bytes()
+	assert.Nil(t, errGen)
+
+	// parse generated mail
+	msg, errRead := mail.ReadMessage(bytes.NewBuffer(rawMail))
+	assert.Nil(t, errRead)
+
+	// check headers
+	expectedHeaders := map[string]string{
+		"To":      "\"Jean-Claude Trucmush\" <trucmush@wanadoo.fr>",
+		"From":    "test@test.com",
+		"Subject": "Registration confirmed: Summer Party",
+	}
+
+	for header, expected := range expectedHeaders {
+		val := msg.Header.Get(header)
+		assert.Equal(t, expected, val)
+	}
+
+	textStr := string(email.Text)
+	assert.Regexp(t, `Thanks Jean-Claude Trucmush!`, textStr)
+	assert.Regexp(t, `Your registration to Summer Party is confirmed\.`, textStr)
+	assert.Regexp(t, `Tuesday July 14 8:00PM`, textStr)
+	assert.Regexp(t, `The Beach`, textStr)
+
+	htmlStr := string(email.HTML)
+	assert.Regexp(t, `<title>Registration confirmed: Summer Party</title>`, htmlStr)
+	assert.Regexp(t, `Visit website`, htmlStr)
+}
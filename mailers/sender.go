@@ -80,7 +80,7 @@ func (sender *Sender) newEmail() *email.Email {
 		panic(err)
 	}
 
-	return &email.Email{
+	result := &email.Email{
 		To:      []string{sender.mailer.To()},
 		From:    sender.smtpConf.From,
 		Subject: sender.mailer.Subject(),
@@ -88,6 +88,21 @@ func (sender *Sender) newEmail() *email.Email {
 		Text:    []byte(sender.content(tplText)),
 		Headers: textproto.MIMEHeader{},
 	}
+
+	if replyToMailer, ok := sender.mailer.(ReplyToMailer); ok {
+		if replyTo := replyToMailer.ReplyTo(); replyTo != "" {
+			result.Headers.Set("Reply-To", replyTo)
+		}
+	}
+
+	if unsubscribeMailer, ok := sender.mailer.(UnsubscribeMailer); ok {
+		if unsubscribeURL := unsubscribeMailer.UnsubscribeURL(); unsubscribeURL != "" {
+			result.Headers.Set("List-Unsubscribe", fmt.Sprintf("<%s>", unsubscribeURL))
+			result.Headers.Set("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+		}
+	}
+
+	return result
 }
 
 func (sender *Sender) content(tplKind TplKind) string {
@@ -0,0 +1,69 @@
+package mailers
+
+import (
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/kowa/token"
+)
+
+// SubscriptionMailer implements the newsletter subscription confirmation mailer
+type SubscriptionMailer struct {
+	*BaseMailer
+
+	subscriber *models.Subscriber
+
+	// Template variables
+	Email           string
+	ConfirmationUrl string
+	SiteName        string
+	SiteUrl         string
+}
+
+// NewSubscriptionMailer instanciates a new SubscriptionMailer
+func NewSubscriptionMailer(subscriber *models.Subscriber, site *models.Site) *SubscriptionMailer {
+	result := &SubscriptionMailer{
+		BaseMailer: newBaseMailerForLang("subscription", site.Lang),
+
+		subscriber: subscriber,
+
+		// Template variables
+		Email:           subscriber.Email,
+		ConfirmationUrl: token.SubscriptionConfirmationURL(subscriber),
+		SiteName:        site.Name,
+		SiteUrl:         site.BaseUrl(),
+	}
+
+	result.I18n = result.computeI18n()
+
+	return result
+}
+
+// Send triggers mail sending
+func (mailer *SubscriptionMailer) Send() error {
+	return NewSender(mailer).Send()
+}
+
+// computeI18n computes translations
+func (mailer *SubscriptionMailer) computeI18n() map[string]string {
+	return map[string]string{
+		"one_more_step":        mailer.T("subscription_email_one_more_step"),
+		"click_button":         mailer.T("subscription_email_click_button", core.P{"SiteName": mailer.SiteName}),
+		"confirm_subscription": mailer.T("subscription_email_confirm_subscription"),
+		"confirm_link":         mailer.T("subscription_email_confirm_link", core.P{"ConfirmationUrl": mailer.ConfirmationUrl}),
+		"not_you":              mailer.T("subscription_email_not_you"),
+	}
+}
+
+//
+// Mailer interface
+//
+
+// To is part of Mailer interface
+func (mailer *SubscriptionMailer) To() string {
+	return mailer.subscriber.Email
+}
+
+// Subject is part of Mailer interface
+func (mailer *SubscriptionMailer) Subject() string {
+	return mailer.T("subscription_email_subject", core.P{"SiteName": mailer.SiteName})
+}
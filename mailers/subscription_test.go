@@ -0,0 +1,98 @@
+package mailers
+
+import (
+	"bytes"
+	"net/mail"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/helpers"
+	"github.com/aymerick/kowa/models"
+)
+
+type SubscriptionTestSuite struct {
+	suite.Suite
+}
+
+// called before all tests
+func (suite *SubscriptionTestSuite) SetupSuite() {
+	core.LoadLocales()
+
+	if os.Getenv("KOWA_TEST_EMBED_ASSETS") != "true" {
+		SetTemplatesDir(path.Join(helpers.WorkingDir(), "templates"))
+	}
+
+	viper.Set("secret_key", "my_so_secure_key")
+	viper.Set("smtp_from", "test@test.com")
+	viper.Set("service_name", "My Service")
+	viper.Set("service_url", "http://www.myservice.bar")
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestSubscriptionTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *SubscriptionTestSuite) TestSubscription() {
+	t := suite.T()
+
+	site := &models.Site{
+		ID:   "my_site",
+		Name: "My Site",
+		Lang: "en",
+	}
+
+	subscriber := &models.Subscriber{
+		ID:     bson.NewObjectId(),
+		SiteID: site.ID,
+		Email:  "trucmush@wanadoo.fr",
+	}
+
+	sender := NewSender(NewSubscriptionMailer(subscriber, site))
+	sender.SetNoop(true)
+
+	email := sender.newEmail()
+	assert.NotNil(t, email)
+
+	errSend := sender.Send()
+	assert.Nil(t, errSend)
+
+	// check mail generation
+	rawMail, errGen := email.Bytes()
+	assert.Nil(t, errGen)
+
+	// parse generated mail
+	msg, errRead := mail.ReadMessage(bytes.NewBuffer(rawMail))
+	assert.Nil(t, errRead)
+
+	// check headers
+	expectedHeaders := map[string]string{
+		"To":      "trucmush@wanadoo.fr",
+		"From":    "test@test.com",
+		"Subject": "[My Site] Confirm your subscription",
+	}
+
+	for header, expected := range expectedHeaders {
+		val := msg.Header.Get(header)
+		assert.Equal(t, expected, val)
+	}
+
+	textStr := string(email.Text)
+	assert.Regexp(t, `receive news from My Site by email`, textStr)
+	assert.Regexp(t, `Confirm your subscription: http://www\.myservice\.bar/api/public/subscribers/confirm\?token=`, textStr)
+
+	htmlStr := string(email.HTML)
+	assert.Regexp(t, `<a href="http://www\.myservice\.bar/api/public/subscribers/confirm\?token=[^"]+"[^>]*>Confirm subscription</a>`, htmlStr)
+}
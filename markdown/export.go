@@ -0,0 +1,408 @@
+package markdown
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/helpers"
+	"github.com/aymerick/kowa/models"
+)
+
+const (
+	contentDir = "content"
+	staticDir  = "static"
+	imagesDir  = "images"
+	postsDir   = "posts"
+	eventsDir  = "events"
+
+	dateFormat = "2006-01-02"
+)
+
+// internal links syntax in contents, cf. builder
+var internalLinkRegexp = regexp.MustCompile(`\[\[(page|post):([0-9a-fA-F]{24})\]\]`)
+
+// media shortcodes in contents, cf. builder
+var mediaShortcodeRegexp = regexp.MustCompile(`\[\[(image|gallery):([0-9a-fA-F]{24}(?:,[0-9a-fA-F]{24})*)(?::[a-z0-9_]+)?\]\]`)
+
+// escapes image alternative text in Markdown
+var markdownAltReplacer = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+// Exporter exports a site as a Markdown content tree:
+//
+//	content/posts/2015-03-17-my-post.md
+//	content/events/2015-04-01-my-event.md
+//	content/about.md
+//	content/about/history.md
+//	static/images/foo.jpg
+//
+// Each file starts with a YAML front matter. Contents that can't be converted to Markdown are exported as HTML files.
+type Exporter struct {
+	site *models.Site
+	dir  string
+
+	images map[bson.ObjectId]*models.Image
+
+	// matches uploaded urls of images and of their derivatives, by id
+	imageURLs map[bson.ObjectId]*regexp.Regexp
+
+	// exported urls of posts and pages, by id
+	urls map[bson.ObjectId]string
+
+	// taken file paths
+	paths map[string]bool
+}
+
+// frontMatter represents a file front matter, with ordered fields
+type frontMatter struct {
+	fields [][2]string
+}
+
+// NewExporter instanciates a new Exporter
+func NewExporter(site *models.Site, dir string) *Exporter {
+	return &Exporter{
+		site: site,
+		dir:  dir,
+
+		images:    make(map[bson.ObjectId]*models.Image),
+		imageURLs: make(map[bson.ObjectId]*regexp.Regexp),
+		urls:      make(map[bson.ObjectId]string),
+		paths:     make(map[string]bool),
+	}
+}
+
+// Export exports site to output directory
+func (exporter *Exporter) Export() error {
+	if err := exporter.exportImages(); err != nil {
+		return err
+	}
+
+	loc := exporter.site.TZLocation()
+
+	posts := *exporter.site.FindAllPosts()
+	pages := *exporter.site.FindAllPages()
+	events := *exporter.site.FindAllEvents()
+
+	// compute all paths first, to resolve internal links
+	postPaths := make([]string, len(posts))
+	for i, post := range posts {
+		postPaths[i] = exporter.filePath(path.Join(postsDir, datedSlug(postDate(post).In(loc), post.Title, post.ID)))
+		exporter.urls[post.ID] = contentURL(postPaths[i])
+	}
+
+	pagePaths := exporter.pagePaths(pages)
+	for _, page := range pages {
+		exporter.urls[page.ID] = contentURL(pagePaths[page.ID])
+	}
+
+	for i, post := range posts {
+		fm := &frontMatter{}
+		fm.add("title", post.Title)
+		fm.addDate("date", postDate(post), loc)
+		exporter.addCover(fm, post.Cover)
+
+		if !post.Published {
+			fm.addRaw("draft", "true")
+		}
+
+		if err := exporter.writeContent(postPaths[i], fm, post.Format, post.Body); err != nil {
+			return err
+		}
+	}
+
+	for _, page := range pages {
+		fm := &frontMatter{}
+		fm.add("title", page.Title)
+		fm.addDate("date", page.CreatedAt, loc)
+		fm.add("description", page.Tagline)
+		fm.addRaw("weight", fmt.Sprintf("%d", page.Order))
+		exporter.addCover(fm, page.Cover)
+
+		if err := exporter.writeContent(pagePaths[page.ID], fm, page.Format, page.Body); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range events {
+		fm := &frontMatter{}
+		fm.add("title", event.Title)
+		fm.addDate("date", event.StartDate, loc)
+		fm.addDate("endDate", event.EndDate, loc)
+		fm.add("place", event.Place)
+		fm.add("rrule", event.RRule)
+		exporter.addCover(fm, event.Cover)
+
+		filePath := exporter.filePath(path.Join(eventsDir, datedSlug(event.StartDate.In(loc), event.Title, event.ID)))
+
+		if err := exporter.writeContent(filePath, fm, event.Format, event.Body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Copy all site images
+func (exporter *Exporter) exportImages() error {
+	dstDir := filepath.Join(exporter.dir, staticDir, imagesDir)
+
+	for _, img := range *exporter.site.FindAllImages() {
+		srcPath := img.OriginalFilePath()
+
+		if _, err := os.Stat(srcPath); os.IsNotExist(err) {
+			log.Printf("[export] Skipping image with missing original: %v", srcPath)
+			continue
+		}
+
+		helpers.EnsureDirectory(dstDir)
+
+		if err := copyFile(srcPath, filepath.Join(dstDir, img.Path)); err != nil {
+			return err
+		}
+
+		exporter.images[img.ID] = img
+		exporter.imageURLs[img.ID] = img.URLsRegexp()
+	}
+
+	return nil
+}
+
+// Computes pages paths, with children pages in parent directory
+func (exporter *Exporter) pagePaths(pages models.PagesList) map[bson.ObjectId]string {
+	result := make(map[bson.ObjectId]string)
+
+	byID := make(map[bson.ObjectId]*models.Page)
+	for _, page := range pages {
+		byID[page.ID] = page
+	}
+
+	var pagePath func(page *models.Page, visiting map[bson.ObjectId]bool) string
+	pagePath = func(page *models.Page, visiting map[bson.ObjectId]bool) string {
+		if result[page.ID] != "" {
+			return result[page.ID]
+		}
+
+		visiting[page.ID] = true
+
+		dir := ""
+		if parent := byID[page.ParentID]; parent != nil && !visiting[parent.ID] {
+			dir = pagePath(parent, visiting)
+		}
+
+		result[page.ID] = exporter.filePath(path.Join(dir, slug(page.Title, page.ID)))
+
+		return result[page.ID]
+	}
+
+	for _, page := range pages {
+		pagePath(page, make(map[bson.ObjectId]bool))
+	}
+
+	return result
+}
+
+// Returns an available file path for given content path without extension, eg: posts/2015-03-17-my-post
+func (exporter *Exporter) filePath(contentPath string) string {
+	result := contentPath
+
+	for i := 1; exporter.paths[result]; i++ {
+		result = fmt.Sprintf("%s-%d", contentPath, i)
+	}
+
+	exporter.paths[result] = true
+
+	return result
+}
+
+// Add cover image to front matter
+func (exporter *Exporter) addCover(fm *frontMatter, imageID bson.ObjectId) {
+	if img := exporter.images[imageID]; img != nil {
+		fm.add("cover", imageURL(img))
+	}
+}
+
+// Write content file
+func (exporter *Exporter) writeContent(contentPath string, fm *frontMatter, format string, body string) error {
+	body = exporter.rewriteURLs(body, format)
+
+	ext := ".md"
+
+	if format != models.FormatMarkdown {
+		if converted, ok := FromHTML(body); ok {
+			body = converted
+		} else {
+			// keep HTML
+			ext = ".html"
+		}
+	}
+
+	fm.add("format", strings.TrimPrefix(ext, "."))
+
+	var buf bytes.Buffer
+
+	buf.WriteString("---\n")
+	for _, field := range fm.fields {
+		buf.WriteString(field[0] + ": " + field[1] + "\n")
+	}
+	buf.WriteString("---\n\n")
+
+	buf.WriteString(strings.TrimSpace(body))
+	buf.WriteString("\n")
+
+	filePath := filepath.Join(exporter.dir, contentDir, filepath.FromSlash(contentPath+ext))
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filePath, buf.Bytes(), 0644)
+}
+
+// Rewrite uploaded images urls, media shortcodes and internal links. Derivatives are not exported, so their urls
+// are rewritten to the exported original image.
+func (exporter *Exporter) rewriteURLs(body string, format string) string {
+	for id, img := range exporter.images {
+		body = exporter.imageURLs[id].ReplaceAllLiteralString(body, imageURL(img))
+	}
+
+	body = mediaShortcodeRegexp.ReplaceAllStringFunc(body, func(shortcode string) string {
+		matches := mediaShortcodeRegexp.FindStringSubmatch(shortcode)
+
+		var result []string
+
+		for _, id := range strings.Split(matches[2], ",") {
+			if img := exporter.images[bson.ObjectIdHex(id)]; img != nil {
+				result = append(result, imageMarkup(img, format))
+			}
+		}
+
+		if len(result) == 0 {
+			return shortcode
+		}
+
+		if format == models.FormatMarkdown {
+			return strings.Join(result, "\n")
+		}
+
+		return strings.Join(result, "")
+	})
+
+	return internalLinkRegexp.ReplaceAllStringFunc(body, func(link string) string {
+		matches := internalLinkRegexp.FindStringSubmatch(link)
+
+		if result, ok := exporter.urls[bson.ObjectIdHex(matches[2])]; ok {
+			return result
+		}
+
+		return link
+	})
+}
+
+//
+// Front matter
+//
+
+// Add a string field, if not empty
+func (fm *frontMatter) add(name string, value string) {
+	if value == "" {
+		return
+	}
+
+	// a JSON string is a valid YAML double quoted string
+	data, _ := json.Marshal(value)
+
+	fm.addRaw(name, string(data))
+}
+
+// Add a date field, if not zero
+func (fm *frontMatter) addDate(name string, value time.Time, loc *time.Location) {
+	if !value.IsZero() {
+		fm.add(name, value.In(loc).Format(time.RFC3339))
+	}
+}
+
+// Add a raw field
+func (fm *frontMatter) addRaw(name string, value string) {
+	fm.fields = append(fm.fields, [2]string{name, value})
+}
+
+//
+// Helpers
+//
+
+// Returns post date
+func postDate(post *models.Post) time.Time {
+	if post.PublishedAt.IsZero() {
+		return post.CreatedAt
+	}
+
+	return post.PublishedAt
+}
+
+// Computes a slug usable as a file name
+func slug(title string, id bson.ObjectId) string {
+	result := strings.Trim(strings.NewReplacer("/", "-", "#", "").Replace(helpers.Pathify(title)), "-.")
+	if len(result) > 50 {
+		result = strings.Trim(result[:50], "-.")
+	}
+
+	if result == "" {
+		result = id.Hex()
+	}
+
+	return result
+}
+
+// Computes a slug prefixed by a date, eg: 2015-03-17-my-post
+func datedSlug(date time.Time, title string, id bson.ObjectId) string {
+	return date.Format(dateFormat) + "-" + slug(title, id)
+}
+
+// Returns default URL of exported content, eg: posts/2015-03-17-my-post => /posts/2015-03-17-my-post/
+func contentURL(contentPath string) string {
+	return "/" + contentPath + "/"
+}
+
+// Returns URL of exported image
+func imageURL(img *models.Image) string {
+	return "/" + imagesDir + "/" + img.Path
+}
+
+// Returns markup of exported image in given content format
+func imageMarkup(img *models.Image, format string) string {
+	if format == models.FormatMarkdown {
+		return "![" + markdownAltReplacer.Replace(img.Alt) + "](" + imageURL(img) + ")"
+	}
+
+	return `<img src="` + html.EscapeString(imageURL(img)) + `" alt="` + html.EscapeString(img.Alt) + `">`
+}
+
+// Copy file
+func copyFile(srcPath string, dstPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
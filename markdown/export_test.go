@@ -0,0 +1,84 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/models"
+)
+
+type ExportTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestExportTestSuite(t *testing.T) {
+	suite.Run(t, new(ExportTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *ExportTestSuite) TestRewriteURLs() {
+	t := suite.T()
+
+	img := &models.Image{ID: bson.NewObjectId(), SiteID: "site1", Path: "party.jpg"}
+	pageID := bson.NewObjectId()
+
+	exporter := NewExporter(&models.Site{ID: "site1"}, "")
+	exporter.images[img.ID] = img
+	exporter.imageURLs[img.ID] = img.URLsRegexp()
+	exporter.urls[pageID] = "/about/"
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`<img src="` + img.URL() + `">`, `<img src="/images/party.jpg">`},
+		{`<img src="` + img.LargeURL() + `">`, `<img src="/images/party.jpg">`},
+		{`<img src="` + img.ThumbURL() + `">`, `<img src="/images/party.jpg">`},
+		{`<img src="/upload/site1/party.hero-1600x600-fill-top.jpg">`, `<img src="/images/party.jpg">`},
+		{`<source srcset="/upload/site1/party_l.jpg.webp 1024w">`, `<source srcset="/images/party.jpg 1024w">`},
+		{`<img src="/upload/site1/party2.jpg">`, `<img src="/upload/site1/party2.jpg">`},
+		{`[About](` + "[[page:" + pageID.Hex() + "]]" + `)`, `[About](/about/)`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, exporter.rewriteURLs(test.input, models.FormatHTML), test.input)
+	}
+}
+
+func (suite *ExportTestSuite) TestRewriteMediaShortcodes() {
+	t := suite.T()
+
+	img := &models.Image{ID: bson.NewObjectId(), SiteID: "site1", Path: "party.jpg", Alt: "Party [2015]"}
+	other := &models.Image{ID: bson.NewObjectId(), SiteID: "site1", Path: "other.jpg"}
+	missing := bson.NewObjectId()
+
+	exporter := NewExporter(&models.Site{ID: "site1"}, "")
+	for _, i := range []*models.Image{img, other} {
+		exporter.images[i.ID] = i
+		exporter.imageURLs[i.ID] = i.URLsRegexp()
+	}
+
+	tests := []struct {
+		input    string
+		format   string
+		expected string
+	}{
+		{"[[image:" + img.ID.Hex() + "]]", models.FormatMarkdown, `![Party \[2015\]](/images/party.jpg)`},
+		{"[[image:" + img.ID.Hex() + ":small]]", models.FormatHTML, `<img src="/images/party.jpg" alt="Party [2015]">`},
+		{"[[gallery:" + img.ID.Hex() + "," + missing.Hex() + "," + other.ID.Hex() + ":small_fill]]", models.FormatMarkdown, "![Party \\[2015\\]](/images/party.jpg)\n![](/images/other.jpg)"},
+		{"<p>[[gallery:" + other.ID.Hex() + "," + img.ID.Hex() + "]]</p>", models.FormatHTML, `<p><img src="/images/other.jpg" alt=""><img src="/images/party.jpg" alt="Party [2015]"></p>`},
+		{"[[image:" + missing.Hex() + "]]", models.FormatMarkdown, "[[image:" + missing.Hex() + "]]"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, exporter.rewriteURLs(test.input, test.format), test.input)
+	}
+}
@@ -0,0 +1,326 @@
+// Package markdown exports sites as Markdown content trees, usable with static site generators like Hugo or Jekyll.
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	spacesRegexp = regexp.MustCompile(`\s+`)
+
+	// characters to escape in text
+	escaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+)
+
+// elements rendered as blocks
+var blockElements = map[atom.Atom]bool{
+	atom.Address:    true,
+	atom.Article:    true,
+	atom.Aside:      true,
+	atom.Audio:      true,
+	atom.Blockquote: true,
+	atom.Center:     true,
+	atom.Dl:         true,
+	atom.Div:        true,
+	atom.Embed:      true,
+	atom.Figcaption: true,
+	atom.Figure:     true,
+	atom.Footer:     true,
+	atom.Form:       true,
+	atom.H1:         true,
+	atom.H2:         true,
+	atom.H3:         true,
+	atom.H4:         true,
+	atom.H5:         true,
+	atom.H6:         true,
+	atom.Header:     true,
+	atom.Hr:         true,
+	atom.Iframe:     true,
+	atom.Nav:        true,
+	atom.Object:     true,
+	atom.Ol:         true,
+	atom.P:          true,
+	atom.Pre:        true,
+	atom.Section:    true,
+	atom.Table:      true,
+	atom.Ul:         true,
+	atom.Video:      true,
+}
+
+// elements that can't be converted to Markdown
+var unsupportedElements = map[atom.Atom]bool{
+	atom.Audio:  true,
+	atom.Dl:     true,
+	atom.Embed:  true,
+	atom.Form:   true,
+	atom.Iframe: true,
+	atom.Object: true,
+	atom.Table:  true,
+	atom.Video:  true,
+}
+
+// elements silently dropped
+var ignoredElements = map[atom.Atom]bool{
+	atom.Script: true,
+	atom.Style:  true,
+}
+
+// converter converts HTML to Markdown
+type converter struct {
+	// false if some content can't be converted
+	ok bool
+}
+
+// FromHTML converts given HTML to Markdown
+//
+// Returns false if some content can't be converted without loss, eg: tables or iframes.
+func FromHTML(input string) (string, bool) {
+	context := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+
+	nodes, err := html.ParseFragment(strings.NewReader(input), context)
+	if err != nil {
+		return "", false
+	}
+
+	for _, node := range nodes {
+		context.AppendChild(node)
+	}
+
+	c := &converter{ok: true}
+	result := c.children(context)
+
+	if !c.ok {
+		return "", false
+	}
+
+	return result, true
+}
+
+// Converts children of given node
+func (c *converter) children(node *html.Node) string {
+	var blocks []string
+	var inline bytes.Buffer
+
+	flush := func() {
+		if s := cleanInline(inline.String()); s != "" {
+			blocks = append(blocks, s)
+		}
+
+		inline.Reset()
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && blockElements[child.DataAtom] {
+			flush()
+
+			if s := c.block(child); s != "" {
+				blocks = append(blocks, s)
+			}
+		} else {
+			inline.WriteString(c.inline(child))
+		}
+	}
+
+	flush()
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// Converts a block element
+func (c *converter) block(node *html.Node) string {
+	if unsupportedElements[node.DataAtom] {
+		c.ok = false
+		return ""
+	}
+
+	switch node.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(node.Data[1] - '0')
+		return strings.Repeat("#", level) + " " + strings.Replace(cleanInline(c.inlineChildren(node)), "\n", " ", -1)
+
+	case atom.Ul, atom.Ol:
+		return c.list(node)
+
+	case atom.Blockquote:
+		return prefixLines(c.children(node), "> ", ">")
+
+	case atom.Pre:
+		return "```\n" + strings.Trim(textContent(node), "\n") + "\n```"
+
+	case atom.Hr:
+		return "---"
+
+	default:
+		return c.children(node)
+	}
+}
+
+// Converts a list
+func (c *converter) list(node *html.Node) string {
+	var items []string
+
+	i := 1
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || child.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if node.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", i)
+		}
+
+		content := c.children(child)
+		indent := strings.Repeat(" ", len(marker))
+
+		items = append(items, marker+strings.TrimPrefix(prefixLines(content, indent, ""), indent))
+		i++
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// Converts an inline node
+func (c *converter) inline(node *html.Node) string {
+	switch node.Type {
+	case html.TextNode:
+		return escaper.Replace(spacesRegexp.ReplaceAllString(node.Data, " "))
+
+	case html.ElementNode:
+		// handled below
+
+	default:
+		// comments...
+		return ""
+	}
+
+	if ignoredElements[node.DataAtom] {
+		return ""
+	}
+
+	if unsupportedElements[node.DataAtom] {
+		c.ok = false
+		return ""
+	}
+
+	switch node.DataAtom {
+	case atom.Br:
+		return "  \n"
+
+	case atom.Strong, atom.B:
+		return wrapInline(c.inlineChildren(node), "**")
+
+	case atom.Em, atom.I:
+		return wrapInline(c.inlineChildren(node), "*")
+
+	case atom.Code:
+		return wrapInline(textContent(node), "`")
+
+	case atom.A:
+		content := c.inlineChildren(node)
+
+		href := attr(node, "href")
+		if href == "" {
+			return content
+		}
+
+		return "[" + strings.TrimSpace(content) + "](" + href + ")"
+
+	case atom.Img:
+		return "![" + escaper.Replace(attr(node, "alt")) + "](" + attr(node, "src") + ")"
+
+	default:
+		// span, u, font...
+		return c.inlineChildren(node)
+	}
+}
+
+// Converts children of an inline node
+func (c *converter) inlineChildren(node *html.Node) string {
+	var result bytes.Buffer
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && blockElements[child.DataAtom] {
+			// block inside inline element
+			result.WriteString(c.block(child))
+		} else {
+			result.WriteString(c.inline(child))
+		}
+	}
+
+	return result.String()
+}
+
+// Returns value of given node attribute
+func attr(node *html.Node, name string) string {
+	for _, a := range node.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+// Returns raw text content of given node
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+
+	var result bytes.Buffer
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		result.WriteString(textContent(child))
+	}
+
+	return result.String()
+}
+
+// Wraps inline content with given marker, keeping surrounding spaces outside, eg: " foo" => " **foo**"
+func wrapInline(content string, marker string) string {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		return content
+	}
+
+	start := content[:strings.Index(content, trimmed)]
+	end := content[len(start)+len(trimmed):]
+
+	return start + marker + trimmed + marker + end
+}
+
+// Trims lines of inline content
+func cleanInline(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		hardBreak := strings.HasSuffix(line, "  ")
+
+		lines[i] = spacesRegexp.ReplaceAllString(strings.TrimSpace(line), " ")
+		if hardBreak {
+			lines[i] += "  "
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Prefixes all lines of content
+func prefixLines(content string, prefix string, emptyPrefix string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = emptyPrefix
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
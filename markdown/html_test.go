@@ -0,0 +1,62 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type HTMLTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestHTMLTestSuite(t *testing.T) {
+	suite.Run(t, new(HTMLTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *HTMLTestSuite) TestFromHTML() {
+	t := suite.T()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"<p>Hello <strong>world</strong> !</p>", "Hello **world** !"},
+		{"<p>First</p>\n<p>Second <em> line</em></p>", "First\n\nSecond *line*"},
+		{"<h2>Title</h2><p>Text</p>", "## Title\n\nText"},
+		{`<p>See <a href="/about/">about us</a></p>`, "See [about us](/about/)"},
+		{`<p><img src="/images/foo.jpg" alt="Foo"></p>`, "![Foo](/images/foo.jpg)"},
+		{"<p>Line one<br>Line two</p>", "Line one  \nLine two"},
+		{"<ul><li>One</li><li>Two <b>bold</b></li></ul>", "- One\n- Two **bold**"},
+		{"<ol><li><p>One</p><p>More</p></li><li>Two</li></ol>", "1. One\n\n   More\n2. Two"},
+		{"<blockquote><p>Quote</p><p>Again</p></blockquote>", "> Quote\n>\n> Again"},
+		{"<pre><code>a * b\n</code></pre>", "```\na * b\n```"},
+		{"<p>Some <code>x_y</code> and 2*3</p>", "Some `x_y` and 2\\*3"},
+		{"<p>A</p><hr><p>B</p>", "A\n\n---\n\nB"},
+		{"Raw text\n<!-- wp:paragraph --><div><p>In div</p></div>", "Raw text\n\nIn div"},
+	}
+
+	for _, test := range tests {
+		result, ok := FromHTML(test.input)
+
+		assert.True(t, ok, test.input)
+		assert.Equal(t, test.expected, result, test.input)
+	}
+}
+
+func (suite *HTMLTestSuite) TestFromHTMLUnsupported() {
+	t := suite.T()
+
+	_, ok := FromHTML("<p>Table:</p><table><tr><td>1</td></tr></table>")
+	assert.False(t, ok)
+
+	_, ok = FromHTML(`<p><iframe src="https://www.youtube.com/embed/foo"></iframe></p>`)
+	assert.False(t, ok)
+}
@@ -49,6 +49,8 @@ func (session *DBSession) EnsureActivitiesIndexes() {
 	if err != nil {
 		panic(err)
 	}
+
+	ensureTextIndex(session.ActivitiesCol(), "title", "summary", "body")
 }
 
 // FindActivity finds an activity by id
@@ -84,8 +86,8 @@ func (session *DBSession) CreateActivity(activity *Activity) error {
 
 // RemoveImageReferencesFromActivities removes all references to given image from all activities
 func (session *DBSession) RemoveImageReferencesFromActivities(image *Image) error {
-	// @todo
-	return nil
+	_, err := session.ActivitiesCol().UpdateAll(bson.M{"site_id": image.SiteID, "cover": image.ID}, bson.M{"$unset": bson.M{"cover": 1}})
+	return err
 }
 
 //
@@ -0,0 +1,177 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	apiTokensColName = "api_tokens"
+
+	// APITokenScopeRead allows read-only requests
+	APITokenScopeRead = "read"
+
+	// APITokenScopeWrite allows requests that modify content
+	APITokenScopeWrite = "write"
+
+	// apiTokenUsageResolution is the minimum delay between two LastUsedAt updates
+	apiTokenUsageResolution = time.Minute
+)
+
+// APITokenScopes holds all available scopes
+var APITokenScopes = []string{APITokenScopeRead, APITokenScopeWrite}
+
+// APIToken represents a personal access token, used by scripts to call the API
+type APIToken struct {
+	dbSession *DBSession `bson:"-"`
+
+	ID        bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time     `bson:"created_at"    json:"createdAt"`
+	UserID    string        `bson:"user_id"       json:"user"`
+
+	Name       string    `bson:"name"                   json:"name"`
+	Hash       string    `bson:"hash"                   json:"-"`
+	Hint       string    `bson:"hint"                   json:"hint"` // first characters of token, to help user identify it
+	Scopes     []string  `bson:"scopes"                 json:"scopes"`
+	SiteID     string    `bson:"site_id,omitempty"      json:"site,omitempty"`
+	LastUsedAt time.Time `bson:"last_used_at,omitempty" json:"lastUsedAt,omitempty"`
+}
+
+// APITokensList represents a list of API tokens
+type APITokensList []*APIToken
+
+//
+// DBSession
+//
+
+// APITokensCol returns the API tokens collection
+func (session *DBSession) APITokensCol() *mgo.Collection {
+	return session.DB().C(apiTokensColName)
+}
+
+// EnsureAPITokensIndexes ensures indexes on API tokens collection
+func (session *DBSession) EnsureAPITokensIndexes() {
+	index := mgo.Index{
+		Key:        []string{"hash"},
+		Unique:     true,
+		Background: true,
+	}
+
+	err := session.APITokensCol().EnsureIndex(index)
+	if err != nil {
+		panic(err)
+	}
+
+	index = mgo.Index{
+		Key:        []string{"user_id"},
+		Background: true,
+	}
+
+	err = session.APITokensCol().EnsureIndex(index)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// FindAPIToken finds an API token by id
+func (session *DBSession) FindAPIToken(apiTokenID bson.ObjectId) *APIToken {
+	var result APIToken
+
+	if err := session.APITokensCol().FindId(apiTokenID).One(&result); err != nil {
+		return nil
+	}
+
+	result.dbSession = session
+
+	return &result
+}
+
+// FindAPITokenByHash finds an API token by hash
+func (session *DBSession) FindAPITokenByHash(hash string) *APIToken {
+	var result APIToken
+
+	if err := session.APITokensCol().Find(bson.M{"hash": hash}).One(&result); err != nil {
+		return nil
+	}
+
+	result.dbSession = session
+
+	return &result
+}
+
+// CreateAPIToken creates a new API token in database
+// Side effect: 'Id' and 'CreatedAt' fields are set on API token record
+func (session *DBSession) CreateAPIToken(apiToken *APIToken) error {
+	apiToken.ID = bson.NewObjectId()
+	apiToken.CreatedAt = time.Now()
+
+	if err := session.APITokensCol().Insert(apiToken); err != nil {
+		return err
+	}
+
+	apiToken.dbSession = session
+
+	return nil
+}
+
+// RemoveStaleAPITokens removes API tokens that have not been used since given time
+func (session *DBSession) RemoveStaleAPITokens(since time.Time) (int, error) {
+	selector := bson.M{
+		"$or": []bson.M{
+			{"last_used_at": bson.M{"$lt": since}},
+			{"last_used_at": bson.M{"$exists": false}, "created_at": bson.M{"$lt": since}},
+		},
+	}
+
+	info, err := session.APITokensCol().RemoveAll(selector)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Removed, nil
+}
+
+//
+// APIToken
+//
+
+// HasScope returns true if token has given scope
+func (apiToken *APIToken) HasScope(scope string) bool {
+	for _, tokenScope := range apiToken.Scopes {
+		if tokenScope == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowSite returns true if token is allowed to access given site
+func (apiToken *APIToken) AllowSite(siteID string) bool {
+	return (apiToken.SiteID == "") || (apiToken.SiteID == siteID)
+}
+
+// Touch updates LastUsedAt field
+func (apiToken *APIToken) Touch() error {
+	now := time.Now()
+
+	if now.Sub(apiToken.LastUsedAt) < apiTokenUsageResolution {
+		// no need to hammer database
+		return nil
+	}
+
+	if err := apiToken.dbSession.APITokensCol().UpdateId(apiToken.ID, bson.M{"$set": bson.M{"last_used_at": now}}); err != nil {
+		return err
+	}
+
+	apiToken.LastUsedAt = now
+
+	return nil
+}
+
+// Delete deletes API token from database
+func (apiToken *APIToken) Delete() error {
+	return apiToken.dbSession.APITokensCol().RemoveId(apiToken.ID)
+}
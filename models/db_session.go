@@ -76,15 +76,22 @@ func (session *DBSession) Close() {
 
 // EnsureIndexes ensures indexes on all collections
 func (session *DBSession) EnsureIndexes() {
+	session.EnsureAPITokensIndexes()
 	session.EnsureActivitiesIndexes()
 	session.EnsureEventsIndexes()
 	session.EnsureFilesIndexes()
 	session.EnsureImagesIndexes()
+	session.EnsureLocationsIndexes()
 	session.EnsureMembersIndexes()
+	session.EnsureMessagesIndexes()
 	session.EnsurePagesIndexes()
 	session.EnsurePostsIndexes()
+	session.EnsureRedirectsIndexes()
+	session.EnsureRegistrationsIndexes()
 	session.EnsureSitesIndexes()
+	session.EnsureSubscribersIndexes()
 	session.EnsureUsersIndexes()
+	session.EnsureWebhooksIndexes()
 }
 
 // DB returns a database handler
@@ -1,6 +1,10 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"gopkg.in/mgo.v2"
@@ -20,13 +24,47 @@ type Event struct {
 	UpdatedAt time.Time     `bson:"updated_at"    json:"updatedAt"`
 	SiteID    string        `bson:"site_id"       json:"site"`
 
-	StartDate time.Time     `bson:"start_date"      json:"startDate,omitempty"`
-	EndDate   time.Time     `bson:"end_date"        json:"endDate,omitempty"`
-	Title     string        `bson:"title"           json:"title"`
-	Body      string        `bson:"body"            json:"body"`
-	Format    string        `bson:"format"          json:"format"`
-	Place     string        `bson:"place"           json:"place"`
-	Cover     bson.ObjectId `bson:"cover,omitempty" json:"cover,omitempty"`
+	StartDate time.Time     `bson:"start_date"         json:"startDate,omitempty"`
+	EndDate   time.Time     `bson:"end_date"           json:"endDate,omitempty"`
+	Title     string        `bson:"title"              json:"title"`
+	Body      string        `bson:"body"               json:"body"`
+	Format    string        `bson:"format"             json:"format"`
+	Place     string        `bson:"place"              json:"place"`
+	Location  bson.ObjectId `bson:"location,omitempty" json:"location,omitempty"`
+	Cover     bson.ObjectId `bson:"cover,omitempty"    json:"cover,omitempty"`
+
+	Files []bson.ObjectId `bson:"files,omitempty" json:"files,omitempty"` // attached files
+
+	RegistrationEnabled  bool                      `bson:"registration_enabled"            json:"registrationEnabled"`
+	RegistrationCapacity int                       `bson:"registration_capacity"           json:"registrationCapacity"`
+	RegistrationDeadline time.Time                 `bson:"registration_deadline,omitempty" json:"registrationDeadline,omitempty"`
+	RegistrationFields   []*EventRegistrationField `bson:"registration_fields,omitempty"   json:"registrationFields,omitempty"`
+
+	RRule     string                    `bson:"rrule,omitempty"     json:"rrule,omitempty"`
+	ExDates   []time.Time               `bson:"exdates,omitempty"   json:"exDates,omitempty"`
+	Overrides map[string]*EventOverride `bson:"overrides,omitempty" json:"overrides,omitempty"`
+
+	// set on expanded occurrences of recurring events
+	OccurrenceID string `bson:"-" json:"-"`
+}
+
+// EventRegistrationField represents a custom field in event registration form
+type EventRegistrationField struct {
+	Name     string `bson:"name"     json:"name"`
+	Label    string `bson:"label"    json:"label"`
+	Required bool   `bson:"required" json:"required"`
+}
+
+// EventOverride represents modifications of a single occurrence of a recurring event
+type EventOverride struct {
+	// occurrence id, see OccurrenceID()
+	ID string `bson:"id" json:"id"`
+
+	StartDate time.Time `bson:"start_date,omitempty" json:"startDate,omitempty"`
+	EndDate   time.Time `bson:"end_date,omitempty"   json:"endDate,omitempty"`
+	Title     string    `bson:"title,omitempty"      json:"title,omitempty"`
+	Body      string    `bson:"body,omitempty"       json:"body,omitempty"`
+	Place     string    `bson:"place,omitempty"      json:"place,omitempty"`
 }
 
 // EventsList represents a list of events
@@ -52,6 +90,8 @@ func (session *DBSession) EnsureEventsIndexes() {
 	if err != nil {
 		panic(err)
 	}
+
+	ensureTextIndex(session.EventsCol(), "title", "body", "place")
 }
 
 // FindEvent finds an event by id
@@ -87,19 +127,40 @@ func (session *DBSession) CreateEvent(event *Event) error {
 
 // RemoveImageReferencesFromEvents remove all references to given image from all events
 func (session *DBSession) RemoveImageReferencesFromEvents(image *Image) error {
-	// @todo
-	return nil
+	_, err := session.EventsCol().UpdateAll(bson.M{"site_id": image.SiteID, "cover": image.ID}, bson.M{"$unset": bson.M{"cover": 1}})
+	return err
+}
+
+// RemoveFileReferencesFromEvents removes all references to given file from all events
+func (session *DBSession) RemoveFileReferencesFromEvents(file *File) error {
+	_, err := session.EventsCol().UpdateAll(bson.M{"site_id": file.SiteID, "files": file.ID}, bson.M{"$pull": bson.M{"files": file.ID}})
+	return err
 }
 
 //
 // Event
 //
 
+// OccurrenceID computes the id of an occurrence starting at given date, formatted as a RFC 5545 RECURRENCE-ID
+func OccurrenceID(startDate time.Time) string {
+	return startDate.UTC().Format(rruleUntilFormat)
+}
+
+// ParseOccurrenceID returns occurrence start date from given occurrence id
+func ParseOccurrenceID(occurrenceID string) (time.Time, error) {
+	return time.Parse(rruleUntilFormat, occurrenceID)
+}
+
 // FindSite fetch site that event belongs to
 func (event *Event) FindSite() *Site {
 	return event.dbSession.FindSite(event.SiteID)
 }
 
+// FindFiles fetches event attached files
+func (event *Event) FindFiles() *FilesList {
+	return event.dbSession.findSiteFiles(event.SiteID, event.Files)
+}
+
 // FindCover fetches cover from database
 func (event *Event) FindCover() *Image {
 	if event.Cover != "" {
@@ -117,6 +178,15 @@ func (event *Event) FindCover() *Image {
 	return nil
 }
 
+// FindLocation fetches location from database
+func (event *Event) FindLocation() *Location {
+	if event.Location != "" {
+		return event.dbSession.FindLocation(event.Location)
+	}
+
+	return nil
+}
+
 // Delete deletes event from database
 func (event *Event) Delete() error {
 	var err error
@@ -126,9 +196,223 @@ func (event *Event) Delete() error {
 		return err
 	}
 
+	// delete registrations
+	event.dbSession.RegistrationsCol().RemoveAll(bson.M{"event_id": event.ID})
+
 	return nil
 }
 
+// Recurring returns true if event has a recurrence rule
+func (event *Event) Recurring() bool {
+	return event.RRule != ""
+}
+
+// Occurrences returns all event occurrences that start in given time range, with overrides applied
+//
+// Recurrence rule is expanded in given location. A non recurring event is its own unique occurrence.
+func (event *Event) Occurrences(from time.Time, to time.Time, loc *time.Location) []*Event {
+	if !event.Recurring() {
+		return []*Event{event}
+	}
+
+	rule, err := ParseRRule(event.RRule)
+	if err != nil {
+		return []*Event{event}
+	}
+
+	result := []*Event{}
+
+	duration := event.EndDate.Sub(event.StartDate)
+
+	for _, startDate := range rule.Occurrences(event.StartDate.In(loc), from, to) {
+		if event.excluded(startDate) {
+			continue
+		}
+
+		occurrence := *event
+
+		occurrence.OccurrenceID = OccurrenceID(startDate)
+		occurrence.StartDate = startDate
+		occurrence.EndDate = startDate.Add(duration)
+
+		if override := event.Overrides[occurrence.OccurrenceID]; override != nil {
+			override.applyTo(&occurrence)
+		}
+
+		result = append(result, &occurrence)
+	}
+
+	return result
+}
+
+// HasOccurrence returns true if event has an occurrence with given id
+func (event *Event) HasOccurrence(occurrenceID string, loc *time.Location) bool {
+	startDate, err := ParseOccurrenceID(occurrenceID)
+	if err != nil {
+		return false
+	}
+
+	for _, occurrence := range event.Occurrences(startDate, startDate, loc) {
+		if occurrence.OccurrenceID == occurrenceID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckRecurrence returns an error if recurrence rule is invalid, or if an override does not match an occurrence
+func (event *Event) CheckRecurrence(loc *time.Location) error {
+	if !event.Recurring() {
+		if len(event.Overrides) > 0 {
+			return errors.New("A non recurring event can't have occurrence overrides")
+		}
+
+		return nil
+	}
+
+	rule, err := ParseRRule(event.RRule)
+	if err != nil {
+		return fmt.Errorf("Invalid recurrence rule: %v", err)
+	}
+
+	if err := rule.CheckRange(event.StartDate.In(loc)); err != nil {
+		return fmt.Errorf("Invalid recurrence rule: %v", err)
+	}
+
+	for occurrenceID, override := range event.Overrides {
+		if (override == nil) || (override.ID != occurrenceID) || !event.HasOccurrence(occurrenceID, loc) {
+			return fmt.Errorf("Invalid occurrence override: %s", occurrenceID)
+		}
+	}
+
+	return nil
+}
+
+// excluded returns true if occurrence starting at given date is excluded from recurrence
+func (event *Event) excluded(startDate time.Time) bool {
+	for _, exDate := range event.ExDates {
+		if exDate.Equal(startDate) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetOverride sets an occurrence override
+func (event *Event) SetOverride(override *EventOverride) error {
+	if event.Overrides == nil {
+		event.Overrides = make(map[string]*EventOverride)
+	}
+
+	event.Overrides[override.ID] = override
+
+	return event.dbSession.EventsCol().UpdateId(event.ID, bson.M{"$set": bson.D{bson.DocElem{fmt.Sprintf("overrides.%s", override.ID), override}}})
+}
+
+// DeleteOverride deletes an occurrence override
+func (event *Event) DeleteOverride(occurrenceID string) error {
+	delete(event.Overrides, occurrenceID)
+
+	return event.dbSession.EventsCol().UpdateId(event.ID, bson.M{"$unset": bson.D{bson.DocElem{fmt.Sprintf("overrides.%s", occurrenceID), 1}}})
+}
+
+// RegistrationOpen returns true if registration is currently possible for that event
+func (event *Event) RegistrationOpen() bool {
+	if !event.RegistrationEnabled {
+		return false
+	}
+
+	if !event.RegistrationDeadline.IsZero() && time.Now().After(event.RegistrationDeadline) {
+		return false
+	}
+
+	if !event.EndDate.IsZero() && time.Now().After(event.EndDate) {
+		return false
+	}
+
+	return !event.RegistrationFull()
+}
+
+// RegistrationFull returns true if event capacity is reached
+func (event *Event) RegistrationFull() bool {
+	return (event.RegistrationCapacity > 0) && (event.RegistrationsNb() >= event.RegistrationCapacity)
+}
+
+// RegistrationField returns registration field with given name
+func (event *Event) RegistrationField(name string) *EventRegistrationField {
+	for _, field := range event.RegistrationFields {
+		if field.Name == name {
+			return field
+		}
+	}
+
+	return nil
+}
+
+//
+// Event registrations
+//
+
+func (event *Event) registrationsBaseQuery() *mgo.Query {
+	return event.dbSession.RegistrationsCol().Find(bson.M{"event_id": event.ID})
+}
+
+// RegistrationsNb returns the total number of registrations
+func (event *Event) RegistrationsNb() int {
+	result, err := event.registrationsBaseQuery().Count()
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// FindRegistrations fetches registrations to event
+func (event *Event) FindRegistrations(skip int, limit int) *RegistrationsList {
+	result := RegistrationsList{}
+
+	query := event.registrationsBaseQuery().Sort("created_at")
+
+	if skip > 0 {
+		query = query.Skip(skip)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.All(&result); err != nil {
+		panic(err)
+	}
+
+	// inject dbSession in all result items
+	for _, registration := range result {
+		registration.dbSession = event.dbSession
+	}
+
+	return &result
+}
+
+// FindRegistrationByEmail fetches registration to event with given email
+func (event *Event) FindRegistrationByEmail(email string) *Registration {
+	var result Registration
+
+	if err := event.dbSession.RegistrationsCol().Find(bson.M{"event_id": event.ID, "email": email}).One(&result); err != nil {
+		return nil
+	}
+
+	result.dbSession = event.dbSession
+
+	return &result
+}
+
+// FindAllRegistrations fetches all registrations to event
+func (event *Event) FindAllRegistrations() *RegistrationsList {
+	return event.FindRegistrations(0, 0)
+}
+
 // Update updates event in database
 func (event *Event) Update(newEvent *Event) (bool, error) {
 	var set, unset, modifier bson.D
@@ -200,6 +484,17 @@ func (event *Event) Update(newEvent *Event) (bool, error) {
 		}
 	}
 
+	// Location
+	if event.Location != newEvent.Location {
+		event.Location = newEvent.Location
+
+		if event.Location == "" {
+			unset = append(unset, bson.DocElem{"location", 1})
+		} else {
+			set = append(set, bson.DocElem{"location", event.Location})
+		}
+	}
+
 	// Cover
 	if event.Cover != newEvent.Cover {
 		event.Cover = newEvent.Cover
@@ -211,6 +506,81 @@ func (event *Event) Update(newEvent *Event) (bool, error) {
 		}
 	}
 
+	// Files
+	if !reflect.DeepEqual(event.Files, newEvent.Files) {
+		event.Files = newEvent.Files
+
+		if len(event.Files) == 0 {
+			unset = append(unset, bson.DocElem{"files", 1})
+		} else {
+			set = append(set, bson.DocElem{"files", event.Files})
+		}
+	}
+
+	// RRule
+	newRRule := strings.TrimSpace(newEvent.RRule)
+	if event.RRule != newRRule {
+		event.RRule = newRRule
+
+		if event.RRule == "" {
+			unset = append(unset, bson.DocElem{"rrule", 1})
+		} else {
+			set = append(set, bson.DocElem{"rrule", event.RRule})
+		}
+	}
+
+	// ExDates
+	if !reflect.DeepEqual(event.ExDates, newEvent.ExDates) {
+		event.ExDates = newEvent.ExDates
+
+		if len(event.ExDates) == 0 {
+			unset = append(unset, bson.DocElem{"exdates", 1})
+		} else {
+			set = append(set, bson.DocElem{"exdates", event.ExDates})
+		}
+	}
+
+	// RegistrationEnabled
+	if event.RegistrationEnabled != newEvent.RegistrationEnabled {
+		event.RegistrationEnabled = newEvent.RegistrationEnabled
+
+		set = append(set, bson.DocElem{"registration_enabled", event.RegistrationEnabled})
+	}
+
+	// RegistrationCapacity
+	newCapacity := newEvent.RegistrationCapacity
+	if newCapacity < 0 {
+		newCapacity = 0
+	}
+
+	if event.RegistrationCapacity != newCapacity {
+		event.RegistrationCapacity = newCapacity
+
+		set = append(set, bson.DocElem{"registration_capacity", event.RegistrationCapacity})
+	}
+
+	// RegistrationDeadline
+	if event.RegistrationDeadline != newEvent.RegistrationDeadline {
+		event.RegistrationDeadline = newEvent.RegistrationDeadline
+
+		if event.RegistrationDeadline.IsZero() {
+			unset = append(unset, bson.DocElem{"registration_deadline", 1})
+		} else {
+			set = append(set, bson.DocElem{"registration_deadline", event.RegistrationDeadline})
+		}
+	}
+
+	// RegistrationFields
+	if !reflect.DeepEqual(event.RegistrationFields, newEvent.RegistrationFields) {
+		event.RegistrationFields = newEvent.RegistrationFields
+
+		if len(event.RegistrationFields) == 0 {
+			unset = append(unset, bson.DocElem{"registration_fields", 1})
+		} else {
+			set = append(set, bson.DocElem{"registration_fields", event.RegistrationFields})
+		}
+	}
+
 	if len(unset) > 0 {
 		modifier = append(modifier, bson.DocElem{"$unset", unset})
 	}
@@ -228,3 +598,30 @@ func (event *Event) Update(newEvent *Event) (bool, error) {
 
 	return false, nil
 }
+
+//
+// EventOverride
+//
+
+// applyTo applies override on given occurrence
+func (override *EventOverride) applyTo(occurrence *Event) {
+	if !override.StartDate.IsZero() {
+		occurrence.StartDate = override.StartDate
+	}
+
+	if !override.EndDate.IsZero() {
+		occurrence.EndDate = override.EndDate
+	}
+
+	if override.Title != "" {
+		occurrence.Title = override.Title
+	}
+
+	if override.Body != "" {
+		occurrence.Body = override.Body
+	}
+
+	if override.Place != "" {
+		occurrence.Place = override.Place
+	}
+}
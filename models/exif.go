@@ -0,0 +1,322 @@
+package models
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io/ioutil"
+	"os"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// EXIF tags
+	exifOrientationTag = 0x0112
+	exifGPSInfoTag     = 0x8825
+
+	// JPEG markers
+	jpegMarkerSOI  = 0xD8 // start of image
+	jpegMarkerEOI  = 0xD9 // end of image
+	jpegMarkerSOS  = 0xDA // start of scan
+	jpegMarkerAPP1 = 0xE1 // EXIF and XMP segments
+	jpegMarkerAPP2 = 0xE2 // ICC profile segments
+)
+
+var (
+	exifHeader        = []byte("Exif\x00\x00")
+	xmpHeader         = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	xmpExtendedHeader = []byte("http://ns.adobe.com/xmp/extension/\x00")
+	iccHeader         = []byte("ICC_PROFILE\x00")
+
+	// GPS properties namespace prefix in XMP packets
+	xmpGPSProperty = []byte("exif:GPS")
+)
+
+// jpegSegment is a metadata segment of a JPEG file
+type jpegSegment struct {
+	marker  byte
+	payload []byte
+
+	// segment bounds in file, including marker and length
+	start int
+	end   int
+}
+
+// jpegExif holds EXIF data found in a JPEG file
+type jpegExif struct {
+	orientation int
+	gps         bool
+
+	// EXIF segment bounds in file
+	start int
+	end   int
+}
+
+// NormalizeOriginal applies EXIF orientation to original JPEG image and strips its GPS data, from EXIF and XMP
+// metadata. Returns true if original file was modified.
+//
+// When image is rotated, it is re-encoded: only its ICC color profile is kept, all other metadata are dropped.
+func (img *Image) NormalizeOriginal() (bool, error) {
+	if img.Type != "image/jpeg" {
+		return false, nil
+	}
+
+	filePath := img.OriginalFilePath()
+
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return false, err
+	}
+
+	data, modified, err := normalizeJPEG(data)
+	if err != nil || !modified {
+		return false, err
+	}
+
+	if err := ioutil.WriteFile(filePath, data, 0644); err != nil {
+		return false, err
+	}
+
+	// reset memoized original
+	img.original = nil
+
+	if info, err := os.Stat(filePath); err == nil {
+		img.Size = info.Size()
+	}
+
+	return true, nil
+}
+
+// Applies EXIF orientation to given JPEG file content and strips GPS data. Returns false if content is unchanged.
+func normalizeJPEG(data []byte) ([]byte, bool, error) {
+	segments := jpegSegments(data)
+
+	exif := jpegSegmentsExif(segments)
+	if (exif != nil) && (exif.orientation > 1) && (exif.orientation <= 8) {
+		src, err := imaging.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, false, err
+		}
+
+		// encoded image has no metadata at all
+		var buf bytes.Buffer
+		if err := imaging.Encode(&buf, orient(src, exif.orientation), imaging.JPEG); err != nil {
+			return nil, false, err
+		}
+
+		// keep color profile
+		var icc []*jpegSegment
+		for _, segment := range segments {
+			if (segment.marker == jpegMarkerAPP2) && bytes.HasPrefix(segment.payload, iccHeader) {
+				icc = append(icc, segment)
+			}
+		}
+
+		return insertJPEGSegments(buf.Bytes(), data, icc), true, nil
+	}
+
+	// segments to strip, by start position
+	strip := make(map[int]bool)
+
+	if (exif != nil) && exif.gps {
+		strip[exif.start] = true
+	}
+
+	// XMP packet may be split in a main packet and extended segments
+	var extended []*jpegSegment
+	var extendedGPS bool
+
+	for _, segment := range segments {
+		if segment.marker != jpegMarkerAPP1 {
+			continue
+		}
+
+		if bytes.HasPrefix(segment.payload, xmpHeader) && bytes.Contains(segment.payload, xmpGPSProperty) {
+			strip[segment.start] = true
+		} else if bytes.HasPrefix(segment.payload, xmpExtendedHeader) {
+			extended = append(extended, segment)
+			extendedGPS = extendedGPS || bytes.Contains(segment.payload, xmpGPSProperty)
+		}
+	}
+
+	if extendedGPS {
+		for _, segment := range extended {
+			strip[segment.start] = true
+		}
+	}
+
+	if len(strip) == 0 {
+		return data, false, nil
+	}
+
+	return removeJPEGSegments(data, segments, strip), true, nil
+}
+
+// Transforms image according to given EXIF orientation
+func orient(src image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return imaging.FlipH(src)
+	case 3:
+		return imaging.Rotate180(src)
+	case 4:
+		return imaging.FlipV(src)
+	case 5:
+		// transpose
+		return imaging.Rotate90(imaging.FlipH(src))
+	case 6:
+		return imaging.Rotate270(src)
+	case 7:
+		// transverse
+		return imaging.Rotate90(imaging.FlipV(src))
+	case 8:
+		return imaging.Rotate90(src)
+	}
+
+	return src
+}
+
+// Finds EXIF data in given JPEG file content, returns nil if not found
+func parseJPEGExif(data []byte) *jpegExif {
+	return jpegSegmentsExif(jpegSegments(data))
+}
+
+// Returns EXIF data found in given JPEG segments, or nil if not found
+func jpegSegmentsExif(segments []*jpegSegment) *jpegExif {
+	for _, segment := range segments {
+		if (segment.marker == jpegMarkerAPP1) && bytes.HasPrefix(segment.payload, exifHeader) {
+			result := parseTIFFExif(segment.payload[len(exifHeader):])
+			if result != nil {
+				result.start = segment.start
+				result.end = segment.end
+			}
+
+			return result
+		}
+	}
+
+	return nil
+}
+
+// Returns metadata segments of given JPEG file content, ie. all segments found before image data
+func jpegSegments(data []byte) []*jpegSegment {
+	if (len(data) < 4) || (data[0] != 0xFF) || (data[1] != jpegMarkerSOI) {
+		return nil
+	}
+
+	var result []*jpegSegment
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return result
+		}
+
+		marker := data[i+1]
+
+		switch {
+		case marker == 0xFF:
+			// fill byte
+			i++
+			continue
+
+		case (marker == 0x01) || ((marker >= 0xD0) && (marker <= 0xD7)):
+			// standalone markers
+			i += 2
+			continue
+
+		case (marker == jpegMarkerSOS) || (marker == jpegMarkerEOI):
+			// no more metadata
+			return result
+		}
+
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+		if end > len(data) {
+			return result
+		}
+
+		result = append(result, &jpegSegment{marker: marker, payload: data[i+4 : end], start: i, end: end})
+
+		i = end
+	}
+
+	return result
+}
+
+// Returns given JPEG file content without segments whose start position is in strip
+func removeJPEGSegments(data []byte, segments []*jpegSegment, strip map[int]bool) []byte {
+	result := make([]byte, 0, len(data))
+	last := 0
+
+	for _, segment := range segments {
+		if strip[segment.start] {
+			result = append(result, data[last:segment.start]...)
+			last = segment.end
+		}
+	}
+
+	return append(result, data[last:]...)
+}
+
+// Inserts segments of src JPEG file content right after start of image marker of data JPEG file content
+func insertJPEGSegments(data []byte, src []byte, segments []*jpegSegment) []byte {
+	if len(segments) == 0 {
+		return data
+	}
+
+	result := append([]byte{}, data[:2]...)
+	for _, segment := range segments {
+		result = append(result, src[segment.start:segment.end]...)
+	}
+
+	return append(result, data[2:]...)
+}
+
+// Parses orientation and GPS tags in IFD0 of given TIFF data
+func parseTIFFExif(tiff []byte) *jpegExif {
+	if len(tiff) < 8 {
+		return nil
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil
+	}
+
+	if order.Uint16(tiff[2:]) != 42 {
+		return nil
+	}
+
+	result := &jpegExif{}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if (offset < 8) || (offset+2 > len(tiff)) {
+		return result
+	}
+
+	entries := int(order.Uint16(tiff[offset:]))
+
+	for i := 0; i < entries; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+
+		switch order.Uint16(tiff[entry:]) {
+		case exifOrientationTag:
+			// SHORT value, stored in value field
+			result.orientation = int(order.Uint16(tiff[entry+8:]))
+
+		case exifGPSInfoTag:
+			result.gps = true
+		}
+	}
+
+	return result
+}
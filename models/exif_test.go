@@ -0,0 +1,193 @@
+package models
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a JPEG file content with an EXIF segment containing given orientation, and a GPS tag if asked
+func testJPEGWithExif(t *testing.T, order binary.ByteOrder, orientation int, gps bool) []byte {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 2)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := [][3]uint32{{exifOrientationTag, 3, uint32(orientation)}}
+	if gps {
+		entries = append(entries, [3]uint32{exifGPSInfoTag, 4, 0})
+	}
+
+	// TIFF header
+	tiff := make([]byte, 8+2+len(entries)*12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+
+	// IFD0
+	order.PutUint16(tiff[8:], uint16(len(entries)))
+	for i, entry := range entries {
+		offset := 10 + i*12
+		order.PutUint16(tiff[offset:], uint16(entry[0]))
+		order.PutUint16(tiff[offset+2:], uint16(entry[1]))
+		order.PutUint32(tiff[offset+4:], 1)
+
+		if entry[1] == 3 {
+			order.PutUint16(tiff[offset+8:], uint16(entry[2]))
+		} else {
+			order.PutUint32(tiff[offset+8:], entry[2])
+		}
+	}
+
+	segment := append(append([]byte{}, exifHeader...), tiff...)
+
+	header := []byte{0xFF, jpegMarkerAPP1, 0, 0}
+	binary.BigEndian.PutUint16(header[2:], uint16(len(segment)+2))
+
+	data := buf.Bytes()
+
+	result := append([]byte{}, data[:2]...)
+	result = append(result, header...)
+	result = append(result, segment...)
+	return append(result, data[2:]...)
+}
+
+// Returns given JPEG file content with a segment inserted right after start of image marker
+func testInsertJPEGSegment(data []byte, marker byte, payload []byte) []byte {
+	header := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(header[2:], uint16(len(payload)+2))
+
+	result := append([]byte{}, data[:2]...)
+	result = append(result, header...)
+	result = append(result, payload...)
+	return append(result, data[2:]...)
+}
+
+// Returns markers of application segments found in given JPEG file content, with their payload prefixes
+func testJPEGSegments(data []byte) []string {
+	result := []string{}
+	for _, segment := range jpegSegments(data) {
+		if (segment.marker < 0xE0) || (segment.marker > 0xEF) {
+			continue
+		}
+
+		prefix := segment.payload
+		if len(prefix) > 4 {
+			prefix = prefix[:4]
+		}
+
+		result = append(result, fmt.Sprintf("%X:%s", segment.marker, prefix))
+	}
+
+	return result
+}
+
+func TestParseJPEGExif(t *testing.T) {
+	data := testJPEGWithExif(t, binary.LittleEndian, 6, true)
+
+	exif := parseJPEGExif(data)
+	if assert.NotNil(t, exif) {
+		assert.Equal(t, 6, exif.orientation)
+		assert.True(t, exif.gps)
+		assert.Equal(t, 2, exif.start)
+
+		// EXIF segment removed
+		stripped := append(data[:exif.start:exif.start], data[exif.end:]...)
+		assert.Nil(t, parseJPEGExif(stripped))
+
+		_, err := jpeg.Decode(bytes.NewReader(stripped))
+		assert.Nil(t, err)
+	}
+
+	exif = parseJPEGExif(testJPEGWithExif(t, binary.BigEndian, 3, false))
+	if assert.NotNil(t, exif) {
+		assert.Equal(t, 3, exif.orientation)
+		assert.False(t, exif.gps)
+	}
+
+	// no EXIF
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 2)), nil)
+	assert.Nil(t, parseJPEGExif(buf.Bytes()))
+
+	// not a JPEG
+	assert.Nil(t, parseJPEGExif([]byte("GIF89a")))
+}
+
+func TestNormalizeJPEG(t *testing.T) {
+	xmp := func(props string) []byte {
+		return append(append([]byte{}, xmpHeader...), []byte(`<x:xmpmeta><rdf:Description `+props+`/></x:xmpmeta>`)...)
+	}
+	xmpExtended := func(props string) []byte {
+		return append(append([]byte{}, xmpExtendedHeader...), []byte(props)...)
+	}
+	icc := append(append([]byte{}, iccHeader...), 1, 1, 'f', 'a', 'k', 'e')
+
+	noGPS := testJPEGWithExif(t, binary.BigEndian, 1, false)
+
+	// nothing to do
+	result, modified, err := normalizeJPEG(noGPS)
+	assert.Nil(t, err)
+	assert.False(t, modified)
+	assert.Equal(t, noGPS, result)
+
+	result, modified, err = normalizeJPEG(testInsertJPEGSegment(noGPS, jpegMarkerAPP1, xmp(`dc:creator="Bob"`)))
+	assert.Nil(t, err)
+	assert.False(t, modified)
+
+	// GPS in EXIF and XMP
+	data := testInsertJPEGSegment(testJPEGWithExif(t, binary.LittleEndian, 1, true), jpegMarkerAPP1, xmp(`exif:GPSLatitude="48,51.5N" dc:creator="Bob"`))
+	assert.Equal(t, []string{"E1:http", "E1:Exif"}, testJPEGSegments(data))
+
+	result, modified, err = normalizeJPEG(data)
+	assert.Nil(t, err)
+	assert.True(t, modified)
+	assert.Equal(t, []string{}, testJPEGSegments(result))
+
+	_, err = jpeg.Decode(bytes.NewReader(result))
+	assert.Nil(t, err)
+
+	// GPS in extended XMP
+	data = testInsertJPEGSegment(noGPS, jpegMarkerAPP1, xmpExtended(`exif:GPSLongitude="2,21.1E"`))
+	data = testInsertJPEGSegment(data, jpegMarkerAPP1, xmpExtended(`<rdf:Description `))
+	data = testInsertJPEGSegment(data, jpegMarkerAPP1, xmp(`xmpNote:HasExtendedXMP="1234"`))
+
+	result, modified, err = normalizeJPEG(data)
+	assert.Nil(t, err)
+	assert.True(t, modified)
+	assert.Equal(t, []string{"E1:http", "E1:Exif"}, testJPEGSegments(result))
+	assert.Equal(t, xmp(`xmpNote:HasExtendedXMP="1234"`), jpegSegments(result)[0].payload)
+
+	// rotation keeps ICC profile only
+	data = testInsertJPEGSegment(testJPEGWithExif(t, binary.BigEndian, 6, true), jpegMarkerAPP2, icc)
+	data = testInsertJPEGSegment(data, jpegMarkerAPP1, xmp(`exif:GPSLatitude="48,51.5N"`))
+
+	result, modified, err = normalizeJPEG(data)
+	assert.Nil(t, err)
+	assert.True(t, modified)
+	assert.Equal(t, []string{"E2:ICC_"}, testJPEGSegments(result))
+	assert.Equal(t, icc, jpegSegments(result)[0].payload)
+
+	decoded, err := jpeg.Decode(bytes.NewReader(result))
+	if assert.Nil(t, err) {
+		assert.Equal(t, image.Rect(0, 0, 2, 4), decoded.Bounds())
+	}
+}
+
+func TestOrient(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 4, 2))
+
+	assert.Equal(t, image.Rect(0, 0, 4, 2), orient(src, 1).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 4, 2), orient(src, 3).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 2, 4), orient(src, 6).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 2, 4), orient(src, 8).Bounds())
+}
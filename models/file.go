@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"log"
 	"os"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/aymerick/kowa/core"
@@ -15,11 +17,14 @@ const (
 	// FileMembership represents a membership file kind
 	FileMembership = "membership"
 
+	// FileDocument represents a downloadable document file kind
+	FileDocument = "document"
+
 	filesColName = "files"
 )
 
 // FileKinds all possible file kinds
-var FileKinds = []string{FileMembership}
+var FileKinds = []string{FileMembership, FileDocument}
 
 // File represents a file
 type File struct {
@@ -35,6 +40,12 @@ type File struct {
 	Name string `bson:"name" json:"name"` // this is the uploaded file name (may be different from Path)
 	Size int64  `bson:"size" json:"size"`
 	Type string `bson:"type" json:"type"` // content type
+
+	Title       string `bson:"title,omitempty"       json:"title"`
+	Description string `bson:"description,omitempty" json:"description"`
+
+	Folder string   `bson:"folder,omitempty" json:"folder"` // media library folder
+	Tags   []string `bson:"tags,omitempty"   json:"tags"`   // media library tags
 }
 
 // FilesList represents a list of files
@@ -77,6 +88,8 @@ func (session *DBSession) EnsureFilesIndexes() {
 	if err != nil {
 		panic(err)
 	}
+
+	ensureMediaIndexes(session.FilesCol())
 }
 
 // FindFile finds a file by id
@@ -92,6 +105,34 @@ func (session *DBSession) FindFile(fileID bson.ObjectId) *File {
 	return &result
 }
 
+// Fetches files with given ids that belong to given site, in ids order
+func (session *DBSession) findSiteFiles(siteID string, fileIDs []bson.ObjectId) *FilesList {
+	result := FilesList{}
+
+	if len(fileIDs) == 0 {
+		return &result
+	}
+
+	var files FilesList
+	if err := session.FilesCol().Find(bson.M{"site_id": siteID, "_id": bson.M{"$in": fileIDs}}).All(&files); err != nil {
+		panic(err)
+	}
+
+	byID := make(map[bson.ObjectId]*File)
+	for _, f := range files {
+		f.dbSession = session
+		byID[f.ID] = f
+	}
+
+	for _, fileID := range fileIDs {
+		if f := byID[fileID]; f != nil {
+			result = append(result, f)
+		}
+	}
+
+	return &result
+}
+
 // CreateFile creates a new file in database
 func (session *DBSession) CreateFile(f *File) error {
 	now := time.Now()
@@ -137,6 +178,70 @@ func (f *File) Delete() error {
 	return nil
 }
 
+// Update updates file in database
+func (f *File) Update(newFile *File) (bool, error) {
+	var set, unset, modifier bson.D
+
+	// Title
+	if title := strings.TrimSpace(newFile.Title); f.Title != title {
+		f.Title = title
+
+		if f.Title == "" {
+			unset = append(unset, bson.DocElem{"title", 1})
+		} else {
+			set = append(set, bson.DocElem{"title", f.Title})
+		}
+	}
+
+	// Description
+	if f.Description != newFile.Description {
+		f.Description = newFile.Description
+
+		if f.Description == "" {
+			unset = append(unset, bson.DocElem{"description", 1})
+		} else {
+			set = append(set, bson.DocElem{"description", f.Description})
+		}
+	}
+
+	// Folder
+	if folder := NormalizeFolder(newFile.Folder); f.Folder != folder {
+		f.Folder = folder
+
+		if f.Folder == "" {
+			unset = append(unset, bson.DocElem{"folder", 1})
+		} else {
+			set = append(set, bson.DocElem{"folder", f.Folder})
+		}
+	}
+
+	// Tags
+	if tags := NormalizeTags(newFile.Tags); !reflect.DeepEqual(f.Tags, tags) {
+		f.Tags = tags
+
+		if len(f.Tags) == 0 {
+			unset = append(unset, bson.DocElem{"tags", 1})
+		} else {
+			set = append(set, bson.DocElem{"tags", f.Tags})
+		}
+	}
+
+	if (len(set) == 0) && (len(unset) == 0) {
+		return false, nil
+	}
+
+	f.UpdatedAt = time.Now()
+	set = append(set, bson.DocElem{"updated_at", f.UpdatedAt})
+
+	modifier = append(modifier, bson.DocElem{"$set", set})
+
+	if len(unset) > 0 {
+		modifier = append(modifier, bson.DocElem{"$unset", unset})
+	}
+
+	return true, f.dbSession.FilesCol().UpdateId(f.ID, modifier)
+}
+
 // FindSite fetches site that file belongs to
 func (f *File) FindSite() *Site {
 	return f.dbSession.FindSite(f.SiteID)
@@ -147,6 +252,15 @@ func (f *File) FilePath() string {
 	return core.UploadSiteFilePath(f.SiteID, f.Path)
 }
 
+// DisplayTitle returns file title, or uploaded file name if title is not set
+func (f *File) DisplayTitle() string {
+	if f.Title != "" {
+		return f.Title
+	}
+
+	return f.Name
+}
+
 // URL returns file URL
 func (f *File) URL() string {
 	return core.UploadSiteUrlPath(f.SiteID, f.Path)
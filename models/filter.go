@@ -0,0 +1,160 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ListFilter holds filters and sort order applied when listing site records
+type ListFilter struct {
+	Text      string    // full-text search
+	From      time.Time // min date (inclusive)
+	To        time.Time // max date (exclusive)
+	Published *bool     // published state
+	Sort      string    // sort key, prefixed by '-' for descending order, eg: "-date"
+}
+
+// ListSorts maps sort keys to database fields
+type ListSorts map[string]string
+
+// listSpec describes how filters apply to a collection
+type listSpec struct {
+	dateField      string
+	publishedField string // empty if collection has no published state
+	sorts          ListSorts
+	defaultSort    []string
+}
+
+// text search score field
+const textScoreField = "score"
+
+var (
+	// PostsSorts holds valid sort keys for posts
+	PostsSorts = ListSorts{"date": "published_at", "title": "title", "created": "created_at", "updated": "updated_at"}
+
+	// EventsSorts holds valid sort keys for events
+	EventsSorts = ListSorts{"date": "start_date", "title": "title", "created": "created_at", "updated": "updated_at"}
+
+	// PagesSorts holds valid sort keys for pages
+	PagesSorts = ListSorts{"order": "order", "title": "title", "created": "created_at", "updated": "updated_at"}
+
+	// ActivitiesSorts holds valid sort keys for activities
+	ActivitiesSorts = ListSorts{"title": "title", "created": "created_at", "updated": "updated_at"}
+
+	// MembersSorts holds valid sort keys for members
+	MembersSorts = ListSorts{"order": "order", "name": "fullname", "created": "created_at", "updated": "updated_at"}
+)
+
+var (
+	postsListSpec      = &listSpec{"published_at", "published", PostsSorts, []string{"published", "-published_at", "-updated_at"}}
+	eventsListSpec     = &listSpec{"start_date", "", EventsSorts, []string{"-start_date"}}
+	pagesListSpec      = &listSpec{"created_at", "", PagesSorts, []string{"order", "created_at"}}
+	activitiesListSpec = &listSpec{"created_at", "", ActivitiesSorts, []string{"created_at"}}
+	membersListSpec    = &listSpec{"created_at", "", MembersSorts, []string{"order", "created_at"}}
+)
+
+// Valid returns true if given sort key is supported
+func (sorts ListSorts) Valid(key string) bool {
+	if key == "" {
+		return true
+	}
+
+	if key[0] == '-' {
+		key = key[1:]
+	}
+
+	_, ok := sorts[key]
+	return ok
+}
+
+// Returns a filter on published records, or nil
+func publishedFilter(onlyPub bool) *ListFilter {
+	if !onlyPub {
+		return nil
+	}
+
+	published := true
+
+	return &ListFilter{Published: &published}
+}
+
+// ensureTextIndex ensures a full-text index on given collection fields
+//
+// Sites have different languages, so no language specific stemming or stop words are used.
+func ensureTextIndex(col *mgo.Collection, fields ...string) {
+	key := []string{"site_id"}
+	for _, field := range fields {
+		key = append(key, "$text:"+field)
+	}
+
+	index := mgo.Index{
+		Key:             key,
+		DefaultLanguage: "none",
+		Background:      true,
+	}
+
+	if err := col.EnsureIndex(index); err != nil {
+		panic(err)
+	}
+}
+
+// Returns query selector
+func (filter *ListFilter) selector(siteID string, spec *listSpec) bson.M {
+	result := bson.M{"site_id": siteID}
+
+	if filter == nil {
+		return result
+	}
+
+	if filter.Text != "" {
+		result["$text"] = bson.M{"$search": filter.Text}
+	}
+
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		dates := bson.M{}
+
+		if !filter.From.IsZero() {
+			dates["$gte"] = filter.From
+		}
+
+		if !filter.To.IsZero() {
+			dates["$lt"] = filter.To
+		}
+
+		result[spec.dateField] = dates
+	}
+
+	if (filter.Published != nil) && (spec.publishedField != "") {
+		result[spec.publishedField] = *filter.Published
+	}
+
+	return result
+}
+
+// Returns sorted query
+//
+// Full-text search results are sorted by relevance, unless a sort key is specified.
+func (filter *ListFilter) sort(query *mgo.Query, spec *listSpec) *mgo.Query {
+	if filter == nil {
+		return query.Sort(spec.defaultSort...)
+	}
+
+	if filter.Sort != "" {
+		key, order := filter.Sort, ""
+		if key[0] == '-' {
+			key, order = key[1:], "-"
+		}
+
+		if field, ok := spec.sorts[key]; ok {
+			return query.Sort(order+field, "_id")
+		}
+	}
+
+	if filter.Text != "" {
+		return query.Select(bson.M{textScoreField: bson.M{"$meta": "textScore"}}).Sort("$textScore:" + textScoreField)
+	}
+
+	return query.Sort(spec.defaultSort...)
+}
@@ -6,7 +6,11 @@ import (
 	"image"
 	"log"
 	"os"
+	"os/exec"
 	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
@@ -25,6 +29,18 @@ const (
 	derivativeFit  = "fit"
 	derivativeFill = "fill"
 
+	// derivatives generation statuses
+	ImageStatusPending = "pending"
+	ImageStatusReady   = "ready"
+	ImageStatusFailed  = "failed"
+
+	// max size of custom derivatives
+	maxDerivativeSize = 4096
+
+	// WebP variants of derivatives are stored next to them, with that extension appended, eg: foo_l.jpg.webp
+	webpExt     = ".webp"
+	webpQuality = "80"
+
 	// derivatives
 	thumbKind   = "thumb"
 	thumbScale  = derivativeFill
@@ -76,9 +92,35 @@ type Image struct {
 	Size      int64         `bson:"size"          json:"size"`
 	Type      string        `bson:"type"          json:"type"` // jpeg | png
 
+	Status string `bson:"status,omitempty" json:"status"` // derivatives generation status: pending | ready | failed
+
+	Folder string   `bson:"folder,omitempty" json:"folder"` // media library folder, eg: events/2015
+	Tags   []string `bson:"tags,omitempty"   json:"tags"`   // media library tags
+
+	Alt     string `bson:"alt,omitempty"     json:"alt"`     // alternative text
+	Caption string `bson:"caption,omitempty" json:"caption"` // caption
+	Credit  string `bson:"credit,omitempty"  json:"credit"`  // author or copyright notice
+
+	Focus *FocalPoint          `bson:"focus,omitempty" json:"focus,omitempty"` // focal point used by fill derivatives
+	Crops map[string]*CropRect `bson:"crops,omitempty" json:"crops,omitempty"` // manual crops, by derivative kind
+
 	original *image.Image
 }
 
+// FocalPoint represents the point of interest in an image, with coordinates relative to image size (from 0 to 1)
+type FocalPoint struct {
+	X float64 `bson:"x" json:"x"`
+	Y float64 `bson:"y" json:"y"`
+}
+
+// CropRect represents a crop rectangle, with coordinates relative to image size (from 0 to 1)
+type CropRect struct {
+	X      float64 `bson:"x"      json:"x"`
+	Y      float64 `bson:"y"      json:"y"`
+	Width  float64 `bson:"width"  json:"width"`
+	Height float64 `bson:"height" json:"height"`
+}
+
 // ImagesList represents an list of images
 type ImagesList []*Image
 
@@ -102,11 +144,37 @@ type Derivative struct {
 	suffix string
 	width  int
 	height int
+	anchor string // for fill scale only
 }
 
 // Derivatives represents a list of image derivatives
 var Derivatives []*Derivative
 
+// derivatives anchors, with horizontal and vertical crop position
+var derivativeAnchors = map[string][2]float64{
+	"center":       {0.5, 0.5},
+	"top":          {0.5, 0},
+	"bottom":       {0.5, 1},
+	"left":         {0, 0.5},
+	"right":        {1, 0.5},
+	"top_left":     {0, 0},
+	"top_right":    {1, 0},
+	"bottom_left":  {0, 1},
+	"bottom_right": {1, 1},
+}
+
+var (
+	derivativeKindRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+	// escapes glob patterns special characters
+	globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`)
+
+	// custom derivative file suffix, eg: .hero-1600x600-fill-top
+	customDerivativeRegexp = regexp.MustCompile(customDerivativePattern + "$")
+)
+
+const customDerivativePattern = `\.[a-z0-9_]+-[0-9]+x[0-9]+-(fit|fill)(-[a-z_]+)?`
+
 func init() {
 	Derivatives = []*Derivative{
 		&Derivative{
@@ -154,6 +222,58 @@ func init() {
 	}
 }
 
+// NewDerivative instanciates a custom derivative, eg: a derivative defined by a theme
+//
+// The scale is "fit" or "fill", and the anchor is used with "fill" scale to choose the kept part of the image: center,
+// top, bottom, left, right, top_left, top_right, bottom_left or bottom_right.
+func NewDerivative(kind string, scale string, width int, height int, anchor string) (*Derivative, error) {
+	if !derivativeKindRegexp.MatchString(kind) {
+		return nil, fmt.Errorf("Invalid derivative name: %s", kind)
+	}
+
+	if (width <= 0) || (height <= 0) || (width > maxDerivativeSize) || (height > maxDerivativeSize) {
+		return nil, fmt.Errorf("Invalid derivative %s size: %dx%d", kind, width, height)
+	}
+
+	// dimensions are part of file name so that derivative is regenerated when its definition changes
+	suffix := fmt.Sprintf(".%s-%dx%d-%s", kind, width, height, scale)
+
+	switch scale {
+	case derivativeFit:
+		anchor = ""
+
+	case derivativeFill:
+		if anchor == "" {
+			anchor = "center"
+		}
+
+		if _, ok := derivativeAnchors[anchor]; !ok {
+			return nil, fmt.Errorf("Invalid derivative %s anchor: %s", kind, anchor)
+		}
+
+		if anchor != "center" {
+			suffix += "-" + anchor
+		}
+
+	default:
+		return nil, fmt.Errorf("Invalid derivative %s scale: %s", kind, scale)
+	}
+
+	return &Derivative{
+		kind:   kind,
+		scale:  scale,
+		suffix: suffix,
+		width:  width,
+		height: height,
+		anchor: anchor,
+	}, nil
+}
+
+// Kind returns derivative kind
+func (derivative *Derivative) Kind() string {
+	return derivative.kind
+}
+
 // DerivativeForKind returns a derivative definition
 func DerivativeForKind(kind string) *Derivative {
 	for _, derivative := range Derivatives {
@@ -167,14 +287,17 @@ func DerivativeForKind(kind string) *Derivative {
 
 // IsDerivativePath returns true if given path is an image derivative
 func IsDerivativePath(path string) bool {
+	path = strings.TrimSuffix(path, webpExt)
+
+	fileBase := helpers.FileBase(path)
+
 	for _, derivative := range Derivatives {
-		fileBase := helpers.FileBase(path)
 		if strings.HasSuffix(fileBase, derivative.suffix) {
 			return true
 		}
 	}
 
-	return false
+	return customDerivativeRegexp.MatchString(fileBase)
 }
 
 //
@@ -197,6 +320,8 @@ func (session *DBSession) EnsureImagesIndexes() {
 	if err != nil {
 		panic(err)
 	}
+
+	ensureMediaIndexes(session.ImagesCol())
 }
 
 // FindImage finds an image by id
@@ -212,6 +337,21 @@ func (session *DBSession) FindImage(imageID bson.ObjectId) *Image {
 	return &result
 }
 
+// FindPendingImages fetches all images with pending derivatives, in all sites
+func (session *DBSession) FindPendingImages() *ImagesList {
+	result := ImagesList{}
+
+	if err := session.ImagesCol().Find(bson.M{"status": ImageStatusPending}).All(&result); err != nil {
+		panic(err)
+	}
+
+	for _, img := range result {
+		img.dbSession = session
+	}
+
+	return &result
+}
+
 // CreateImage creates a new image in database
 func (session *DBSession) CreateImage(img *Image) error {
 	now := time.Now()
@@ -262,6 +402,16 @@ func (img *Image) Delete() error {
 		if err := os.Remove(derivativePath); err != nil {
 			log.Printf("Failed to delete image: %s", derivativePath)
 		}
+
+		if err := os.Remove(img.WebPFilePath(derivative)); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to delete image: %s", img.WebPFilePath(derivative))
+		}
+	}
+
+	for _, derivativePath := range img.customDerivativesFilePaths() {
+		if err := os.Remove(derivativePath); err != nil {
+			log.Printf("Failed to delete image: %s", derivativePath)
+		}
 	}
 
 	originalPath := img.OriginalFilePath()
@@ -272,6 +422,177 @@ func (img *Image) Delete() error {
 	return nil
 }
 
+// Update updates image metadata, focal point and crops, and returns true if image was modified
+//
+// Derivatives must be regenerated when focal point or crops are modified, cf. CroppingChanged().
+func (img *Image) Update(newImage *Image) (bool, error) {
+	var set, unset, modifier bson.D
+
+	// Alt
+	if img.Alt != newImage.Alt {
+		img.Alt = newImage.Alt
+
+		if img.Alt == "" {
+			unset = append(unset, bson.DocElem{"alt", 1})
+		} else {
+			set = append(set, bson.DocElem{"alt", img.Alt})
+		}
+	}
+
+	// Caption
+	if img.Caption != newImage.Caption {
+		img.Caption = newImage.Caption
+
+		if img.Caption == "" {
+			unset = append(unset, bson.DocElem{"caption", 1})
+		} else {
+			set = append(set, bson.DocElem{"caption", img.Caption})
+		}
+	}
+
+	// Credit
+	if img.Credit != newImage.Credit {
+		img.Credit = newImage.Credit
+
+		if img.Credit == "" {
+			unset = append(unset, bson.DocElem{"credit", 1})
+		} else {
+			set = append(set, bson.DocElem{"credit", img.Credit})
+		}
+	}
+
+	// Folder
+	if folder := NormalizeFolder(newImage.Folder); img.Folder != folder {
+		img.Folder = folder
+
+		if img.Folder == "" {
+			unset = append(unset, bson.DocElem{"folder", 1})
+		} else {
+			set = append(set, bson.DocElem{"folder", img.Folder})
+		}
+	}
+
+	// Tags
+	if tags := NormalizeTags(newImage.Tags); !reflect.DeepEqual(img.Tags, tags) {
+		img.Tags = tags
+
+		if len(img.Tags) == 0 {
+			unset = append(unset, bson.DocElem{"tags", 1})
+		} else {
+			set = append(set, bson.DocElem{"tags", img.Tags})
+		}
+	}
+
+	// Focus
+	if !reflect.DeepEqual(img.Focus, newImage.Focus) {
+		img.Focus = newImage.Focus
+
+		if img.Focus == nil {
+			unset = append(unset, bson.DocElem{"focus", 1})
+		} else {
+			set = append(set, bson.DocElem{"focus", img.Focus})
+		}
+	}
+
+	// Crops
+	if !reflect.DeepEqual(img.Crops, newImage.Crops) {
+		img.Crops = newImage.Crops
+
+		if len(img.Crops) == 0 {
+			unset = append(unset, bson.DocElem{"crops", 1})
+		} else {
+			set = append(set, bson.DocElem{"crops", img.Crops})
+		}
+	}
+
+	if (len(set) == 0) && (len(unset) == 0) {
+		return false, nil
+	}
+
+	img.UpdatedAt = time.Now()
+	set = append(set, bson.DocElem{"updated_at", img.UpdatedAt})
+
+	modifier = append(modifier, bson.DocElem{"$set", set})
+
+	if len(unset) > 0 {
+		modifier = append(modifier, bson.DocElem{"$unset", unset})
+	}
+
+	return true, img.dbSession.ImagesCol().UpdateId(img.ID, modifier)
+}
+
+// CroppingChanged returns true if given image have a different focal point or crops
+func (img *Image) CroppingChanged(newImage *Image) bool {
+	return !reflect.DeepEqual(img.Focus, newImage.Focus) || !reflect.DeepEqual(img.Crops, newImage.Crops)
+}
+
+// ValidateEdits checks image focal point and crops
+func (img *Image) ValidateEdits() error {
+	if (img.Focus != nil) && !(inUnitRange(img.Focus.X) && inUnitRange(img.Focus.Y)) {
+		return fmt.Errorf("Invalid focal point")
+	}
+
+	for kind, rect := range img.Crops {
+		if !derivativeKindRegexp.MatchString(kind) {
+			return fmt.Errorf("Invalid crop derivative: %s", kind)
+		}
+
+		if (rect == nil) || !rect.valid() {
+			return fmt.Errorf("Invalid crop for derivative: %s", kind)
+		}
+	}
+
+	return nil
+}
+
+// Returns true if crop rectangle is inside image
+func (rect *CropRect) valid() bool {
+	return inUnitRange(rect.X) && inUnitRange(rect.Y) && (rect.Width > 0) && (rect.Height > 0) &&
+		(rect.X+rect.Width <= 1) && (rect.Y+rect.Height <= 1)
+}
+
+// Returns crop rectangle in pixels for given image bounds, at least one pixel wide and high
+func (rect *CropRect) pixels(bounds image.Rectangle) image.Rectangle {
+	w := float64(bounds.Dx())
+	h := float64(bounds.Dy())
+
+	x0 := clamp(int(rect.X*w), 0, bounds.Dx()-1)
+	y0 := clamp(int(rect.Y*h), 0, bounds.Dy()-1)
+	x1 := clamp(int((rect.X+rect.Width)*w), x0+1, bounds.Dx())
+	y1 := clamp(int((rect.Y+rect.Height)*h), y0+1, bounds.Dy())
+
+	return image.Rect(x0, y0, x1, y1).Add(bounds.Min)
+}
+
+func inUnitRange(val float64) bool {
+	return (val >= 0) && (val <= 1)
+}
+
+// DeleteCustomDerivatives deletes custom derivatives, so that they are regenerated on demand
+func (img *Image) DeleteCustomDerivatives() {
+	for _, derivativePath := range img.customDerivativesFilePaths() {
+		if err := os.Remove(derivativePath); err != nil {
+			log.Printf("Failed to delete image: %s", derivativePath)
+		}
+	}
+}
+
+// SetStatus updates derivatives generation status
+func (img *Image) SetStatus(status string) error {
+	if err := img.dbSession.ImagesCol().UpdateId(img.ID, bson.M{"$set": bson.M{"status": status}}); err != nil {
+		return err
+	}
+
+	img.Status = status
+	return nil
+}
+
+// DerivativesReady returns true if derivatives were generated
+func (img *Image) DerivativesReady() bool {
+	// images uploaded before derivatives pipeline have no status
+	return (img.Status == "") || (img.Status == ImageStatusReady)
+}
+
 // FindSite fetches site that image belongs to
 func (img *Image) FindSite() *Site {
 	return img.dbSession.FindSite(img.SiteID)
@@ -304,6 +625,18 @@ func (img *Image) URL() string {
 	return core.UploadSiteUrlPath(img.SiteID, img.Path)
 }
 
+// URLsRegexp returns a regexp matching URLs of image original and of all its derivatives, including WebP variants
+func (img *Image) URLsRegexp() *regexp.Regexp {
+	suffixes := []string{customDerivativePattern}
+	for _, derivative := range Derivatives {
+		suffixes = append(suffixes, regexp.QuoteMeta(derivative.suffix))
+	}
+
+	base := core.UploadSiteUrlPath(img.SiteID, helpers.FileBase(img.Path))
+
+	return regexp.MustCompile(regexp.QuoteMeta(base) + "(" + strings.Join(suffixes, "|") + ")?" + regexp.QuoteMeta(path.Ext(img.Path)) + "(" + regexp.QuoteMeta(webpExt) + ")?")
+}
+
 //
 // Derivatives
 //
@@ -378,21 +711,112 @@ func (img *Image) DerivativeURL(derivative *Derivative) string {
 	return core.UploadSiteUrlPath(img.SiteID, img.DerivativePath(derivative))
 }
 
+// WebPFilePath returns file path of given derivative WebP variant
+func (img *Image) WebPFilePath(derivative *Derivative) string {
+	return img.DerivativeFilePath(derivative) + webpExt
+}
+
+// HaveWebP returns true if WebP variants of image derivatives are generated
+//
+// Animated GIF images are not supported.
+func (img *Image) HaveWebP() bool {
+	switch strings.ToLower(path.Ext(img.Path)) {
+	case ".jpg", ".jpeg", ".png":
+		return core.CWebPPath() != ""
+	}
+
+	return false
+}
+
+// Dimensions returns original image width and height
+func (img *Image) Dimensions() (int, int, error) {
+	file, err := os.Open(img.OriginalFilePath())
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	conf, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return conf.Width, conf.Height, nil
+}
+
+// DerivativeWidth returns the width of given derivative, computed from original image dimensions
+func (img *Image) DerivativeWidth(derivative *Derivative) (int, error) {
+	if derivative.scale == derivativeFill {
+		return derivative.width, nil
+	}
+
+	width, height, err := img.Dimensions()
+	if err != nil {
+		return 0, err
+	}
+
+	if (width <= derivative.width) && (height <= derivative.height) {
+		return width, nil
+	}
+
+	// cf. imaging.Fit()
+	if float64(width)/float64(height) > float64(derivative.width)/float64(derivative.height) {
+		return derivative.width, nil
+	}
+
+	return int(float64(derivative.height) * float64(width) / float64(height)), nil
+}
+
 // DerivativeFilePath returns given derivative file path
 func (img *Image) DerivativeFilePath(derivative *Derivative) string {
 	return core.UploadSiteFilePath(img.SiteID, img.DerivativePath(derivative))
 }
 
+// Returns file paths of all generated custom derivatives
+func (img *Image) customDerivativesFilePaths() []string {
+	var result []string
+
+	base := helpers.FileBase(img.Path)
+	ext := path.Ext(img.Path)
+
+	pattern := globEscaper.Replace(core.UploadSiteFilePath(img.SiteID, base)) + ".*" + globEscaper.Replace(ext)
+
+	matches, _ := filepath.Glob(pattern)
+	for _, match := range matches {
+		fileBase := helpers.FileBase(filepath.Base(match))
+
+		// check that file is really a derivative of that image, eg: "foo.bar.hero-1600x600-fill.jpg" is not a derivative of "foo.jpg"
+		if suffix := strings.TrimPrefix(fileBase, base); (suffix != "") && (customDerivativeRegexp.FindString(suffix) == suffix) {
+			result = append(result, match)
+
+			if _, err := os.Stat(match + webpExt); err == nil {
+				result = append(result, match+webpExt)
+			}
+		}
+	}
+
+	return result
+}
+
+// GenerateDerivative generates given derivative and its WebP variant, if not generated yet or if force is true
+func (img *Image) GenerateDerivative(derivative *Derivative, force bool) error {
+	return img.generateDerivative(derivative, force)
+}
+
 func (img *Image) generateDerivative(derivative *Derivative, force bool) error {
 	derivativePath := img.DerivativeFilePath(derivative)
 
 	if !force {
 		// check if derivative already exists
 		if _, err := os.Stat(derivativePath); !os.IsNotExist(err) {
-			return nil
+			return img.generateWebP(derivative, false)
 		}
 	}
 
+	if img.Original() == nil {
+		return fmt.Errorf("Failed to load original image: %v", img.Path)
+	}
+
 	log.Printf("Generating derivative %s: %s", derivative.kind, derivativePath)
 
 	// create derivative
@@ -403,24 +827,116 @@ func (img *Image) generateDerivative(derivative *Derivative, force bool) error {
 		result = imaging.Fit(*img.Original(), derivative.width, derivative.height, imaging.Lanczos)
 
 	case derivativeFill:
-		result = imaging.Thumbnail(*img.Original(), derivative.width, derivative.height, imaging.Lanczos)
+		result = img.fill(derivative)
 
 	default:
 		panic("Insupported derivative scale")
 	}
 
 	// save derivative
-	return imaging.Save(result, derivativePath)
+	if err := imaging.Save(result, derivativePath); err != nil {
+		return err
+	}
+
+	return img.generateWebP(derivative, true)
+}
+
+// Encodes WebP variant of given derivative, if cwebp is available
+func (img *Image) generateWebP(derivative *Derivative, force bool) error {
+	if !img.HaveWebP() {
+		return nil
+	}
+
+	webpPath := img.WebPFilePath(derivative)
+
+	if !force {
+		// check if variant already exists
+		if _, err := os.Stat(webpPath); !os.IsNotExist(err) {
+			return nil
+		}
+	}
+
+	output, err := exec.Command(core.CWebPPath(), "-quiet", "-q", webpQuality, img.DerivativeFilePath(derivative), "-o", webpPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Failed to encode WebP image %s: %v - %s", webpPath, err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// Computes a fill derivative, respecting manual crop and focal point
+func (img *Image) fill(derivative *Derivative) *image.NRGBA {
+	src := *img.Original()
+
+	if rect := img.Crops[derivative.kind]; (rect != nil) && rect.valid() {
+		// manual crop
+		cropped := imaging.Crop(src, rect.pixels(src.Bounds()))
+
+		return imaging.Thumbnail(cropped, derivative.width, derivative.height, imaging.Lanczos)
+	}
+
+	if img.Focus != nil {
+		return fill(src, derivative.width, derivative.height, [2]float64{img.Focus.X, img.Focus.Y}, true)
+	}
+
+	if (derivative.anchor == "") || (derivative.anchor == "center") {
+		return imaging.Thumbnail(src, derivative.width, derivative.height, imaging.Lanczos)
+	}
+
+	return fill(src, derivative.width, derivative.height, derivativeAnchors[derivative.anchor], false)
+}
+
+// Resizes and crops image to fill given size
+//
+// If focus is false, the point is an anchor: eg. {0, 0} keeps top left part of image. Else the point is the focal point
+// of image, and the crop is centered on it as much as possible.
+func fill(src image.Image, width int, height int, point [2]float64, focus bool) *image.NRGBA {
+	srcW := src.Bounds().Dx()
+	srcH := src.Bounds().Dy()
+
+	var resized *image.NRGBA
+
+	if srcW*height > srcH*width {
+		// source is wider
+		resized = imaging.Resize(src, 0, height, imaging.Lanczos)
+	} else {
+		resized = imaging.Resize(src, width, 0, imaging.Lanczos)
+	}
+
+	// crop origin must stay inside resized image
+	maxX := clamp(resized.Bounds().Dx()-width, 0, resized.Bounds().Dx())
+	maxY := clamp(resized.Bounds().Dy()-height, 0, resized.Bounds().Dy())
+
+	var x, y int
+
+	if focus {
+		x = clamp(int(point[0]*float64(resized.Bounds().Dx()))-width/2, 0, maxX)
+		y = clamp(int(point[1]*float64(resized.Bounds().Dy()))-height/2, 0, maxY)
+	} else {
+		x = int(float64(maxX) * point[0])
+		y = int(float64(maxY) * point[1])
+	}
+
+	return imaging.Crop(resized, image.Rect(x, y, x+width, y+height))
+}
+
+// Returns val restricted to [min, max] range, or min if max is lower than min
+func clamp(val int, min int, max int) int {
+	if val > max {
+		val = max
+	}
+
+	if val < min {
+		return min
+	}
+
+	return val
 }
 
 // GenerateDerivatives generates all derivatives that were not generated yet
 func (img *Image) GenerateDerivatives(force bool) error {
 	var err error
 
-	if img.Original() == nil {
-		return fmt.Errorf("Failed to load original image: %v", img.Path)
-	}
-
 	for _, derivative := range Derivatives {
 		if errGen := img.generateDerivative(derivative, force); errGen != nil {
 			err = errGen
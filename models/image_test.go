@@ -0,0 +1,134 @@
+package models
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testRed  = color.NRGBA{255, 0, 0, 255}
+	testBlue = color.NRGBA{0, 0, 255, 255}
+)
+
+// Returns an image with a red left half and a blue right half
+func testHalvesImage(width int, height int) image.Image {
+	result := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			if x < width/2 {
+				result.Set(x, y, testRed)
+			} else {
+				result.Set(x, y, testBlue)
+			}
+		}
+	}
+
+	return result
+}
+
+func TestClamp(t *testing.T) {
+	tests := []struct {
+		val      int
+		min      int
+		max      int
+		expected int
+	}{
+		{5, 0, 10, 5},
+		{-3, 0, 10, 0},
+		{12, 0, 10, 10},
+		{0, 0, 0, 0},
+		{5, 0, -1, 0},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, clamp(test.val, test.min, test.max), "clamp(%d, %d, %d)", test.val, test.min, test.max)
+	}
+}
+
+func TestFillFocus(t *testing.T) {
+	src := testHalvesImage(200, 100)
+
+	tests := []struct {
+		point    [2]float64
+		focus    bool
+		expected color.NRGBA
+	}{
+		// focal point near edges must not crop outside image
+		{[2]float64{0, 0}, true, testRed},
+		{[2]float64{0.1, 0.5}, true, testRed},
+		{[2]float64{1, 1}, true, testBlue},
+		{[2]float64{0.9, 0.5}, true, testBlue},
+		// anchors
+		{[2]float64{0, 0.5}, false, testRed},
+		{[2]float64{1, 0.5}, false, testBlue},
+	}
+
+	for _, test := range tests {
+		result := fill(src, 50, 50, test.point, test.focus)
+
+		if assert.Equal(t, image.Rect(0, 0, 50, 50), result.Bounds(), "point: %v", test.point) {
+			assert.Equal(t, test.expected, result.NRGBAAt(25, 25), "point: %v", test.point)
+		}
+	}
+}
+
+func TestCropRectValid(t *testing.T) {
+	tests := []struct {
+		rect     CropRect
+		expected bool
+	}{
+		{CropRect{0, 0, 1, 1}, true},
+		{CropRect{0.25, 0.25, 0.5, 0.5}, true},
+		{CropRect{0.5, 0.5, 0.5, 0.5}, true},
+		{CropRect{0.999, 0.999, 0.0001, 0.0001}, true},
+		{CropRect{-0.1, 0, 0.5, 0.5}, false},
+		{CropRect{0, 1.1, 0.5, 0.5}, false},
+		{CropRect{0, 0, 0, 0.5}, false},
+		{CropRect{0, 0, 0.5, -0.5}, false},
+		{CropRect{0.6, 0, 0.5, 0.5}, false},
+		{CropRect{0, 0.6, 0.5, 0.5}, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.rect.valid(), "rect: %v", test.rect)
+	}
+}
+
+func TestCropRectPixels(t *testing.T) {
+	bounds := image.Rect(10, 20, 110, 70)
+
+	tests := []struct {
+		rect     CropRect
+		expected image.Rectangle
+	}{
+		{CropRect{0, 0, 1, 1}, image.Rect(10, 20, 110, 70)},
+		{CropRect{0.5, 0.5, 0.5, 0.5}, image.Rect(60, 45, 110, 70)},
+		// rounds to 0 pixel
+		{CropRect{0.5, 0.5, 0.001, 0.001}, image.Rect(60, 45, 61, 46)},
+		{CropRect{0.999, 0.999, 0.001, 0.001}, image.Rect(109, 69, 110, 70)},
+		{CropRect{1, 1, 0, 0}, image.Rect(109, 69, 110, 70)},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.rect.pixels(bounds), "rect: %v", test.rect)
+	}
+}
+
+func TestFillTinyCrop(t *testing.T) {
+	src := testHalvesImage(100, 100)
+
+	img := &Image{
+		original: &src,
+		Crops:    map[string]*CropRect{smallKind: {X: 0.999, Y: 0.5, Width: 0.0005, Height: 0.0005}},
+	}
+
+	result := img.fill(&Derivative{kind: smallKind, scale: derivativeFill, width: 50, height: 50})
+
+	if assert.False(t, result.Bounds().Empty()) {
+		assert.Equal(t, testBlue, result.NRGBAAt(0, 0))
+	}
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	locationsColName = "locations"
+)
+
+// Location represents a location
+type Location struct {
+	dbSession *DBSession `bson:"-"`
+
+	ID        bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time     `bson:"created_at"    json:"createdAt"`
+	UpdatedAt time.Time     `bson:"updated_at"    json:"updatedAt"`
+	SiteID    string        `bson:"site_id"       json:"site"`
+
+	Name      string  `bson:"name"      json:"name"`
+	Address   string  `bson:"address"   json:"address"`
+	Latitude  float64 `bson:"latitude"  json:"latitude"`
+	Longitude float64 `bson:"longitude" json:"longitude"`
+}
+
+// LocationsList represents a list of locations
+type LocationsList []*Location
+
+//
+// DBSession
+//
+
+// LocationsCol returns the locations collection
+func (session *DBSession) LocationsCol() *mgo.Collection {
+	return session.DB().C(locationsColName)
+}
+
+// EnsureLocationsIndexes ensures indexes on locations collection
+func (session *DBSession) EnsureLocationsIndexes() {
+	index := mgo.Index{
+		Key:        []string{"site_id", "name"},
+		Background: true,
+	}
+
+	err := session.LocationsCol().EnsureIndex(index)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// FindLocation finds a location by id
+func (session *DBSession) FindLocation(locationID bson.ObjectId) *Location {
+	var result Location
+
+	if err := session.LocationsCol().FindId(locationID).One(&result); err != nil {
+		return nil
+	}
+
+	result.dbSession = session
+
+	return &result
+}
+
+// CreateLocation creates a new location in database
+// Side effect: 'Id', 'CreatedAt' and 'UpdatedAt' fields are set on location record
+func (session *DBSession) CreateLocation(location *Location) error {
+	location.ID = bson.NewObjectId()
+
+	now := time.Now()
+	location.CreatedAt = now
+	location.UpdatedAt = now
+
+	if err := session.LocationsCol().Insert(location); err != nil {
+		return err
+	}
+
+	location.dbSession = session
+
+	return nil
+}
+
+//
+// Location
+//
+
+// FindSite fetches site that location belongs to
+func (location *Location) FindSite() *Site {
+	return location.dbSession.FindSite(location.SiteID)
+}
+
+// HaveCoordinates returns true if location coordinates are set
+func (location *Location) HaveCoordinates() bool {
+	return (location.Latitude != 0) || (location.Longitude != 0)
+}
+
+// Delete deletes location from database
+func (location *Location) Delete() error {
+	var err error
+
+	// delete from database
+	if err = location.dbSession.LocationsCol().RemoveId(location.ID); err != nil {
+		return err
+	}
+
+	// remove references
+	// @todo Catch and report errors
+	location.dbSession.EventsCol().UpdateAll(bson.M{"location": location.ID}, bson.M{"$unset": bson.M{"location": 1}})
+	location.dbSession.SitesCol().UpdateAll(bson.M{"location": location.ID}, bson.M{"$unset": bson.M{"location": 1}})
+
+	return nil
+}
+
+// Update updates location in database
+func (location *Location) Update(newLocation *Location) (bool, error) {
+	var set, unset, modifier bson.D
+
+	// Name
+	if location.Name != newLocation.Name {
+		location.Name = newLocation.Name
+
+		if location.Name == "" {
+			unset = append(unset, bson.DocElem{"name", 1})
+		} else {
+			set = append(set, bson.DocElem{"name", location.Name})
+		}
+	}
+
+	// Address
+	if location.Address != newLocation.Address {
+		location.Address = newLocation.Address
+
+		if location.Address == "" {
+			unset = append(unset, bson.DocElem{"address", 1})
+		} else {
+			set = append(set, bson.DocElem{"address", location.Address})
+		}
+	}
+
+	// Latitude
+	if location.Latitude != newLocation.Latitude {
+		location.Latitude = newLocation.Latitude
+
+		set = append(set, bson.DocElem{"latitude", location.Latitude})
+	}
+
+	// Longitude
+	if location.Longitude != newLocation.Longitude {
+		location.Longitude = newLocation.Longitude
+
+		set = append(set, bson.DocElem{"longitude", location.Longitude})
+	}
+
+	if len(unset) > 0 {
+		modifier = append(modifier, bson.DocElem{"$unset", unset})
+	}
+
+	if len(set) > 0 {
+		modifier = append(modifier, bson.DocElem{"$set", set})
+	}
+
+	if len(modifier) > 0 {
+		location.UpdatedAt = time.Now()
+		set = append(set, bson.DocElem{"updated_at", location.UpdatedAt})
+
+		return true, location.dbSession.LocationsCol().UpdateId(location.ID, modifier)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,420 @@
+package models
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// MediaUsage represents a reference to an image or a file
+type MediaUsage struct {
+	Kind  string `json:"kind"`  // site | pageSettings | post | event | page | activity | member
+	ID    string `json:"id"`    // id of referencing record
+	Title string `json:"title"` // title of referencing record
+	Field string `json:"field"` // referencing field, eg: cover
+}
+
+// MediaFilter holds filters applied when listing images and files
+type MediaFilter struct {
+	Folder string // only media in that folder (not in sub folders)
+	Tag    string // only media with that tag
+	Kind   string // only files of that kind
+}
+
+// media shortcodes in contents, as resolved by builder
+var mediaShortcodeRegexp = regexp.MustCompile(`(<p>\s*)?\[\[(image|gallery):([0-9a-fA-F]{24}(?:,[0-9a-fA-F]{24})*)(:[a-z0-9_]+)?\]\](\s*</p>)?`)
+
+// mediaRecord is a site record that may reference media
+type mediaRecord struct {
+	ID       bson.ObjectId   `bson:"_id"`
+	Title    string          `bson:"title"`
+	Fullname string          `bson:"fullname"`
+	Cover    bson.ObjectId   `bson:"cover,omitempty"`
+	Photo    bson.ObjectId   `bson:"photo,omitempty"`
+	Files    []bson.ObjectId `bson:"files"`
+	Body     string          `bson:"body"`
+	Desc     string          `bson:"description"`
+	Summary  string          `bson:"summary"`
+
+	Overrides map[string]*EventOverride `bson:"overrides"`
+}
+
+// mediaRecordsCol is a collection of site records that may reference media
+type mediaRecordsCol struct {
+	kind string
+	col  *mgo.Collection
+}
+
+// mediaRefs holds all media references found in site
+type mediaRefs struct {
+	byID   map[bson.ObjectId][]*MediaUsage
+	bodies []*mediaBody
+}
+
+// mediaBody is a text field that may reference media by URL or by id
+type mediaBody struct {
+	usage *MediaUsage
+	text  string
+}
+
+// NormalizeFolder returns a clean folder path, without leading and trailing slashes
+func NormalizeFolder(folder string) string {
+	folder = strings.TrimSpace(folder)
+	if folder == "" {
+		return ""
+	}
+
+	return strings.Trim(path.Clean("/"+folder), "/")
+}
+
+// NormalizeTags returns trimmed, lowercased, deduplicated and sorted tags
+func NormalizeTags(tags []string) []string {
+	var result []string
+
+	seen := make(map[string]bool)
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if (tag != "") && !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// ensure media library indexes on images or files collection
+func ensureMediaIndexes(col *mgo.Collection) {
+	for _, field := range []string{"folder", "tags"} {
+		index := mgo.Index{
+			Key:        []string{"site_id", field},
+			Background: true,
+		}
+
+		if err := col.EnsureIndex(index); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// selector returns the database selector for that filter
+func (filter *MediaFilter) selector(siteID string) bson.M {
+	result := bson.M{"site_id": siteID}
+
+	if filter != nil {
+		if folder := NormalizeFolder(filter.Folder); folder != "" {
+			result["folder"] = folder
+		}
+
+		if tag := strings.ToLower(strings.TrimSpace(filter.Tag)); tag != "" {
+			result["tags"] = tag
+		}
+
+		if filter.Kind != "" {
+			result["kind"] = filter.Kind
+		}
+	}
+
+	return result
+}
+
+//
+// Site media
+//
+
+// ImageUsages returns all references to given image in site
+func (site *Site) ImageUsages(image *Image) []*MediaUsage {
+	return site.findMediaRefs().usages(image.ID, image.URLsRegexp())
+}
+
+// FileUsages returns all references to given file in site
+func (site *Site) FileUsages(file *File) []*MediaUsage {
+	return site.findMediaRefs().usages(file.ID, fileURLRegexp(file))
+}
+
+// FindUnusedImages fetches all site images that are not referenced anywhere
+func (site *Site) FindUnusedImages() *ImagesList {
+	result := ImagesList{}
+
+	refs := site.findMediaRefs()
+
+	for _, image := range *site.FindAllImages() {
+		if len(refs.usages(image.ID, image.URLsRegexp())) == 0 {
+			result = append(result, image)
+		}
+	}
+
+	return &result
+}
+
+// FindUnusedFiles fetches all site files that are not referenced anywhere
+func (site *Site) FindUnusedFiles() *FilesList {
+	result := FilesList{}
+
+	refs := site.findMediaRefs()
+
+	for _, file := range *site.FindAllFiles() {
+		if len(refs.usages(file.ID, fileURLRegexp(file))) == 0 {
+			result = append(result, file)
+		}
+	}
+
+	return &result
+}
+
+// MediaFolders returns all folders used by site images and files
+func (site *Site) MediaFolders() []string {
+	return site.distinctMediaValues("folder")
+}
+
+// MediaTags returns all tags used by site images and files
+func (site *Site) MediaTags() []string {
+	return site.distinctMediaValues("tags")
+}
+
+func (site *Site) distinctMediaValues(field string) []string {
+	var result []string
+
+	seen := make(map[string]bool)
+
+	for _, col := range []*mgo.Collection{site.dbSession.ImagesCol(), site.dbSession.FilesCol()} {
+		var values []string
+
+		if err := col.Find(bson.M{"site_id": site.ID}).Distinct(field, &values); err != nil {
+			panic(err)
+		}
+
+		for _, value := range values {
+			if (value != "") && !seen[value] {
+				seen[value] = true
+				result = append(result, value)
+			}
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// Collects all media references in site settings and site records
+func (site *Site) findMediaRefs() *mediaRefs {
+	result := &mediaRefs{byID: make(map[bson.ObjectId][]*MediaUsage)}
+
+	site.addMediaRefs(result)
+
+	// site records
+	for _, c := range site.mediaRecordsCols() {
+		for _, record := range site.findMediaRecords(c.col) {
+			record.addMediaRefs(result, c.kind)
+		}
+	}
+
+	return result
+}
+
+// Returns collections of site records that may reference media
+func (site *Site) mediaRecordsCols() []mediaRecordsCol {
+	return []mediaRecordsCol{
+		{"post", site.dbSession.PostsCol()},
+		{"event", site.dbSession.EventsCol()},
+		{"page", site.dbSession.PagesCol()},
+		{"activity", site.dbSession.ActivitiesCol()},
+		{"member", site.dbSession.MembersCol()},
+	}
+}
+
+// Fetches all site records in given collection
+func (site *Site) findMediaRecords(col *mgo.Collection) []*mediaRecord {
+	records := []*mediaRecord{}
+
+	query := col.Find(bson.M{"site_id": site.ID}).Select(bson.M{"title": 1, "fullname": 1, "cover": 1, "photo": 1, "files": 1, "body": 1, "description": 1, "summary": 1, "overrides": 1})
+	if err := query.All(&records); err != nil {
+		panic(err)
+	}
+
+	return records
+}
+
+// Removes media shortcodes referencing given image from site settings and site records
+func (site *Site) removeImageShortcodes(image *Image) error {
+	// site settings
+	set := bson.M{}
+
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"description", &site.Description},
+		{"more_desc", &site.MoreDesc},
+		{"join_text", &site.JoinText},
+	}
+
+	for _, field := range fields {
+		if text := removeImageShortcodes(*field.value, image.ID); text != *field.value {
+			*field.value = text
+			set[field.name] = text
+		}
+	}
+
+	if len(set) > 0 {
+		if err := site.dbSession.SitesCol().UpdateId(site.ID, bson.M{"$set": set}); err != nil {
+			return err
+		}
+	}
+
+	// site records
+	for _, c := range site.mediaRecordsCols() {
+		for _, record := range site.findMediaRecords(c.col) {
+			if set := record.removeImageShortcodes(image.ID); len(set) > 0 {
+				if err := c.col.UpdateId(record.ID, bson.M{"$set": set}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Collects media references in site settings
+func (site *Site) addMediaRefs(refs *mediaRefs) {
+	usage := func(field string) *MediaUsage {
+		return &MediaUsage{Kind: "site", ID: site.ID, Title: site.Name, Field: field}
+	}
+
+	refs.add(site.Logo, usage("logo"))
+	refs.add(site.Cover, usage("cover"))
+	refs.add(site.Favicon, usage("favicon"))
+	refs.add(site.Membership, usage("membership"))
+
+	for kind, settings := range site.PageSettings {
+		refs.add(settings.Cover, &MediaUsage{Kind: "pageSettings", ID: kind, Title: settings.Title, Field: "cover"})
+	}
+
+	refs.addBody(site.Description, usage("description"))
+	refs.addBody(site.MoreDesc, usage("moreDesc"))
+	refs.addBody(site.JoinText, usage("joinText"))
+}
+
+// Collects media references in record of given kind
+func (record *mediaRecord) addMediaRefs(refs *mediaRefs, kind string) {
+	title := record.Title
+	if title == "" {
+		title = record.Fullname
+	}
+
+	usage := func(field string) *MediaUsage {
+		return &MediaUsage{Kind: kind, ID: record.ID.Hex(), Title: title, Field: field}
+	}
+
+	refs.add(record.Cover, usage("cover"))
+	refs.add(record.Photo, usage("photo"))
+
+	for _, fileID := range record.Files {
+		refs.add(fileID, usage("files"))
+	}
+
+	refs.addBody(record.Body, usage("body"))
+	refs.addBody(record.Desc, usage("description"))
+	refs.addBody(record.Summary, usage("summary"))
+
+	for _, override := range record.Overrides {
+		refs.addBody(override.Body, usage("overrides"))
+	}
+}
+
+// Returns modifications of record text fields needed to remove media shortcodes referencing given image
+func (record *mediaRecord) removeImageShortcodes(id bson.ObjectId) bson.M {
+	result := bson.M{}
+
+	fields := map[string]string{"body": record.Body, "description": record.Desc, "summary": record.Summary}
+	for key, override := range record.Overrides {
+		fields["overrides."+key+".body"] = override.Body
+	}
+
+	for field, value := range fields {
+		if text := removeImageShortcodes(value, id); text != value {
+			result[field] = text
+		}
+	}
+
+	return result
+}
+
+func (refs *mediaRefs) add(id bson.ObjectId, usage *MediaUsage) {
+	if id != "" {
+		refs.byID[id] = append(refs.byID[id], usage)
+	}
+}
+
+func (refs *mediaRefs) addBody(text string, usage *MediaUsage) {
+	if text != "" {
+		refs.bodies = append(refs.bodies, &mediaBody{usage, text})
+	}
+}
+
+// Returns all usages of media with given id, and with URLs matching given regexp
+func (refs *mediaRefs) usages(id bson.ObjectId, urls *regexp.Regexp) []*MediaUsage {
+	result := []*MediaUsage{}
+
+	result = append(result, refs.byID[id]...)
+
+	for _, body := range refs.bodies {
+		if bodyReferencesMedia(body.text, id, urls) {
+			result = append(result, body.usage)
+		}
+	}
+
+	return result
+}
+
+// Returns true if given text references media with given id, or with an URL matching given regexp
+func bodyReferencesMedia(text string, id bson.ObjectId, urls *regexp.Regexp) bool {
+	return strings.Contains(text, id.Hex()) || ((urls != nil) && urls.MatchString(text))
+}
+
+// Returns given text without references to image with given id in media shortcodes
+//
+// Image is removed from galleries, and shortcodes that do not reference any image anymore are removed.
+func removeImageShortcodes(text string, id bson.ObjectId) string {
+	if !strings.Contains(strings.ToLower(text), id.Hex()) {
+		return text
+	}
+
+	return mediaShortcodeRegexp.ReplaceAllStringFunc(text, func(shortcode string) string {
+		matches := mediaShortcodeRegexp.FindStringSubmatch(shortcode)
+		before, kind, ids, derivative, after := matches[1], matches[2], strings.Split(matches[3], ","), matches[4], matches[5]
+
+		kept := []string{}
+		for _, hex := range ids {
+			if !strings.EqualFold(hex, id.Hex()) {
+				kept = append(kept, hex)
+			}
+		}
+
+		switch {
+		case len(kept) == len(ids):
+			return shortcode
+		case len(kept) > 0:
+			return before + "[[" + kind + ":" + strings.Join(kept, ",") + derivative + "]]" + after
+		case (before != "") && (after != ""):
+			// shortcode was alone in its paragraph
+			return ""
+		default:
+			return before + after
+		}
+	})
+}
+
+// Returns a regexp matching given file URL
+func fileURLRegexp(file *File) *regexp.Regexp {
+	return regexp.MustCompile(regexp.QuoteMeta(file.URL()))
+}
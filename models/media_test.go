@@ -0,0 +1,167 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestNormalizeFolder(t *testing.T) {
+	assert.Equal(t, "", NormalizeFolder(""))
+	assert.Equal(t, "", NormalizeFolder(" / "))
+	assert.Equal(t, "events", NormalizeFolder(" /events/ "))
+	assert.Equal(t, "events/2015", NormalizeFolder("events//2015/"))
+	assert.Equal(t, "2015", NormalizeFolder("events/../../2015"))
+}
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{"party", "summer"}, NormalizeTags([]string{" Summer", "party", "", "summer "}))
+	assert.Nil(t, NormalizeTags(nil))
+}
+
+func newTestMediaRefs() *mediaRefs {
+	return &mediaRefs{byID: make(map[bson.ObjectId][]*MediaUsage)}
+}
+
+func TestMediaRefsUsages(t *testing.T) {
+	img := &Image{ID: bson.NewObjectId(), SiteID: "site1", Path: "party.jpg"}
+	other := &Image{ID: bson.NewObjectId(), SiteID: "site1", Path: "other.jpg"}
+
+	refs := newTestMediaRefs()
+
+	cover := &MediaUsage{Kind: "post", ID: "post1", Field: "cover"}
+	refs.add(img.ID, cover)
+	refs.add("", &MediaUsage{Kind: "post", ID: "post2", Field: "cover"})
+
+	byURL := &MediaUsage{Kind: "page", ID: "page1", Field: "body"}
+	refs.addBody("See ![party](/upload/site1/party.jpg)", byURL)
+
+	byID := &MediaUsage{Kind: "event", ID: "event1", Field: "body"}
+	refs.addBody("Photo: [[image:"+img.ID.Hex()+"]]", byID)
+
+	refs.addBody("", &MediaUsage{Kind: "event", ID: "event2", Field: "body"})
+
+	assert.Equal(t, []*MediaUsage{cover, byURL, byID}, refs.usages(img.ID, img.URLsRegexp()))
+	assert.Equal(t, []*MediaUsage{}, refs.usages(other.ID, other.URLsRegexp()))
+}
+
+func TestBodyReferencesImageDerivatives(t *testing.T) {
+	img := &Image{ID: bson.NewObjectId(), SiteID: "site1", Path: "party.jpg"}
+	urls := img.URLsRegexp()
+
+	assert.True(t, bodyReferencesMedia(`<img src="/upload/site1/party.jpg">`, img.ID, urls))
+	assert.True(t, bodyReferencesMedia(`<img src="/upload/site1/party_s.jpg">`, img.ID, urls))
+	assert.True(t, bodyReferencesMedia(`<source srcset="/upload/site1/party_l.jpg.webp 1024w">`, img.ID, urls))
+	assert.True(t, bodyReferencesMedia(`<img src="http://example.com/upload/site1/party.hero-1600x600-fill-top.jpg">`, img.ID, urls))
+	assert.True(t, bodyReferencesMedia(`<img src="`+img.LargeURL()+`">`, img.ID, urls))
+	assert.True(t, bodyReferencesMedia(`<img src="`+img.ThumbURL()+`">`, img.ID, urls))
+
+	assert.False(t, bodyReferencesMedia(`<img src="/upload/site1/party2.jpg">`, img.ID, urls))
+	assert.False(t, bodyReferencesMedia(`<img src="/upload/site1/party_s.png">`, img.ID, urls))
+	assert.False(t, bodyReferencesMedia(`<img src="/upload/site2/party.jpg">`, img.ID, urls))
+	assert.False(t, bodyReferencesMedia(`<img src="/upload/site1/myparty.jpg">`, img.ID, urls))
+}
+
+func TestBodyReferencesFile(t *testing.T) {
+	file := &File{ID: bson.NewObjectId(), SiteID: "site1", Path: "report (1).pdf"}
+	urls := fileURLRegexp(file)
+
+	assert.True(t, bodyReferencesMedia(`[Report](/upload/site1/report (1).pdf)`, file.ID, urls))
+	assert.False(t, bodyReferencesMedia(`[Report](/upload/site1/report.pdf)`, file.ID, urls))
+}
+
+func TestSiteMediaRefs(t *testing.T) {
+	img := &Image{ID: bson.NewObjectId(), SiteID: "site1", Path: "party.jpg"}
+	logo := bson.NewObjectId()
+
+	site := &Site{
+		ID:          "site1",
+		Name:        "My site",
+		Logo:        logo,
+		Description: "Welcome ![party](/upload/site1/party_s.jpg)",
+		MoreDesc:    "More",
+		JoinText:    "Join us: [[image:" + img.ID.Hex() + "]]",
+	}
+
+	refs := newTestMediaRefs()
+	site.addMediaRefs(refs)
+
+	assert.Equal(t, []*MediaUsage{
+		&MediaUsage{Kind: "site", ID: "site1", Title: "My site", Field: "description"},
+		&MediaUsage{Kind: "site", ID: "site1", Title: "My site", Field: "joinText"},
+	}, refs.usages(img.ID, img.URLsRegexp()))
+
+	assert.Equal(t, []*MediaUsage{
+		&MediaUsage{Kind: "site", ID: "site1", Title: "My site", Field: "logo"},
+	}, refs.usages(logo, nil))
+}
+
+func TestRecordMediaRefs(t *testing.T) {
+	img := &Image{ID: bson.NewObjectId(), SiteID: "site1", Path: "party.jpg"}
+	fileID := bson.NewObjectId()
+	eventID := bson.NewObjectId()
+
+	record := &mediaRecord{
+		ID:    eventID,
+		Title: "Party",
+		Files: []bson.ObjectId{fileID},
+		Overrides: map[string]*EventOverride{
+			"20150601T200000": &EventOverride{Body: "Cancelled"},
+			"20150608T200000": &EventOverride{Body: "Photos: ![](/upload/site1/party_l.jpg.webp)"},
+		},
+	}
+
+	refs := newTestMediaRefs()
+	record.addMediaRefs(refs, "event")
+
+	assert.Equal(t, []*MediaUsage{
+		&MediaUsage{Kind: "event", ID: eventID.Hex(), Title: "Party", Field: "overrides"},
+	}, refs.usages(img.ID, img.URLsRegexp()))
+
+	assert.Equal(t, []*MediaUsage{
+		&MediaUsage{Kind: "event", ID: eventID.Hex(), Title: "Party", Field: "files"},
+	}, refs.usages(fileID, nil))
+}
+
+func TestRemoveImageShortcodes(t *testing.T) {
+	id := bson.ObjectIdHex("5565ad2c4e6f6c4a0c000004")
+	other := "5565ad2c4e6f6c4a0c000005"
+
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"No image", "No image"},
+		{"Photo: [[image:" + other + "]]", "Photo: [[image:" + other + "]]"},
+		{"Photo: [[image:5565ad2c4e6f6c4a0c000004]] here", "Photo:  here"},
+		{"Photo: [[image:5565AD2C4E6F6C4A0C000004:small]]", "Photo: "},
+		{"<p>Before</p><p>[[image:5565ad2c4e6f6c4a0c000004]]</p><p>After</p>", "<p>Before</p><p>After</p>"},
+		{"<p>Photo: [[image:5565ad2c4e6f6c4a0c000004]]</p>", "<p>Photo: </p>"},
+		{"[[gallery:5565ad2c4e6f6c4a0c000004," + other + ":small_fill]]", "[[gallery:" + other + ":small_fill]]"},
+		{"[[gallery:" + other + ",5565ad2c4e6f6c4a0c000004]]", "[[gallery:" + other + "]]"},
+		{"[[gallery:5565ad2c4e6f6c4a0c000004]]", ""},
+		{"![](/upload/site1/5565ad2c4e6f6c4a0c000004.jpg)", "![](/upload/site1/5565ad2c4e6f6c4a0c000004.jpg)"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.output, removeImageShortcodes(test.input, id), "input: %s", test.input)
+	}
+}
+
+func TestRecordRemoveImageShortcodes(t *testing.T) {
+	id := bson.NewObjectId()
+	shortcode := "[[image:" + id.Hex() + "]]"
+
+	record := &mediaRecord{
+		Body:    "Body " + shortcode,
+		Summary: "Summary",
+		Overrides: map[string]*EventOverride{
+			"20150601T200000": &EventOverride{Body: "Cancelled"},
+			"20150608T200000": &EventOverride{Body: "Photos: " + shortcode},
+		},
+	}
+
+	assert.Equal(t, bson.M{"body": "Body ", "overrides.20150608T200000.body": "Photos: "}, record.removeImageShortcodes(id))
+	assert.Equal(t, bson.M{}, record.removeImageShortcodes(bson.NewObjectId()))
+}
@@ -50,6 +50,8 @@ func (session *DBSession) EnsureMembersIndexes() {
 	if err != nil {
 		panic(err)
 	}
+
+	ensureTextIndex(session.MembersCol(), "fullname", "role", "description")
 }
 
 // FindMember finds member by id
@@ -85,8 +87,8 @@ func (session *DBSession) CreateMember(member *Member) error {
 
 // RemoveImageReferencesFromMembers removes all references to given image from all members
 func (session *DBSession) RemoveImageReferencesFromMembers(image *Image) error {
-	// @todo
-	return nil
+	_, err := session.MembersCol().UpdateAll(bson.M{"site_id": image.SiteID, "photo": image.ID}, bson.M{"$unset": bson.M{"photo": 1}})
+	return err
 }
 
 //
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"net/mail"
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	messagesColName = "messages"
+)
+
+// Message represents a message sent by a visitor with site contact form
+type Message struct {
+	dbSession *DBSession `bson:"-"`
+
+	ID        bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time     `bson:"created_at"    json:"createdAt"`
+	SiteID    string        `bson:"site_id"       json:"site"`
+
+	Name    string `bson:"name"    json:"name"`
+	Email   string `bson:"email"   json:"email"`
+	Subject string `bson:"subject" json:"subject"`
+	Body    string `bson:"body"    json:"body"`
+	Read    bool   `bson:"read"    json:"read"`
+}
+
+// MessagesList represents a list of messages
+type MessagesList []*Message
+
+//
+// DBSession
+//
+
+// MessagesCol returns the messages collection
+func (session *DBSession) MessagesCol() *mgo.Collection {
+	return session.DB().C(messagesColName)
+}
+
+// EnsureMessagesIndexes ensures indexes on messages collection
+func (session *DBSession) EnsureMessagesIndexes() {
+	index := mgo.Index{
+		Key:        []string{"site_id", "-created_at"},
+		Background: true,
+	}
+
+	err := session.MessagesCol().EnsureIndex(index)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// FindMessage finds a message by id
+func (session *DBSession) FindMessage(messageID bson.ObjectId) *Message {
+	var result Message
+
+	if err := session.MessagesCol().FindId(messageID).One(&result); err != nil {
+		return nil
+	}
+
+	result.dbSession = session
+
+	return &result
+}
+
+// CreateMessage creates a new message in database
+// Side effect: 'Id' and 'CreatedAt' fields are set on message record
+func (session *DBSession) CreateMessage(message *Message) error {
+	message.ID = bson.NewObjectId()
+	message.CreatedAt = time.Now()
+
+	if err := session.MessagesCol().Insert(message); err != nil {
+		return err
+	}
+
+	message.dbSession = session
+
+	return nil
+}
+
+//
+// Message
+//
+
+// FindSite fetches site that message belongs to
+func (message *Message) FindSite() *Site {
+	return message.dbSession.FindSite(message.SiteID)
+}
+
+// MailAddress returns the sender email address, formatted for mail headers
+func (message *Message) MailAddress() string {
+	addr := mail.Address{
+		Name:    message.Name,
+		Address: message.Email,
+	}
+
+	return addr.String()
+}
+
+// SetRead sets the Read flag
+func (message *Message) SetRead(value bool) error {
+	if message.Read == value {
+		return nil
+	}
+
+	if err := message.dbSession.MessagesCol().UpdateId(message.ID, bson.M{"$set": bson.M{"read": value}}); err != nil {
+		return err
+	}
+
+	message.Read = value
+	return nil
+}
+
+// Delete deletes message from database
+func (message *Message) Delete() error {
+	return message.dbSession.MessagesCol().RemoveId(message.ID)
+}
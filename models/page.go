@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"reflect"
 	"time"
 
 	"gopkg.in/mgo.v2"
@@ -26,12 +28,19 @@ type Page struct {
 	Format  string        `bson:"format"          json:"format"`
 	Cover   bson.ObjectId `bson:"cover,omitempty" json:"cover,omitempty"`
 
-	InNavBar bool `bson:"in_nav_bar" json:"inNavBar"`
+	Files []bson.ObjectId `bson:"files,omitempty" json:"files,omitempty"` // attached files
+
+	ParentID bson.ObjectId `bson:"parent,omitempty" json:"parent,omitempty"`
+	Order    int           `bson:"order"            json:"order"`
+	InNavBar bool          `bson:"in_nav_bar"       json:"inNavBar"`
 }
 
 // PagesList represents a list of pages
 type PagesList []*Page
 
+// ErrPageNotFound is returned when updating a page that does not exist in site
+var ErrPageNotFound = errors.New("Page not found")
+
 //
 // DBSession
 //
@@ -52,6 +61,8 @@ func (session *DBSession) EnsurePagesIndexes() {
 	if err != nil {
 		panic(err)
 	}
+
+	ensureTextIndex(session.PagesCol(), "title", "tagline", "body")
 }
 
 // FindPage finds a page by id
@@ -85,10 +96,30 @@ func (session *DBSession) CreatePage(page *Page) error {
 	return nil
 }
 
+// UpdatePagesParent moves all children of given parent page to a new parent page
+func (session *DBSession) UpdatePagesParent(parentID bson.ObjectId, newParentID bson.ObjectId) error {
+	var modifier bson.M
+
+	if newParentID == "" {
+		modifier = bson.M{"$unset": bson.M{"parent": 1}}
+	} else {
+		modifier = bson.M{"$set": bson.M{"parent": newParentID}}
+	}
+
+	_, err := session.PagesCol().UpdateAll(bson.M{"parent": parentID}, modifier)
+	return err
+}
+
 // RemoveImageReferencesFromPages removes all references to given image from all pages
 func (session *DBSession) RemoveImageReferencesFromPages(image *Image) error {
-	// @todo
-	return nil
+	_, err := session.PagesCol().UpdateAll(bson.M{"site_id": image.SiteID, "cover": image.ID}, bson.M{"$unset": bson.M{"cover": 1}})
+	return err
+}
+
+// RemoveFileReferencesFromPages removes all references to given file from all pages
+func (session *DBSession) RemoveFileReferencesFromPages(file *File) error {
+	_, err := session.PagesCol().UpdateAll(bson.M{"site_id": file.SiteID, "files": file.ID}, bson.M{"$pull": bson.M{"files": file.ID}})
+	return err
 }
 
 //
@@ -100,6 +131,40 @@ func (page *Page) FindSite() *Site {
 	return page.dbSession.FindSite(page.SiteID)
 }
 
+// FindParent fetches parent page from database
+func (page *Page) FindParent() *Page {
+	if page.ParentID != "" {
+		return page.dbSession.FindPage(page.ParentID)
+	}
+
+	return nil
+}
+
+// CanHaveParent returns true if given page can be set as parent of that page
+func (page *Page) CanHaveParent(parent *Page) bool {
+	if parent.SiteID != page.SiteID {
+		return false
+	}
+
+	// prevent cycles
+	visited := map[bson.ObjectId]bool{}
+
+	for cur := parent; cur != nil; cur = cur.FindParent() {
+		if (cur.ID == page.ID) || visited[cur.ID] {
+			return false
+		}
+
+		visited[cur.ID] = true
+	}
+
+	return true
+}
+
+// FindFiles fetches page attached files
+func (page *Page) FindFiles() *FilesList {
+	return page.dbSession.findSiteFiles(page.SiteID, page.Files)
+}
+
 // FindCover fetches cover from database
 func (page *Page) FindCover() *Image {
 	if page.Cover != "" {
@@ -126,7 +191,12 @@ func (page *Page) Delete() error {
 		return err
 	}
 
-	return nil
+	// attach children to grand parent
+	if err = page.dbSession.UpdatePagesParent(page.ID, page.ParentID); err != nil {
+		return err
+	}
+
+	return page.dbSession.RemoveRedirectsTo(page.ID)
 }
 
 // Update page in database
@@ -189,6 +259,35 @@ func (page *Page) Update(newPage *Page) (bool, error) {
 		}
 	}
 
+	// Files
+	if !reflect.DeepEqual(page.Files, newPage.Files) {
+		page.Files = newPage.Files
+
+		if len(page.Files) == 0 {
+			unset = append(unset, bson.DocElem{"files", 1})
+		} else {
+			set = append(set, bson.DocElem{"files", page.Files})
+		}
+	}
+
+	// ParentID
+	if page.ParentID != newPage.ParentID {
+		page.ParentID = newPage.ParentID
+
+		if page.ParentID == "" {
+			unset = append(unset, bson.DocElem{"parent", 1})
+		} else {
+			set = append(set, bson.DocElem{"parent", page.ParentID})
+		}
+	}
+
+	// Order
+	if page.Order != newPage.Order {
+		page.Order = newPage.Order
+
+		set = append(set, bson.DocElem{"order", page.Order})
+	}
+
 	// InNavBar
 	if page.InNavBar != newPage.InNavBar {
 		page.InNavBar = newPage.InNavBar
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"reflect"
 	"time"
 
 	"gopkg.in/mgo.v2"
@@ -26,6 +27,10 @@ type Post struct {
 	Body        string        `bson:"body"            json:"body"`
 	Format      string        `bson:"format"          json:"format"`
 	Cover       bson.ObjectId `bson:"cover,omitempty" json:"cover,omitempty"`
+
+	Files []bson.ObjectId `bson:"files,omitempty" json:"files,omitempty"` // attached files
+
+	NotifiedAt time.Time `bson:"notified_at,omitempty" json:"notifiedAt,omitempty"` // newsletter sending date
 }
 
 // PostsList represents a list of posts
@@ -51,6 +56,8 @@ func (session *DBSession) EnsurePostsIndexes() {
 	if err != nil {
 		panic(err)
 	}
+
+	ensureTextIndex(session.PostsCol(), "title", "body")
 }
 
 // FindPost finds a post by id
@@ -86,8 +93,14 @@ func (session *DBSession) CreatePost(post *Post) error {
 
 // RemoveImageReferencesFromPosts removes all references to given image from all posts
 func (session *DBSession) RemoveImageReferencesFromPosts(image *Image) error {
-	// @todo
-	return nil
+	_, err := session.PostsCol().UpdateAll(bson.M{"site_id": image.SiteID, "cover": image.ID}, bson.M{"$unset": bson.M{"cover": 1}})
+	return err
+}
+
+// RemoveFileReferencesFromPosts removes all references to given file from all posts
+func (session *DBSession) RemoveFileReferencesFromPosts(file *File) error {
+	_, err := session.PostsCol().UpdateAll(bson.M{"site_id": file.SiteID, "files": file.ID}, bson.M{"$pull": bson.M{"files": file.ID}})
+	return err
 }
 
 //
@@ -99,6 +112,11 @@ func (post *Post) FindSite() *Site {
 	return post.dbSession.FindSite(post.SiteID)
 }
 
+// FindFiles fetches post attached files
+func (post *Post) FindFiles() *FilesList {
+	return post.dbSession.findSiteFiles(post.SiteID, post.Files)
+}
+
 // FindCover fetches cover from database
 func (post *Post) FindCover() *Image {
 	if post.Cover != "" {
@@ -123,6 +141,16 @@ func (post *Post) Delete() error {
 		return err
 	}
 
+	return post.dbSession.RemoveRedirectsTo(post.ID)
+}
+
+// SetNotifiedAt sets the NotifiedAt value
+func (post *Post) SetNotifiedAt(value time.Time) error {
+	if err := post.dbSession.PostsCol().UpdateId(post.ID, bson.M{"$set": bson.M{"notified_at": value}}); err != nil {
+		return err
+	}
+
+	post.NotifiedAt = value
 	return nil
 }
 
@@ -194,6 +222,17 @@ func (post *Post) Update(newPost *Post) (bool, error) {
 		}
 	}
 
+	// Files
+	if !reflect.DeepEqual(post.Files, newPost.Files) {
+		post.Files = newPost.Files
+
+		if len(post.Files) == 0 {
+			unset = append(unset, bson.DocElem{"files", 1})
+		} else {
+			set = append(set, bson.DocElem{"files", post.Files})
+		}
+	}
+
 	if len(unset) > 0 {
 		modifier = append(modifier, bson.DocElem{"$unset", unset})
 	}
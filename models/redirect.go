@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	redirectsColName = "redirects"
+
+	// RedirectKindPage is a redirect to a page
+	RedirectKindPage = "page"
+
+	// RedirectKindPost is a redirect to a post
+	RedirectKindPost = "post"
+)
+
+// Redirect represents an old URL path that must redirect to a site page or post, eg: after an import
+type Redirect struct {
+	dbSession *DBSession `bson:"-"`
+
+	ID        bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time     `bson:"created_at"    json:"createdAt"`
+	SiteID    string        `bson:"site_id"       json:"site"`
+
+	Path     string        `bson:"path"   json:"path"` // eg: /2015/03/my-post/
+	Kind     string        `bson:"kind"   json:"kind"` // page | post
+	TargetID bson.ObjectId `bson:"target" json:"target"`
+}
+
+// RedirectsList represents a list of redirects
+type RedirectsList []*Redirect
+
+//
+// DBSession
+//
+
+// RedirectsCol returns the redirects collection
+func (session *DBSession) RedirectsCol() *mgo.Collection {
+	return session.DB().C(redirectsColName)
+}
+
+// EnsureRedirectsIndexes ensures indexes on redirects collection
+func (session *DBSession) EnsureRedirectsIndexes() {
+	index := mgo.Index{
+		Key:        []string{"site_id", "path"},
+		Unique:     true,
+		Background: true,
+	}
+
+	if err := session.RedirectsCol().EnsureIndex(index); err != nil {
+		panic(err)
+	}
+
+	index = mgo.Index{
+		Key:        []string{"target"},
+		Background: true,
+	}
+
+	if err := session.RedirectsCol().EnsureIndex(index); err != nil {
+		panic(err)
+	}
+}
+
+// CreateRedirect creates a new redirect in database
+// Side effect: 'Id' and 'CreatedAt' fields are set on redirect record
+func (session *DBSession) CreateRedirect(redirect *Redirect) error {
+	redirect.ID = bson.NewObjectId()
+	redirect.CreatedAt = time.Now()
+
+	if err := session.RedirectsCol().Insert(redirect); err != nil {
+		return err
+	}
+
+	redirect.dbSession = session
+
+	return nil
+}
+
+// RemoveRedirectsTo removes all redirects to given target
+func (session *DBSession) RemoveRedirectsTo(targetID bson.ObjectId) error {
+	_, err := session.RedirectsCol().RemoveAll(bson.M{"target": targetID})
+	return err
+}
+
+//
+// Redirect
+//
+
+// FindSite fetches site that redirect belongs to
+func (redirect *Redirect) FindSite() *Site {
+	return redirect.dbSession.FindSite(redirect.SiteID)
+}
+
+// Delete deletes redirect from database
+func (redirect *Redirect) Delete() error {
+	return redirect.dbSession.RedirectsCol().RemoveId(redirect.ID)
+}
@@ -0,0 +1,190 @@
+package models
+
+import (
+	"errors"
+	"log"
+	"net/mail"
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	registrationsColName = "registrations"
+)
+
+// Registration represents a registration to an event
+type Registration struct {
+	dbSession *DBSession `bson:"-"`
+
+	ID        bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time     `bson:"created_at"    json:"createdAt"`
+	SiteID    string        `bson:"site_id"       json:"site"`
+	EventID   bson.ObjectId `bson:"event_id"      json:"event"`
+
+	Name   string            `bson:"name"             json:"name"`
+	Email  string            `bson:"email"            json:"email"`
+	Fields map[string]string `bson:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// RegistrationsList represents a list of registrations
+type RegistrationsList []*Registration
+
+var (
+	// ErrRegistrationFull is returned when registering to an event whose capacity is reached
+	ErrRegistrationFull = errors.New("Event registration capacity reached")
+
+	// ErrAlreadyRegistered is returned when registering twice to an event with the same email
+	ErrAlreadyRegistered = errors.New("Already registered to event")
+)
+
+//
+// DBSession
+//
+
+// RegistrationsCol returns the registrations collection
+func (session *DBSession) RegistrationsCol() *mgo.Collection {
+	return session.DB().C(registrationsColName)
+}
+
+// EnsureRegistrationsIndexes ensures indexes on registrations collection
+func (session *DBSession) EnsureRegistrationsIndexes() {
+	index := mgo.Index{
+		Key:        []string{"event_id", "created_at"},
+		Background: true,
+	}
+
+	err := session.RegistrationsCol().EnsureIndex(index)
+	if err != nil {
+		panic(err)
+	}
+
+	index = mgo.Index{
+		Key:        []string{"site_id"},
+		Background: true,
+	}
+
+	err = session.RegistrationsCol().EnsureIndex(index)
+	if err != nil {
+		panic(err)
+	}
+
+	index = mgo.Index{
+		Key:        []string{"event_id", "email"},
+		Unique:     true,
+		Background: true,
+	}
+
+	err = session.RegistrationsCol().EnsureIndex(index)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// FindRegistration finds a registration by id
+func (session *DBSession) FindRegistration(registrationID bson.ObjectId) *Registration {
+	var result Registration
+
+	if err := session.RegistrationsCol().FindId(registrationID).One(&result); err != nil {
+		return nil
+	}
+
+	result.dbSession = session
+
+	return &result
+}
+
+//
+// Event
+//
+
+// CreateRegistration creates a new registration to event in database
+//
+// A seat is reserved atomically in event before inserting registration, so that concurrent registrations can't
+// overbook the event. Returns ErrRegistrationFull if event capacity is reached, and ErrAlreadyRegistered if there is
+// already a registration with that email.
+// Side effect: 'Id', 'CreatedAt', 'SiteID' and 'EventID' fields are set on registration record
+func (event *Event) CreateRegistration(registration *Registration) error {
+	if err := event.reserveSeat(); err != nil {
+		return err
+	}
+
+	registration.ID = bson.NewObjectId()
+	registration.CreatedAt = time.Now()
+	registration.SiteID = event.SiteID
+	registration.EventID = event.ID
+
+	if err := event.dbSession.RegistrationsCol().Insert(registration); err != nil {
+		event.releaseSeat()
+
+		if mgo.IsDup(err) {
+			return ErrAlreadyRegistered
+		}
+
+		return err
+	}
+
+	registration.dbSession = event.dbSession
+
+	return nil
+}
+
+// Increments event registrations counter, unless capacity is reached
+func (event *Event) reserveSeat() error {
+	selector := bson.M{"_id": event.ID}
+
+	if event.RegistrationCapacity > 0 {
+		selector["registration_capacity"] = event.RegistrationCapacity
+		selector["$or"] = []bson.M{
+			{"registrations_count": bson.M{"$lt": event.RegistrationCapacity}},
+			{"registrations_count": bson.M{"$exists": false}},
+		}
+	}
+
+	err := event.dbSession.EventsCol().Update(selector, bson.M{"$inc": bson.M{"registrations_count": 1}})
+	if err == mgo.ErrNotFound {
+		return ErrRegistrationFull
+	}
+
+	return err
+}
+
+// Decrements event registrations counter
+func (event *Event) releaseSeat() {
+	if err := event.dbSession.EventsCol().UpdateId(event.ID, bson.M{"$inc": bson.M{"registrations_count": -1}}); err != nil {
+		log.Printf("ERROR: Failed to release registration seat of event %s: %v", event.ID.Hex(), err)
+	}
+}
+
+//
+// Registration
+//
+
+// FindEvent fetches event that registration belongs to
+func (registration *Registration) FindEvent() *Event {
+	return registration.dbSession.FindEvent(registration.EventID)
+}
+
+// MailAddress returns the registrant email address, formatted for mail headers
+func (registration *Registration) MailAddress() string {
+	addr := mail.Address{
+		Name:    registration.Name,
+		Address: registration.Email,
+	}
+
+	return addr.String()
+}
+
+// Delete deletes registration from database
+func (registration *Registration) Delete() error {
+	if err := registration.dbSession.RegistrationsCol().RemoveId(registration.ID); err != nil {
+		return err
+	}
+
+	if event := registration.FindEvent(); event != nil {
+		event.releaseSeat()
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegistrationTestSuite struct {
+	suite.Suite
+	db *DBSession
+}
+
+// called before all tests
+func (suite *RegistrationTestSuite) SetupSuite() {
+	// setup db
+	suite.db = NewTestDBSession()
+	suite.db.SetDBName(TEST_DBNAME)
+}
+
+// called before each test
+func (suite *RegistrationTestSuite) SetupTest() {
+	// Reset database
+	suite.db.DB().DropDatabase()
+	suite.db.EnsureRegistrationsIndexes()
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestRegistrationTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistrationTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *RegistrationTestSuite) TestConcurrentRegistrations() {
+	t := suite.T()
+
+	event := &Event{SiteID: "site1", Title: "Party", RegistrationEnabled: true, RegistrationCapacity: 3}
+	assert.Nil(t, suite.db.CreateEvent(event))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			errs <- event.CreateRegistration(&Registration{Name: "Bob", Email: fmt.Sprintf("bob%d@example.com", i)})
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	var ok, full int
+	for err := range errs {
+		switch err {
+		case nil:
+			ok++
+		case ErrRegistrationFull:
+			full++
+		default:
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+
+	assert.Equal(t, 3, ok)
+	assert.Equal(t, 7, full)
+	assert.Equal(t, 3, event.RegistrationsNb())
+}
+
+func (suite *RegistrationTestSuite) TestDuplicateRegistration() {
+	t := suite.T()
+
+	event := &Event{SiteID: "site1", Title: "Party", RegistrationEnabled: true, RegistrationCapacity: 2}
+	assert.Nil(t, suite.db.CreateEvent(event))
+
+	assert.Nil(t, event.CreateRegistration(&Registration{Name: "Bob", Email: "bob@example.com"}))
+	assert.Equal(t, ErrAlreadyRegistered, event.CreateRegistration(&Registration{Name: "Bob", Email: "bob@example.com"}))
+
+	// seat of rejected registration is released
+	assert.Nil(t, event.CreateRegistration(&Registration{Name: "Alice", Email: "alice@example.com"}))
+	assert.Equal(t, ErrRegistrationFull, event.CreateRegistration(&Registration{Name: "Eve", Email: "eve@example.com"}))
+
+	// seat of deleted registration is released
+	registration := event.FindRegistrationByEmail("alice@example.com")
+	if assert.NotNil(t, registration) {
+		assert.Nil(t, registration.Delete())
+		assert.Nil(t, event.CreateRegistration(&Registration{Name: "Eve", Email: "eve@example.com"}))
+	}
+}
@@ -0,0 +1,367 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// RRuleDaily is the daily recurrence frequency
+	RRuleDaily = "DAILY"
+
+	// RRuleWeekly is the weekly recurrence frequency
+	RRuleWeekly = "WEEKLY"
+
+	// RRuleMonthly is the monthly recurrence frequency
+	RRuleMonthly = "MONTHLY"
+
+	// max number of periods walked through when computing occurrences
+	maxRRulePeriods = 5000
+
+	rruleUntilFormat     = "20060102T150405Z"
+	rruleUntilDateFormat = "20060102"
+)
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// RRule represents a recurrence rule, as a subset of RFC 5545 RRULE
+//
+// Supported parts are: FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, COUNT, UNTIL, BYDAY and BYMONTHDAY
+type RRule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []RRuleWeekday
+	ByMonthDay []int
+}
+
+// RRuleWeekday represents a BYDAY value, eg: MO, 2TU or -1FR
+type RRuleWeekday struct {
+	// Nth occurrence of weekday in month, zero means every occurrence
+	N       int
+	Weekday time.Weekday
+}
+
+// ParseRRule parses a recurrence rule, eg: FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20151231
+func ParseRRule(value string) (*RRule, error) {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "RRULE:")
+	if value == "" {
+		return nil, errors.New("Empty recurrence rule")
+	}
+
+	result := &RRule{Interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("Invalid recurrence rule part: %s", part)
+		}
+
+		var err error
+
+		switch kv[0] {
+		case "FREQ":
+			if (kv[1] != RRuleDaily) && (kv[1] != RRuleWeekly) && (kv[1] != RRuleMonthly) {
+				return nil, fmt.Errorf("Unsupported recurrence frequency: %s", kv[1])
+			}
+			result.Freq = kv[1]
+
+		case "INTERVAL":
+			if result.Interval, err = strconv.Atoi(kv[1]); (err != nil) || (result.Interval < 1) {
+				return nil, fmt.Errorf("Invalid recurrence interval: %s", kv[1])
+			}
+
+		case "COUNT":
+			if result.Count, err = strconv.Atoi(kv[1]); (err != nil) || (result.Count < 1) {
+				return nil, fmt.Errorf("Invalid recurrence count: %s", kv[1])
+			}
+
+		case "UNTIL":
+			if result.Until, err = time.Parse(rruleUntilFormat, kv[1]); err != nil {
+				if result.Until, err = time.Parse(rruleUntilDateFormat, kv[1]); err != nil {
+					return nil, fmt.Errorf("Invalid recurrence end date: %s", kv[1])
+				}
+
+				// date only: whole day is included
+				result.Until = result.Until.Add(24*time.Hour - time.Second)
+			}
+
+		case "BYDAY":
+			for _, day := range strings.Split(kv[1], ",") {
+				weekday, err := parseRRuleWeekday(day)
+				if err != nil {
+					return nil, err
+				}
+
+				result.ByDay = append(result.ByDay, weekday)
+			}
+
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(kv[1], ",") {
+				monthDay, err := strconv.Atoi(day)
+				if (err != nil) || (monthDay == 0) || (monthDay < -31) || (monthDay > 31) {
+					return nil, fmt.Errorf("Invalid recurrence month day: %s", day)
+				}
+
+				result.ByMonthDay = append(result.ByMonthDay, monthDay)
+			}
+
+		case "WKST":
+			// weeks always start on monday
+
+		default:
+			return nil, fmt.Errorf("Unsupported recurrence rule part: %s", kv[0])
+		}
+	}
+
+	if result.Freq == "" {
+		return nil, errors.New("Missing recurrence frequency")
+	}
+
+	if (result.Count > 0) && !result.Until.IsZero() {
+		return nil, errors.New("Recurrence rule can't have both COUNT and UNTIL")
+	}
+
+	return result, nil
+}
+
+func parseRRuleWeekday(value string) (RRuleWeekday, error) {
+	result := RRuleWeekday{}
+
+	if len(value) < 2 {
+		return result, fmt.Errorf("Invalid recurrence weekday: %s", value)
+	}
+
+	weekday, ok := rruleWeekdays[value[len(value)-2:]]
+	if !ok {
+		return result, fmt.Errorf("Invalid recurrence weekday: %s", value)
+	}
+
+	result.Weekday = weekday
+
+	if len(value) > 2 {
+		n, err := strconv.Atoi(value[:len(value)-2])
+		if (err != nil) || (n == 0) || (n < -5) || (n > 5) {
+			return result, fmt.Errorf("Invalid recurrence weekday: %s", value)
+		}
+
+		result.N = n
+	}
+
+	return result, nil
+}
+
+// Occurrences returns start dates of all occurrences in given time range
+//
+// First occurrence is 'start', which must be expressed in the timezone used to compute wall clock times.
+func (rule *RRule) Occurrences(start time.Time, from time.Time, to time.Time) []time.Time {
+	result := []time.Time{}
+
+	nb := 0
+
+	for period := 0; period < maxRRulePeriods; period++ {
+		candidates := rule.periodCandidates(start, period)
+
+		for _, candidate := range candidates {
+			if candidate.Before(start) {
+				continue
+			}
+
+			if !rule.Until.IsZero() && candidate.After(rule.Until) {
+				return result
+			}
+
+			if candidate.After(to) {
+				return result
+			}
+
+			nb++
+			if (rule.Count > 0) && (nb > rule.Count) {
+				return result
+			}
+
+			if !candidate.Before(from) {
+				result = append(result, candidate)
+			}
+		}
+	}
+
+	return result
+}
+
+// CheckRange returns an error if recurrence does not end within the periods walked through when computing occurrences
+//
+// A recurrence without COUNT nor UNTIL never ends, so its occurrences are limited to those periods.
+func (rule *RRule) CheckRange(start time.Time) error {
+	end := rule.periodStart(start, maxRRulePeriods)
+
+	if !rule.Until.IsZero() && !rule.Until.Before(end) {
+		return errors.New("Recurrence end date is too far from start date")
+	}
+
+	if (rule.Count > 0) && (len(rule.Occurrences(start, start, end)) < rule.Count) {
+		return errors.New("Recurrence count is too big")
+	}
+
+	return nil
+}
+
+// periodStart returns start of period with given index
+func (rule *RRule) periodStart(start time.Time, period int) time.Time {
+	year, month, day := start.Date()
+	loc := start.Location()
+
+	switch rule.Freq {
+	case RRuleWeekly:
+		// weeks start on monday
+		offset := (int(start.Weekday()) + 6) % 7
+		return time.Date(year, month, day+period*rule.Interval*7-offset, 0, 0, 0, 0, loc)
+
+	case RRuleMonthly:
+		return time.Date(year, month+time.Month(period*rule.Interval), 1, 0, 0, 0, 0, loc)
+
+	default:
+		return time.Date(year, month, day+period*rule.Interval, 0, 0, 0, 0, loc)
+	}
+}
+
+// periodCandidates returns sorted candidate dates for given period index
+func (rule *RRule) periodCandidates(start time.Time, period int) []time.Time {
+	result := []time.Time{}
+
+	hour, min, sec := start.Clock()
+	loc := start.Location()
+
+	switch rule.Freq {
+	case RRuleDaily:
+		day := start.AddDate(0, 0, period*rule.Interval)
+		if rule.matchWeekday(day.Weekday()) {
+			result = append(result, day)
+		}
+
+	case RRuleWeekly:
+		// weeks start on monday
+		offset := (int(start.Weekday()) + 6) % 7
+		weekStart := start.AddDate(0, 0, period*rule.Interval*7-offset)
+
+		if len(rule.ByDay) == 0 {
+			result = append(result, weekStart.AddDate(0, 0, offset))
+		} else {
+			for _, byDay := range rule.ByDay {
+				result = append(result, weekStart.AddDate(0, 0, (int(byDay.Weekday)+6)%7))
+			}
+		}
+
+	case RRuleMonthly:
+		year, month, _ := start.Date()
+		monthStart := time.Date(year, month+time.Month(period*rule.Interval), 1, hour, min, sec, 0, loc)
+		nbDays := monthStart.AddDate(0, 1, -1).Day()
+
+		days := []int{}
+
+		for _, monthDay := range rule.ByMonthDay {
+			if monthDay < 0 {
+				monthDay = nbDays + monthDay + 1
+			}
+
+			days = append(days, monthDay)
+		}
+
+		for _, byDay := range rule.ByDay {
+			days = append(days, monthWeekdays(monthStart, nbDays, byDay)...)
+		}
+
+		if (len(rule.ByMonthDay) == 0) && (len(rule.ByDay) == 0) {
+			days = append(days, start.Day())
+		}
+
+		for _, day := range days {
+			if (day >= 1) && (day <= nbDays) {
+				result = append(result, monthStart.AddDate(0, 0, day-1))
+			}
+		}
+	}
+
+	sort.Sort(timesList(result))
+
+	return result
+}
+
+// matchWeekday returns true if given weekday is allowed by BYDAY part
+func (rule *RRule) matchWeekday(weekday time.Weekday) bool {
+	if len(rule.ByDay) == 0 {
+		return true
+	}
+
+	for _, byDay := range rule.ByDay {
+		if byDay.Weekday == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+// monthWeekdays returns days of month matching given BYDAY value
+func monthWeekdays(monthStart time.Time, nbDays int, byDay RRuleWeekday) []int {
+	days := []int{}
+
+	first := (int(byDay.Weekday)-int(monthStart.Weekday())+7)%7 + 1
+	for day := first; day <= nbDays; day += 7 {
+		days = append(days, day)
+	}
+
+	switch {
+	case byDay.N > 0:
+		if byDay.N <= len(days) {
+			return []int{days[byDay.N-1]}
+		}
+		return []int{}
+
+	case byDay.N < 0:
+		if -byDay.N <= len(days) {
+			return []int{days[len(days)+byDay.N]}
+		}
+		return []int{}
+	}
+
+	return days
+}
+
+//
+// timesList
+//
+
+type timesList []time.Time
+
+// Implements sort.Interface
+func (times timesList) Len() int {
+	return len(times)
+}
+
+// Implements sort.Interface
+func (times timesList) Swap(i, j int) {
+	times[i], times[j] = times[j], times[i]
+}
+
+// Implements sort.Interface
+func (times timesList) Less(i, j int) bool {
+	return times[i].Before(times[j])
+}
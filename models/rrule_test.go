@@ -0,0 +1,155 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func occurrencesDates(t *testing.T, rrule string, start time.Time, to time.Time) []string {
+	rule, err := ParseRRule(rrule)
+	if !assert.Nil(t, err) {
+		return nil
+	}
+
+	result := []string{}
+	for _, occurrence := range rule.Occurrences(start, start, to) {
+		result = append(result, occurrence.Format("2006-01-02 15:04"))
+	}
+
+	return result
+}
+
+func TestParseRRule(t *testing.T) {
+	rule, err := ParseRRule("RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=2TU,-1FR;UNTIL=20151231")
+	assert.Nil(t, err)
+	assert.Equal(t, RRuleMonthly, rule.Freq)
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, []RRuleWeekday{{2, time.Tuesday}, {-1, time.Friday}}, rule.ByDay)
+	assert.Equal(t, time.Date(2015, time.December, 31, 23, 59, 59, 0, time.UTC), rule.Until)
+
+	for _, invalid := range []string{"", "FREQ=YEARLY", "INTERVAL=2", "FREQ=DAILY;COUNT=0", "FREQ=WEEKLY;BYDAY=XX", "FREQ=DAILY;COUNT=2;UNTIL=20151231"} {
+		_, err = ParseRRule(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestRRuleOccurrences(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Paris")
+
+	// daily, with count
+	start := time.Date(2015, time.March, 27, 20, 0, 0, 0, loc)
+	assert.Equal(t, []string{"2015-03-27 20:00", "2015-03-29 20:00", "2015-03-31 20:00"}, occurrencesDates(t, "FREQ=DAILY;INTERVAL=2;COUNT=3", start, start.AddDate(1, 0, 0)))
+
+	// weekly, with until
+	start = time.Date(2015, time.June, 3, 18, 30, 0, 0, loc) // wednesday
+	assert.Equal(t, []string{"2015-06-03 18:30", "2015-06-05 18:30", "2015-06-08 18:30", "2015-06-10 18:30", "2015-06-12 18:30"}, occurrencesDates(t, "FREQ=WEEKLY;BYDAY=MO,WE,FR;UNTIL=20150612", start, start.AddDate(1, 0, 0)))
+
+	// monthly, by weekday
+	start = time.Date(2015, time.January, 13, 19, 0, 0, 0, loc)
+	assert.Equal(t, []string{"2015-01-13 19:00", "2015-01-30 19:00", "2015-02-10 19:00", "2015-02-27 19:00"}, occurrencesDates(t, "FREQ=MONTHLY;BYDAY=2TU,-1FR;COUNT=4", start, start.AddDate(1, 0, 0)))
+
+	// monthly, skips months without that day
+	start = time.Date(2015, time.January, 31, 10, 0, 0, 0, loc)
+	assert.Equal(t, []string{"2015-01-31 10:00", "2015-03-31 10:00", "2015-05-31 10:00"}, occurrencesDates(t, "FREQ=MONTHLY", start, time.Date(2015, time.June, 1, 0, 0, 0, 0, loc)))
+}
+
+func TestRRuleCheckRange(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Paris")
+	start := time.Date(2015, time.March, 27, 20, 0, 0, 0, loc) // friday
+
+	tests := []struct {
+		rrule string
+		valid bool
+	}{
+		{"FREQ=DAILY", true},
+		{"FREQ=DAILY;COUNT=5000", true},
+		{"FREQ=DAILY;COUNT=5001", false},
+		{"FREQ=DAILY;INTERVAL=2;UNTIL=" + start.AddDate(0, 0, 9990).Format("20060102"), true},
+		{"FREQ=DAILY;INTERVAL=2;UNTIL=" + start.AddDate(0, 0, 10010).Format("20060102"), false},
+		{"FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=14998", true},
+		{"FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=14999", false},
+		{"FREQ=MONTHLY;UNTIL=24000101", true},
+		{"FREQ=MONTHLY;UNTIL=25000101", false},
+	}
+
+	for _, test := range tests {
+		rule, err := ParseRRule(test.rrule)
+		if assert.Nil(t, err, test.rrule) {
+			assert.Equal(t, test.valid, rule.CheckRange(start) == nil, test.rrule)
+		}
+	}
+}
+
+func TestEventCheckRecurrence(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Paris")
+
+	start := time.Date(2015, time.June, 1, 20, 0, 0, 0, loc)
+
+	event := &Event{
+		StartDate: start,
+		RRule:     "FREQ=WEEKLY;COUNT=4",
+		ExDates:   []time.Time{start.AddDate(0, 0, 7)},
+	}
+	assert.Nil(t, event.CheckRecurrence(loc))
+
+	event.Overrides = map[string]*EventOverride{"20150615T180000Z": {ID: "20150615T180000Z", Title: "Special meeting"}}
+	assert.Nil(t, event.CheckRecurrence(loc))
+
+	for _, invalid := range []*EventOverride{
+		{ID: "20150608T180000Z"}, // excluded
+		{ID: "20150629T180000Z"}, // after count
+		{ID: "20150616T180000Z"}, // not an occurrence
+		{ID: "foo"},
+	} {
+		event.Overrides = map[string]*EventOverride{invalid.ID: invalid}
+		assert.NotNil(t, event.CheckRecurrence(loc), invalid.ID)
+	}
+
+	// id mismatch
+	event.Overrides = map[string]*EventOverride{"20150615T180000Z": {ID: "20150622T180000Z"}}
+	assert.NotNil(t, event.CheckRecurrence(loc))
+
+	// rule out of range
+	event.Overrides = nil
+	event.RRule = "FREQ=DAILY;COUNT=10000"
+	assert.NotNil(t, event.CheckRecurrence(loc))
+
+	// not recurring
+	event.RRule = ""
+	assert.Nil(t, event.CheckRecurrence(loc))
+
+	event.Overrides = map[string]*EventOverride{"20150615T180000Z": {ID: "20150615T180000Z"}}
+	assert.NotNil(t, event.CheckRecurrence(loc))
+}
+
+func TestEventOccurrences(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Paris")
+
+	start := time.Date(2015, time.June, 1, 20, 0, 0, 0, loc)
+
+	event := &Event{
+		Title:     "Weekly meeting",
+		StartDate: start,
+		EndDate:   start.Add(2 * time.Hour),
+		RRule:     "FREQ=WEEKLY;COUNT=4",
+		ExDates:   []time.Time{start.AddDate(0, 0, 7)},
+	}
+
+	event.Overrides = map[string]*EventOverride{
+		OccurrenceID(start.AddDate(0, 0, 14)): {Title: "Special meeting"},
+	}
+
+	occurrences := event.Occurrences(start, start.AddDate(1, 0, 0), loc)
+	if assert.Len(t, occurrences, 3) {
+		assert.Equal(t, "Weekly meeting", occurrences[0].Title)
+		assert.Equal(t, "Special meeting", occurrences[1].Title)
+		assert.Equal(t, start.AddDate(0, 0, 14).Add(2*time.Hour), occurrences[1].EndDate)
+		assert.Equal(t, "20150622T180000Z", occurrences[2].OccurrenceID)
+	}
+
+	assert.True(t, event.HasOccurrence("20150615T180000Z", loc))
+	assert.False(t, event.HasOccurrence("20150608T180000Z", loc))
+	assert.False(t, event.HasOccurrence("20150629T180000Z", loc))
+}
@@ -41,6 +41,14 @@ type SiteThemeSettings struct {
 	Theme   string            `bson:"-" json:"theme"`
 }
 
+// SiteNavBarLink represents a custom link in navigation bar
+type SiteNavBarLink struct {
+	ID     bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	Title  string        `bson:"title"         json:"title"`
+	URL    string        `bson:"url"           json:"url"`
+	Parent string        `bson:"parent"        json:"parent"` // cf. NavBarOrder
+}
+
 // SiteThemeSettingsJSON is the JSON representation of SiteThemeSettings
 type SiteThemeSettingsJSON struct {
 	SiteThemeSettings
@@ -68,8 +76,9 @@ type Site struct {
 	MoreDesc    string `bson:"more_desc"   json:"moreDesc"`
 	JoinText    string `bson:"join_text"   json:"joinText"`
 
-	Email   string `bson:"email"   json:"email"`
-	Address string `bson:"address" json:"address"`
+	Email    string        `bson:"email"              json:"email"`
+	Address  string        `bson:"address"            json:"address"`
+	Location bson.ObjectId `bson:"location,omitempty" json:"location,omitempty"`
 
 	Facebook   string `bson:"facebook"    json:"facebook"`
 	Twitter    string `bson:"twitter"     json:"twitter"`
@@ -97,6 +106,14 @@ type Site struct {
 
 	// theme settings
 	NameInNavBar bool `bson:"name_in_navbar" json:"nameInNavBar"`
+
+	// navigation bar settings
+	NavBarLinks map[string]*SiteNavBarLink `bson:"navbar_links"           json:"-"`
+	NavBarOrder []string                   `bson:"navbar_order,omitempty" json:"navBarOrder"` // items keys: built-in page kind, page id or link id
+
+	// newsletter settings
+	Newsletter   string    `bson:"newsletter"               json:"newsletter"` // cf. NewsletterKinds
+	DigestSentAt time.Time `bson:"digest_sent_at,omitempty" json:"digestSentAt,omitempty"`
 }
 
 // SiteJSON represents the json version of a site
@@ -108,6 +125,7 @@ type SiteJSON struct {
 	// of ids (as needed by Ember Data) instead of a hash of embedded documents
 	PageSettings  []string `json:"pageSettings,omitempty"`
 	ThemeSettings []string `json:"themeSettings,omitempty"`
+	NavBarLinks   []string `json:"navBarLinks,omitempty"`
 }
 
 // SitesList represents a list of sites
@@ -128,8 +146,25 @@ const (
 
 	// PageKindEvents represents the contact page
 	PageKindEvents = "events"
+
+	// PageKindDownloads represents the downloads page
+	PageKindDownloads = "downloads"
 )
 
+const (
+	// NewsletterPost sends an email to subscribers each time a post is published
+	NewsletterPost = "post"
+
+	// NewsletterDigest sends a weekly digest of published posts to subscribers
+	NewsletterDigest = "digest"
+)
+
+// NewsletterKinds holds all possible newsletter kinds
+var NewsletterKinds = map[string]bool{
+	NewsletterPost:   true,
+	NewsletterDigest: true,
+}
+
 func init() {
 	SitePagesSettingsKinds = map[string]bool{
 		PageKindContact:    true,
@@ -137,6 +172,7 @@ func init() {
 		PageKindMembers:    true,
 		PageKindPosts:      true,
 		PageKindEvents:     true,
+		PageKindDownloads:  true,
 	}
 }
 
@@ -191,9 +227,32 @@ func (session *DBSession) CreateSite(site *Site) error {
 	return nil
 }
 
+// FindNewsletterSites fetches all sites with given newsletter kind
+func (session *DBSession) FindNewsletterSites(kind string) *SitesList {
+	result := SitesList{}
+
+	if err := session.SitesCol().Find(bson.M{"newsletter": kind}).All(&result); err != nil {
+		panic(err)
+	}
+
+	// inject dbSession in all result items
+	for _, site := range result {
+		site.dbSession = session
+	}
+
+	return &result
+}
+
 // RemoveImageReferencesFromSitePageSettings removes all references to given image from site page settings
 func (session *DBSession) RemoveImageReferencesFromSitePageSettings(image *Image) error {
-	// @todo
+	for kind := range SitePagesSettingsKinds {
+		field := "page_settings." + kind + ".cover"
+
+		if _, err := session.SitesCol().UpdateAll(bson.M{"_id": image.SiteID, field: image.ID}, bson.M{"$unset": bson.M{field: 1}}); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -250,11 +309,18 @@ func (site *Site) MarshalJSON() ([]byte, error) {
 		themeSettingsIds = append(themeSettingsIds, settings.ID.Hex())
 	}
 
+	// convert hash of embedded docs into an array of doc ids, as needed by Ember Data
+	navBarLinksIds := []string{}
+	for _, link := range site.NavBarLinks {
+		navBarLinksIds = append(navBarLinksIds, link.ID.Hex())
+	}
+
 	siteJSON := SiteJSON{
 		Site:          *site,
 		Links:         links,
 		PageSettings:  pageSettingsIds,
 		ThemeSettings: themeSettingsIds,
+		NavBarLinks:   navBarLinksIds,
 	}
 
 	return json.Marshal(siteJSON)
@@ -325,19 +391,18 @@ func (site *Site) TZLocation() *time.Location {
 // Site posts
 //
 
-func (site *Site) postsBaseQuery(onlyPub bool) *mgo.Query {
-	selector := bson.M{"site_id": site.ID}
-
-	if onlyPub {
-		selector["published"] = true
-	}
-
-	return site.dbSession.PostsCol().Find(selector)
+func (site *Site) postsBaseQuery(filter *ListFilter) *mgo.Query {
+	return site.dbSession.PostsCol().Find(filter.selector(site.ID, postsListSpec))
 }
 
 // PostsNb returns the total number of posts
 func (site *Site) PostsNb() int {
-	result, err := site.postsBaseQuery(false).Count()
+	return site.FilteredPostsNb(nil)
+}
+
+// FilteredPostsNb returns the number of posts matching given filter
+func (site *Site) FilteredPostsNb(filter *ListFilter) int {
+	result, err := site.postsBaseQuery(filter).Count()
 	if err != nil {
 		panic(err)
 	}
@@ -347,9 +412,14 @@ func (site *Site) PostsNb() int {
 
 // FindPosts fetches posts belonging to site
 func (site *Site) FindPosts(skip int, limit int, onlyPub bool) *PostsList {
+	return site.FindFilteredPosts(publishedFilter(onlyPub), skip, limit)
+}
+
+// FindFilteredPosts fetches posts belonging to site and matching given filter
+func (site *Site) FindFilteredPosts(filter *ListFilter, skip int, limit int) *PostsList {
 	result := PostsList{}
 
-	query := site.postsBaseQuery(onlyPub).Sort("published", "-published_at", "-updated_at")
+	query := filter.sort(site.postsBaseQuery(filter), postsListSpec)
 
 	if skip > 0 {
 		query = query.Skip(skip)
@@ -385,13 +455,18 @@ func (site *Site) FindPublishedPosts() *PostsList {
 // Site events
 //
 
-func (site *Site) eventsBaseQuery() *mgo.Query {
-	return site.dbSession.EventsCol().Find(bson.M{"site_id": site.ID})
+func (site *Site) eventsBaseQuery(filter *ListFilter) *mgo.Query {
+	return site.dbSession.EventsCol().Find(filter.selector(site.ID, eventsListSpec))
 }
 
 // EventsNb returns the total number of events
 func (site *Site) EventsNb() int {
-	result, err := site.eventsBaseQuery().Count()
+	return site.FilteredEventsNb(nil)
+}
+
+// FilteredEventsNb returns the number of events matching given filter
+func (site *Site) FilteredEventsNb(filter *ListFilter) int {
+	result, err := site.eventsBaseQuery(filter).Count()
 	if err != nil {
 		panic(err)
 	}
@@ -401,9 +476,14 @@ func (site *Site) EventsNb() int {
 
 // FindEvents fetches events belonging to site
 func (site *Site) FindEvents(skip int, limit int) *EventsList {
+	return site.FindFilteredEvents(nil, skip, limit)
+}
+
+// FindFilteredEvents fetches events belonging to site and matching given filter
+func (site *Site) FindFilteredEvents(filter *ListFilter, skip int, limit int) *EventsList {
 	result := EventsList{}
 
-	query := site.eventsBaseQuery().Sort("-start_date")
+	query := filter.sort(site.eventsBaseQuery(filter), eventsListSpec)
 
 	if skip > 0 {
 		query = query.Skip(skip)
@@ -434,13 +514,18 @@ func (site *Site) FindAllEvents() *EventsList {
 // Site pages
 //
 
-func (site *Site) pagesBaseQuery() *mgo.Query {
-	return site.dbSession.PagesCol().Find(bson.M{"site_id": site.ID})
+func (site *Site) pagesBaseQuery(filter *ListFilter) *mgo.Query {
+	return site.dbSession.PagesCol().Find(filter.selector(site.ID, pagesListSpec))
 }
 
 // PagesNb returns the total number of pages
 func (site *Site) PagesNb() int {
-	result, err := site.pagesBaseQuery().Count()
+	return site.FilteredPagesNb(nil)
+}
+
+// FilteredPagesNb returns the number of pages matching given filter
+func (site *Site) FilteredPagesNb(filter *ListFilter) int {
+	result, err := site.pagesBaseQuery(filter).Count()
 	if err != nil {
 		panic(err)
 	}
@@ -450,9 +535,14 @@ func (site *Site) PagesNb() int {
 
 // FindPages fetches pages belonging to site
 func (site *Site) FindPages(skip int, limit int) *PagesList {
+	return site.FindFilteredPages(nil, skip, limit)
+}
+
+// FindFilteredPages fetches pages belonging to site and matching given filter
+func (site *Site) FindFilteredPages(filter *ListFilter, skip int, limit int) *PagesList {
 	result := PagesList{}
 
-	query := site.pagesBaseQuery().Sort("created_at")
+	query := filter.sort(site.pagesBaseQuery(filter), pagesListSpec)
 
 	if skip > 0 {
 		query = query.Skip(skip)
@@ -479,17 +569,36 @@ func (site *Site) FindAllPages() *PagesList {
 	return site.FindPages(0, 0)
 }
 
+// UpdatePageOrder updates a page order in database
+func (site *Site) UpdatePageOrder(id bson.ObjectId, order int) error {
+	// specify site_id in selector to prevent unprivileged users access
+	selector := bson.M{"site_id": site.ID, "_id": id}
+	modifier := bson.M{"$set": bson.M{"order": order}}
+
+	err := site.dbSession.PagesCol().Update(selector, modifier)
+	if err == mgo.ErrNotFound {
+		return ErrPageNotFound
+	}
+
+	return err
+}
+
 //
 // Site activities
 //
 
-func (site *Site) activitiesBaseQuery() *mgo.Query {
-	return site.dbSession.ActivitiesCol().Find(bson.M{"site_id": site.ID})
+func (site *Site) activitiesBaseQuery(filter *ListFilter) *mgo.Query {
+	return site.dbSession.ActivitiesCol().Find(filter.selector(site.ID, activitiesListSpec))
 }
 
 // ActivitiesNb returns the total number of activities
 func (site *Site) ActivitiesNb() int {
-	result, err := site.activitiesBaseQuery().Count()
+	return site.FilteredActivitiesNb(nil)
+}
+
+// FilteredActivitiesNb returns the number of activities matching given filter
+func (site *Site) FilteredActivitiesNb(filter *ListFilter) int {
+	result, err := site.activitiesBaseQuery(filter).Count()
 	if err != nil {
 		panic(err)
 	}
@@ -499,9 +608,14 @@ func (site *Site) ActivitiesNb() int {
 
 // FindActivities fetches activities belonging to site
 func (site *Site) FindActivities(skip int, limit int) *ActivitiesList {
+	return site.FindFilteredActivities(nil, skip, limit)
+}
+
+// FindFilteredActivities fetches activities belonging to site and matching given filter
+func (site *Site) FindFilteredActivities(filter *ListFilter, skip int, limit int) *ActivitiesList {
 	result := ActivitiesList{}
 
-	query := site.activitiesBaseQuery().Sort("created_at")
+	query := filter.sort(site.activitiesBaseQuery(filter), activitiesListSpec)
 
 	if skip > 0 {
 		query = query.Skip(skip)
@@ -532,13 +646,18 @@ func (site *Site) FindAllActivities() *ActivitiesList {
 // Site members
 //
 
-func (site *Site) membersBaseQuery() *mgo.Query {
-	return site.dbSession.MembersCol().Find(bson.M{"site_id": site.ID})
+func (site *Site) membersBaseQuery(filter *ListFilter) *mgo.Query {
+	return site.dbSession.MembersCol().Find(filter.selector(site.ID, membersListSpec))
 }
 
 // MembersNb returns the total number of members
 func (site *Site) MembersNb() int {
-	result, err := site.membersBaseQuery().Count()
+	return site.FilteredMembersNb(nil)
+}
+
+// FilteredMembersNb returns the number of members matching given filter
+func (site *Site) FilteredMembersNb(filter *ListFilter) int {
+	result, err := site.membersBaseQuery(filter).Count()
 	if err != nil {
 		panic(err)
 	}
@@ -548,9 +667,14 @@ func (site *Site) MembersNb() int {
 
 // FindMembers fetches members belonging to site
 func (site *Site) FindMembers(skip int, limit int) *MembersList {
+	return site.FindFilteredMembers(nil, skip, limit)
+}
+
+// FindFilteredMembers fetches members belonging to site and matching given filter
+func (site *Site) FindFilteredMembers(filter *ListFilter, skip int, limit int) *MembersList {
 	result := MembersList{}
 
-	query := site.membersBaseQuery().Sort("order", "created_at")
+	query := filter.sort(site.membersBaseQuery(filter), membersListSpec)
 
 	if skip > 0 {
 		query = query.Skip(skip)
@@ -586,17 +710,257 @@ func (site *Site) UpdateMemberOrder(id bson.ObjectId, order int) {
 	site.dbSession.MembersCol().Update(selector, modifier)
 }
 
+//
+// Site locations
+//
+
+func (site *Site) locationsBaseQuery() *mgo.Query {
+	return site.dbSession.LocationsCol().Find(bson.M{"site_id": site.ID})
+}
+
+// LocationsNb returns the total number of locations
+func (site *Site) LocationsNb() int {
+	result, err := site.locationsBaseQuery().Count()
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// FindLocations fetches locations belonging to site
+func (site *Site) FindLocations(skip int, limit int) *LocationsList {
+	result := LocationsList{}
+
+	query := site.locationsBaseQuery().Sort("name")
+
+	if skip > 0 {
+		query = query.Skip(skip)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.All(&result); err != nil {
+		panic(err)
+	}
+
+	// inject dbSession in all result items
+	for _, location := range result {
+		location.dbSession = site.dbSession
+	}
+
+	return &result
+}
+
+// FindAllLocations fetches all locations belonging to site
+func (site *Site) FindAllLocations() *LocationsList {
+	return site.FindLocations(0, 0)
+}
+
+//
+// Site redirects
+//
+
+// FindAllRedirects fetches all redirects belonging to site
+func (site *Site) FindAllRedirects() *RedirectsList {
+	result := RedirectsList{}
+
+	if err := site.dbSession.RedirectsCol().Find(bson.M{"site_id": site.ID}).Sort("path").All(&result); err != nil {
+		panic(err)
+	}
+
+	// inject dbSession in all result items
+	for _, redirect := range result {
+		redirect.dbSession = site.dbSession
+	}
+
+	return &result
+}
+
+//
+// Site messages
+//
+
+func (site *Site) messagesBaseQuery() *mgo.Query {
+	return site.dbSession.MessagesCol().Find(bson.M{"site_id": site.ID})
+}
+
+// MessagesNb returns the total number of messages
+func (site *Site) MessagesNb() int {
+	result, err := site.messagesBaseQuery().Count()
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// FindMessages fetches messages belonging to site
+func (site *Site) FindMessages(skip int, limit int) *MessagesList {
+	result := MessagesList{}
+
+	query := site.messagesBaseQuery().Sort("-created_at")
+
+	if skip > 0 {
+		query = query.Skip(skip)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.All(&result); err != nil {
+		panic(err)
+	}
+
+	// inject dbSession in all result items
+	for _, message := range result {
+		message.dbSession = site.dbSession
+	}
+
+	return &result
+}
+
+//
+// Site subscribers
+//
+
+func (site *Site) subscribersBaseQuery(onlyConfirmed bool) *mgo.Query {
+	query := bson.M{"site_id": site.ID}
+
+	if onlyConfirmed {
+		query["confirmed"] = true
+	}
+
+	return site.dbSession.SubscribersCol().Find(query)
+}
+
+// SubscribersNb returns the total number of subscribers
+func (site *Site) SubscribersNb() int {
+	result, err := site.subscribersBaseQuery(false).Count()
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// FindSubscribers fetches subscribers belonging to site
+func (site *Site) FindSubscribers(skip int, limit int, onlyConfirmed bool) *SubscribersList {
+	result := SubscribersList{}
+
+	query := site.subscribersBaseQuery(onlyConfirmed).Sort("-created_at")
+
+	if skip > 0 {
+		query = query.Skip(skip)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.All(&result); err != nil {
+		panic(err)
+	}
+
+	// inject dbSession in all result items
+	for _, subscriber := range result {
+		subscriber.dbSession = site.dbSession
+	}
+
+	return &result
+}
+
+// FindConfirmedSubscribers fetches all confirmed subscribers belonging to site
+func (site *Site) FindConfirmedSubscribers() *SubscribersList {
+	return site.FindSubscribers(0, 0, true)
+}
+
+//
+// Site webhooks
+//
+
+func (site *Site) webhooksBaseQuery() *mgo.Query {
+	return site.dbSession.WebhooksCol().Find(bson.M{"site_id": site.ID})
+}
+
+// WebhooksNb returns the total number of webhooks
+func (site *Site) WebhooksNb() int {
+	result, err := site.webhooksBaseQuery().Count()
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// FindWebhooks fetches webhooks belonging to site
+func (site *Site) FindWebhooks(skip int, limit int) *WebhooksList {
+	result := WebhooksList{}
+
+	query := site.webhooksBaseQuery().Sort("created_at")
+
+	if skip > 0 {
+		query = query.Skip(skip)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.All(&result); err != nil {
+		panic(err)
+	}
+
+	// inject dbSession in all result items
+	for _, webhook := range result {
+		webhook.dbSession = site.dbSession
+	}
+
+	return &result
+}
+
+// FindEventWebhooks fetches all webhooks that must be fired for given event
+func (site *Site) FindEventWebhooks(event string) WebhooksList {
+	result := WebhooksList{}
+
+	for _, webhook := range *site.FindWebhooks(0, 0) {
+		if webhook.HandleEvent(event) {
+			result = append(result, webhook)
+		}
+	}
+
+	return result
+}
+
+// DeleteWebhooks deletes all site webhooks and their deliveries log
+func (site *Site) DeleteWebhooks() error {
+	if _, err := site.dbSession.WebhooksCol().RemoveAll(bson.M{"site_id": site.ID}); err != nil {
+		return err
+	}
+
+	_, err := site.dbSession.WebhookDeliveriesCol().RemoveAll(bson.M{"site_id": site.ID})
+	return err
+}
+
 //
 // Site images
 //
 
-func (site *Site) imagesBaseQuery() *mgo.Query {
-	return site.dbSession.ImagesCol().Find(bson.M{"site_id": site.ID})
+func (site *Site) imagesBaseQuery(filter *MediaFilter) *mgo.Query {
+	return site.dbSession.ImagesCol().Find(filter.selector(site.ID))
 }
 
 // ImagesNb returns the total number of images
 func (site *Site) ImagesNb() int {
-	result, err := site.imagesBaseQuery().Count()
+	return site.FilteredImagesNb(nil)
+}
+
+// FilteredImagesNb returns the number of images matching given filter
+func (site *Site) FilteredImagesNb(filter *MediaFilter) int {
+	result, err := site.imagesBaseQuery(filter).Count()
 	if err != nil {
 		panic(err)
 	}
@@ -606,9 +970,14 @@ func (site *Site) ImagesNb() int {
 
 // FindImages fetches images belonging to site
 func (site *Site) FindImages(skip int, limit int) *ImagesList {
+	return site.FindFilteredImages(nil, skip, limit)
+}
+
+// FindFilteredImages fetches images belonging to site and matching given filter
+func (site *Site) FindFilteredImages(filter *MediaFilter, skip int, limit int) *ImagesList {
 	result := ImagesList{}
 
-	query := site.imagesBaseQuery().Sort("-created_at")
+	query := site.imagesBaseQuery(filter).Sort("-created_at")
 
 	if skip > 0 {
 		query = query.Skip(skip)
@@ -622,11 +991,24 @@ func (site *Site) FindImages(skip int, limit int) *ImagesList {
 		panic(err)
 	}
 
-	// @todo Inject dbSession in all result items
+	// inject dbSession in all result items
+	for _, item := range result {
+		item.dbSession = site.dbSession
+	}
 
 	return &result
 }
 
+// FindImage fetches an image belonging to site
+func (site *Site) FindImage(imageID bson.ObjectId) *Image {
+	result := site.dbSession.FindImage(imageID)
+	if (result == nil) || (result.SiteID != site.ID) {
+		return nil
+	}
+
+	return result
+}
+
 // FindAllImages fetches all images belonging to site
 func (site *Site) FindAllImages() *ImagesList {
 	return site.FindImages(0, 0)
@@ -636,13 +1018,18 @@ func (site *Site) FindAllImages() *ImagesList {
 // Site files
 //
 
-func (site *Site) filesBaseQuery() *mgo.Query {
-	return site.dbSession.FilesCol().Find(bson.M{"site_id": site.ID})
+func (site *Site) filesBaseQuery(filter *MediaFilter) *mgo.Query {
+	return site.dbSession.FilesCol().Find(filter.selector(site.ID))
 }
 
 // FilesNb returns the total number of files
 func (site *Site) FilesNb() int {
-	result, err := site.filesBaseQuery().Count()
+	return site.FilteredFilesNb(nil)
+}
+
+// FilteredFilesNb returns the number of files matching given filter
+func (site *Site) FilteredFilesNb(filter *MediaFilter) int {
+	result, err := site.filesBaseQuery(filter).Count()
 	if err != nil {
 		panic(err)
 	}
@@ -652,9 +1039,14 @@ func (site *Site) FilesNb() int {
 
 // FindFiles fetches files belonging to site
 func (site *Site) FindFiles(skip int, limit int) *FilesList {
+	return site.FindFilteredFiles(nil, skip, limit)
+}
+
+// FindFilteredFiles fetches files belonging to site and matching given filter
+func (site *Site) FindFilteredFiles(filter *MediaFilter, skip int, limit int) *FilesList {
 	result := FilesList{}
 
-	query := site.filesBaseQuery().Sort("-created_at")
+	query := site.filesBaseQuery(filter).Sort("-created_at")
 
 	if skip > 0 {
 		query = query.Skip(skip)
@@ -668,11 +1060,19 @@ func (site *Site) FindFiles(skip int, limit int) *FilesList {
 		panic(err)
 	}
 
-	// @todo Inject dbSession in all result items
+	// inject dbSession in all result items
+	for _, item := range result {
+		item.dbSession = site.dbSession
+	}
 
 	return &result
 }
 
+// FindDocuments fetches all downloadable documents belonging to site
+func (site *Site) FindDocuments() *FilesList {
+	return site.FindFilteredFiles(&MediaFilter{Kind: FileDocument}, 0, 0)
+}
+
 // FindAllFiles fetches all files belonging to site
 func (site *Site) FindAllFiles() *FilesList {
 	return site.FindFiles(0, 0)
@@ -750,6 +1150,15 @@ func (site *Site) FindMembership() *File {
 	return nil
 }
 
+// FindLocation fetches location from database
+func (site *Site) FindLocation() *Location {
+	if site.Location != "" {
+		return site.dbSession.FindLocation(site.Location)
+	}
+
+	return nil
+}
+
 // FindPageSettingsCover fetches page settings cover from database
 func (site *Site) FindPageSettingsCover(settingKind string) *Image {
 	pageSettings := site.PageSettings[settingKind]
@@ -794,6 +1203,12 @@ func (site *Site) RemoveImageReferences(image *Image) error {
 		return err
 	}
 
+	for _, settings := range site.PageSettings {
+		if settings.Cover == image.ID {
+			settings.Cover = ""
+		}
+	}
+
 	// remove image references from posts
 	if err := site.dbSession.RemoveImageReferencesFromPosts(image); err != nil {
 		return err
@@ -814,17 +1229,18 @@ func (site *Site) RemoveImageReferences(image *Image) error {
 		return err
 	}
 
-	// remove image references from activities
+	// remove image references from members
 	if err := site.dbSession.RemoveImageReferencesFromMembers(image); err != nil {
 		return err
 	}
 
-	return nil
+	// remove image shortcodes from contents
+	return site.removeImageShortcodes(image)
 }
 
 // RemoveFileReferences removes all references to given file from database
 func (site *Site) RemoveFileReferences(file *File) error {
-	// remove image reference from site settings
+	// remove file reference from site settings
 	fieldsToDelete := []string{}
 
 	if site.Membership == file.ID {
@@ -835,6 +1251,21 @@ func (site *Site) RemoveFileReferences(file *File) error {
 		site.DeleteFields(fieldsToDelete)
 	}
 
+	// remove file references from posts
+	if err := site.dbSession.RemoveFileReferencesFromPosts(file); err != nil {
+		return err
+	}
+
+	// remove file references from events
+	if err := site.dbSession.RemoveFileReferencesFromEvents(file); err != nil {
+		return err
+	}
+
+	// remove file references from pages
+	if err := site.dbSession.RemoveFileReferencesFromPages(file); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -1012,6 +1443,16 @@ func (site *Site) Update(newSite *Site) (bool, error) {
 		}
 	}
 
+	if site.Location != newSite.Location {
+		site.Location = newSite.Location
+
+		if site.Location == "" {
+			unset = append(unset, bson.DocElem{"location", 1})
+		} else {
+			set = append(set, bson.DocElem{"location", site.Location})
+		}
+	}
+
 	if site.Theme != newSite.Theme {
 		site.Theme = newSite.Theme
 
@@ -1062,6 +1503,16 @@ func (site *Site) Update(newSite *Site) (bool, error) {
 		}
 	}
 
+	if (site.Newsletter != newSite.Newsletter) && ((newSite.Newsletter == "") || NewsletterKinds[newSite.Newsletter]) {
+		site.Newsletter = newSite.Newsletter
+
+		if site.Newsletter == "" {
+			unset = append(unset, bson.DocElem{"newsletter", 1})
+		} else {
+			set = append(set, bson.DocElem{"newsletter", site.Newsletter})
+		}
+	}
+
 	if (len(unset) > 0) || (len(set) > 0) {
 		site.UpdatedAt = time.Now()
 		set = append(set, bson.DocElem{"updated_at", site.UpdatedAt})
@@ -1108,6 +1559,16 @@ func (site *Site) SetBuiltAt(value time.Time) error {
 	return nil
 }
 
+// SetDigestSentAt sets the DigestSentAt value
+func (site *Site) SetDigestSentAt(value time.Time) error {
+	if err := site.SetValues(bson.M{"digest_sent_at": value}); err != nil {
+		return err
+	}
+
+	site.DigestSentAt = value
+	return nil
+}
+
 // SetMembership sets the Membership value
 func (site *Site) SetMembership(value bson.ObjectId) error {
 	if err := site.SetValues(bson.M{"membership": value}); err != nil {
@@ -1138,12 +1599,18 @@ func (site *Site) Delete() error {
 
 	// delete site content
 	// @todo Catch and report errors
+	site.dbSession.APITokensCol().RemoveAll(bson.M{"site_id": site.ID})
 	site.dbSession.ActivitiesCol().RemoveAll(bson.M{"site_id": site.ID})
 	site.dbSession.EventsCol().RemoveAll(bson.M{"site_id": site.ID})
 	site.dbSession.ImagesCol().RemoveAll(bson.M{"site_id": site.ID})
+	site.dbSession.LocationsCol().RemoveAll(bson.M{"site_id": site.ID})
 	site.dbSession.MembersCol().RemoveAll(bson.M{"site_id": site.ID})
+	site.dbSession.MessagesCol().RemoveAll(bson.M{"site_id": site.ID})
 	site.dbSession.PagesCol().RemoveAll(bson.M{"site_id": site.ID})
 	site.dbSession.PostsCol().RemoveAll(bson.M{"site_id": site.ID})
+	site.dbSession.RedirectsCol().RemoveAll(bson.M{"site_id": site.ID})
+	site.dbSession.RegistrationsCol().RemoveAll(bson.M{"site_id": site.ID})
+	site.dbSession.SubscribersCol().RemoveAll(bson.M{"site_id": site.ID})
 
 	// delete site images
 	// @todo Catch and report error
@@ -1194,3 +1661,51 @@ func (site *Site) SetThemeSettings(settings *SiteThemeSettings) error {
 
 	return site.dbSession.SitesCol().UpdateId(site.ID, bson.M{"$set": bson.D{bson.DocElem{fmt.Sprintf("theme_settings.%s", settings.Theme), settings}}})
 }
+
+// SetNavBarLink inserts (or updates) navigation bar link to database
+// Side effect: 'Id' field is set on record if not already present, and string fields are trimed
+func (site *Site) SetNavBarLink(link *SiteNavBarLink) error {
+	if link.ID == "" {
+		link.ID = bson.NewObjectId()
+	}
+
+	link.Title = strings.TrimSpace(link.Title)
+	link.URL = strings.TrimSpace(link.URL)
+
+	if link.Title == "" || link.URL == "" {
+		return errors.New("Navigation bar link must have a title and an URL")
+	}
+
+	if err := site.dbSession.SitesCol().UpdateId(site.ID, bson.M{"$set": bson.D{bson.DocElem{fmt.Sprintf("navbar_links.%s", link.ID.Hex()), link}}}); err != nil {
+		return err
+	}
+
+	if site.NavBarLinks == nil {
+		site.NavBarLinks = make(map[string]*SiteNavBarLink)
+	}
+
+	site.NavBarLinks[link.ID.Hex()] = link
+
+	return nil
+}
+
+// DeleteNavBarLink removes navigation bar link from database
+func (site *Site) DeleteNavBarLink(link *SiteNavBarLink) error {
+	if err := site.DeleteFields([]string{fmt.Sprintf("navbar_links.%s", link.ID.Hex())}); err != nil {
+		return err
+	}
+
+	delete(site.NavBarLinks, link.ID.Hex())
+
+	return nil
+}
+
+// SetNavBarOrder sets the NavBarOrder value
+func (site *Site) SetNavBarOrder(value []string) error {
+	if err := site.SetValues(bson.M{"navbar_order": value}); err != nil {
+		return err
+	}
+
+	site.NavBarOrder = value
+	return nil
+}
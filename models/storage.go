@@ -0,0 +1,47 @@
+package models
+
+import (
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// StorageUsage represents the storage used by site uploads, in bytes
+type StorageUsage struct {
+	Images int64 `json:"images"`
+	Files  int64 `json:"files"`
+	Total  int64 `json:"total"`
+}
+
+// StorageUsage computes storage used by site images and files originals
+func (site *Site) StorageUsage() *StorageUsage {
+	result := &StorageUsage{
+		Images: sumSizes(site.dbSession.ImagesCol(), site.ID),
+		Files:  sumSizes(site.dbSession.FilesCol(), site.ID),
+	}
+
+	result.Total = result.Images + result.Files
+
+	return result
+}
+
+// Returns the sum of size fields of all site records in given collection
+func sumSizes(col *mgo.Collection, siteID string) int64 {
+	var result struct {
+		Size int64 `bson:"size"`
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"site_id": siteID}},
+		{"$group": bson.M{"_id": nil, "size": bson.M{"$sum": "$size"}}},
+	}
+
+	if err := col.Pipe(pipeline).One(&result); err != nil {
+		if err == mgo.ErrNotFound {
+			return 0
+		}
+
+		panic(err)
+	}
+
+	return result.Size
+}
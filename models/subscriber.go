@@ -0,0 +1,156 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	subscribersColName = "subscribers"
+
+	// minimum delay between two subscription confirmation mails sent to the same subscriber
+	subscriptionConfirmationCooldown = time.Hour
+)
+
+// Subscriber represents an email address subscribed to a site newsletter
+type Subscriber struct {
+	dbSession *DBSession `bson:"-"`
+
+	ID        bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time     `bson:"created_at"    json:"createdAt"`
+	SiteID    string        `bson:"site_id"       json:"site"`
+
+	Email       string    `bson:"email"                  json:"email"`
+	Confirmed   bool      `bson:"confirmed"              json:"confirmed"`
+	ConfirmedAt time.Time `bson:"confirmed_at,omitempty" json:"confirmedAt,omitempty"`
+
+	ConfirmationSentAt time.Time `bson:"confirmation_sent_at,omitempty" json:"-"`
+}
+
+// SubscribersList represents a list of subscribers
+type SubscribersList []*Subscriber
+
+//
+// DBSession
+//
+
+// SubscribersCol returns the subscribers collection
+func (session *DBSession) SubscribersCol() *mgo.Collection {
+	return session.DB().C(subscribersColName)
+}
+
+// EnsureSubscribersIndexes ensures indexes on subscribers collection
+func (session *DBSession) EnsureSubscribersIndexes() {
+	index := mgo.Index{
+		Key:        []string{"site_id", "email"},
+		Unique:     true,
+		Background: true,
+	}
+
+	err := session.SubscribersCol().EnsureIndex(index)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// FindSubscriber finds a subscriber by id
+func (session *DBSession) FindSubscriber(subscriberID bson.ObjectId) *Subscriber {
+	var result Subscriber
+
+	if err := session.SubscribersCol().FindId(subscriberID).One(&result); err != nil {
+		return nil
+	}
+
+	result.dbSession = session
+
+	return &result
+}
+
+// FindSubscriberByEmail finds a site subscriber by email
+func (session *DBSession) FindSubscriberByEmail(siteID string, email string) *Subscriber {
+	var result Subscriber
+
+	if err := session.SubscribersCol().Find(bson.M{"site_id": siteID, "email": email}).One(&result); err != nil {
+		return nil
+	}
+
+	result.dbSession = session
+
+	return &result
+}
+
+// CreateSubscriber creates a new subscriber in database
+// Side effect: 'Id' and 'CreatedAt' fields are set on subscriber record
+func (session *DBSession) CreateSubscriber(subscriber *Subscriber) error {
+	subscriber.ID = bson.NewObjectId()
+	subscriber.CreatedAt = time.Now()
+
+	if err := session.SubscribersCol().Insert(subscriber); err != nil {
+		return err
+	}
+
+	subscriber.dbSession = session
+
+	return nil
+}
+
+//
+// Subscriber
+//
+
+// FindSite fetches site that subscriber belongs to
+func (subscriber *Subscriber) FindSite() *Site {
+	return subscriber.dbSession.FindSite(subscriber.SiteID)
+}
+
+// Confirm marks subscription as confirmed
+func (subscriber *Subscriber) Confirm() error {
+	if subscriber.Confirmed {
+		return nil
+	}
+
+	now := time.Now()
+
+	if err := subscriber.dbSession.SubscribersCol().UpdateId(subscriber.ID, bson.M{"$set": bson.M{"confirmed": true, "confirmed_at": now}}); err != nil {
+		return err
+	}
+
+	subscriber.Confirmed = true
+	subscriber.ConfirmedAt = now
+
+	return nil
+}
+
+// MarkConfirmationSent registers that a subscription confirmation mail is sent, and returns false if a mail was
+// already sent recently, so that an address can't be flooded with confirmation mails
+func (subscriber *Subscriber) MarkConfirmationSent() (bool, error) {
+	now := time.Now()
+
+	selector := bson.M{
+		"_id": subscriber.ID,
+		"$or": []bson.M{
+			bson.M{"confirmation_sent_at": bson.M{"$exists": false}},
+			bson.M{"confirmation_sent_at": bson.M{"$lt": now.Add(-subscriptionConfirmationCooldown)}},
+		},
+	}
+
+	err := subscriber.dbSession.SubscribersCol().Update(selector, bson.M{"$set": bson.M{"confirmation_sent_at": now}})
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	subscriber.ConfirmationSentAt = now
+
+	return true, nil
+}
+
+// Delete deletes subscriber from database
+func (subscriber *Subscriber) Delete() error {
+	return subscriber.dbSession.SubscribersCol().RemoveId(subscriber.ID)
+}
@@ -36,6 +36,11 @@ type User struct {
 	Lang      string `bson:"lang"       json:"lang"`
 	TZ        string `bson:"tz"         json:"tz"`
 	Password  string `bson:"password"   json:"-"`
+
+	TwoFactor     bool     `bson:"two_factor"               json:"twoFactor"`
+	TOTPSecret    string   `bson:"totp_secret,omitempty"    json:"-"`
+	TOTPLastStep  int64    `bson:"totp_last_step,omitempty" json:"-"`
+	RecoveryCodes []string `bson:"recovery_codes,omitempty" json:"-"` // hashed
 }
 
 // UserJSON represents the json version of a user
@@ -180,6 +185,26 @@ func (user *User) FindSites() *SitesList {
 	return &result
 }
 
+// FindAPITokens fetches all API tokens belonging to user
+func (user *User) FindAPITokens() *APITokensList {
+	result := APITokensList{}
+
+	// @todo Handle err
+	user.dbSession.APITokensCol().Find(bson.M{"user_id": user.ID}).Sort("-created_at").All(&result)
+
+	for _, apiToken := range result {
+		apiToken.dbSession = user.dbSession
+	}
+
+	return &result
+}
+
+// RemoveAPITokens removes all API tokens belonging to user
+func (user *User) RemoveAPITokens() error {
+	_, err := user.dbSession.APITokensCol().RemoveAll(bson.M{"user_id": user.ID})
+	return err
+}
+
 // Update updates user in database
 func (user *User) Update(newUser *User) (bool, error) {
 	var set, unset, modifier bson.D
@@ -270,3 +295,129 @@ func (user *User) SetAccountValidated() error {
 
 	return nil
 }
+
+// SetPassword sets user encrypted password
+func (user *User) SetPassword(encryptedPassword string) error {
+	now := time.Now()
+
+	fields := bson.M{
+		"password":   encryptedPassword,
+		"updated_at": now,
+	}
+
+	if err := user.SetValues(fields); err != nil {
+		return err
+	}
+
+	user.Password = encryptedPassword
+	user.UpdatedAt = now
+
+	return nil
+}
+
+// SetTOTPSecret sets the TOTP secret of a pending two-factor authentication enrollment
+func (user *User) SetTOTPSecret(secret string) error {
+	if err := user.SetValues(bson.M{"totp_secret": secret}); err != nil {
+		return err
+	}
+
+	user.TOTPSecret = secret
+
+	return nil
+}
+
+// EnableTwoFactor enables two-factor authentication with given hashed recovery codes
+func (user *User) EnableTwoFactor(recoveryCodes []string) error {
+	fields := bson.M{
+		"two_factor":     true,
+		"recovery_codes": recoveryCodes,
+	}
+
+	if err := user.SetValues(fields); err != nil {
+		return err
+	}
+
+	user.TwoFactor = true
+	user.RecoveryCodes = recoveryCodes
+
+	return nil
+}
+
+// SetRecoveryCodes replaces two-factor authentication hashed recovery codes
+func (user *User) SetRecoveryCodes(recoveryCodes []string) error {
+	if err := user.SetValues(bson.M{"recovery_codes": recoveryCodes}); err != nil {
+		return err
+	}
+
+	user.RecoveryCodes = recoveryCodes
+
+	return nil
+}
+
+// DisableTwoFactor disables two-factor authentication
+func (user *User) DisableTwoFactor() error {
+	modifier := bson.D{
+		{"$set", bson.D{{"two_factor", false}}},
+		{"$unset", bson.D{{"totp_secret", 1}, {"totp_last_step", 1}, {"recovery_codes", 1}}},
+	}
+
+	if err := user.dbSession.UsersCol().UpdateId(user.ID, modifier); err != nil {
+		return err
+	}
+
+	user.TwoFactor = false
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	user.RecoveryCodes = nil
+
+	return nil
+}
+
+// UseTOTPStep records given TOTP time step as used, and returns false if it was already used
+func (user *User) UseTOTPStep(step int64) (bool, error) {
+	selector := bson.M{
+		"_id": user.ID,
+		"$or": []bson.M{
+			{"totp_last_step": bson.M{"$exists": false}},
+			{"totp_last_step": bson.M{"$lt": step}},
+		},
+	}
+
+	if err := user.dbSession.UsersCol().Update(selector, bson.M{"$set": bson.M{"totp_last_step": step}}); err != nil {
+		if err == mgo.ErrNotFound {
+			// code replayed
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	user.TOTPLastStep = step
+
+	return true, nil
+}
+
+// UseRecoveryCode consumes given hashed recovery code, and returns false if it does not exist
+func (user *User) UseRecoveryCode(recoveryCode string) (bool, error) {
+	selector := bson.M{
+		"_id":            user.ID,
+		"recovery_codes": recoveryCode,
+	}
+
+	if err := user.dbSession.UsersCol().Update(selector, bson.M{"$pull": bson.M{"recovery_codes": recoveryCode}}); err != nil {
+		if err == mgo.ErrNotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	for i, code := range user.RecoveryCodes {
+		if code == recoveryCode {
+			user.RecoveryCodes = append(user.RecoveryCodes[:i], user.RecoveryCodes[i+1:]...)
+			break
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,319 @@
+package models
+
+import (
+	"reflect"
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	webhooksColName          = "webhooks"
+	webhookDeliveriesColName = "webhook_deliveries"
+
+	// webhookDeliveriesTTL is the delay after which deliveries are removed from log
+	webhookDeliveriesTTL = 30 * 24 * time.Hour
+
+	// WebhookEventSiteChanged is fired when some site content changed
+	WebhookEventSiteChanged = "site.changed"
+
+	// WebhookEventSiteDeleted is fired when site is deleted
+	WebhookEventSiteDeleted = "site.deleted"
+
+	// WebhookEventBuildSucceeded is fired when site build succeeded
+	WebhookEventBuildSucceeded = "build.succeeded"
+
+	// WebhookEventBuildFailed is fired when site build failed
+	WebhookEventBuildFailed = "build.failed"
+)
+
+// WebhookEvents holds all available webhook events
+var WebhookEvents = []string{
+	WebhookEventSiteChanged,
+	WebhookEventSiteDeleted,
+	WebhookEventBuildSucceeded,
+	WebhookEventBuildFailed,
+}
+
+// Webhook represents a subscription of an external URL to site events
+type Webhook struct {
+	dbSession *DBSession `bson:"-"`
+
+	ID        bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time     `bson:"created_at"    json:"createdAt"`
+	UpdatedAt time.Time     `bson:"updated_at"    json:"updatedAt"`
+	SiteID    string        `bson:"site_id"       json:"site"`
+
+	URL    string   `bson:"url"    json:"url"`
+	Secret string   `bson:"secret" json:"secret"` // HMAC key used to sign requests
+	Events []string `bson:"events" json:"events"` // empty means all events
+	Active bool     `bson:"active" json:"active"`
+}
+
+// WebhooksList represents a list of webhooks
+type WebhooksList []*Webhook
+
+// WebhookDelivery represents a webhook request, with all its attempts
+type WebhookDelivery struct {
+	dbSession *DBSession `bson:"-"`
+
+	ID        bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time     `bson:"created_at"    json:"createdAt"`
+	WebhookID bson.ObjectId `bson:"webhook_id"    json:"webhook"`
+	SiteID    string        `bson:"site_id"       json:"site"`
+
+	Event       string    `bson:"event"                  json:"event"`
+	Payload     string    `bson:"payload"                json:"payload"`
+	Attempts    int       `bson:"attempts"               json:"attempts"`
+	StatusCode  int       `bson:"status_code,omitempty"  json:"statusCode,omitempty"`
+	Error       string    `bson:"error,omitempty"        json:"error,omitempty"`
+	Success     bool      `bson:"success"                json:"success"`
+	DeliveredAt time.Time `bson:"delivered_at,omitempty" json:"deliveredAt,omitempty"`
+}
+
+// WebhookDeliveriesList represents a list of webhook deliveries
+type WebhookDeliveriesList []*WebhookDelivery
+
+//
+// DBSession
+//
+
+// WebhooksCol returns the webhooks collection
+func (session *DBSession) WebhooksCol() *mgo.Collection {
+	return session.DB().C(webhooksColName)
+}
+
+// WebhookDeliveriesCol returns the webhook deliveries collection
+func (session *DBSession) WebhookDeliveriesCol() *mgo.Collection {
+	return session.DB().C(webhookDeliveriesColName)
+}
+
+// EnsureWebhooksIndexes ensures indexes on webhooks and webhook deliveries collections
+func (session *DBSession) EnsureWebhooksIndexes() {
+	index := mgo.Index{
+		Key:        []string{"site_id"},
+		Background: true,
+	}
+
+	err := session.WebhooksCol().EnsureIndex(index)
+	if err != nil {
+		panic(err)
+	}
+
+	index = mgo.Index{
+		Key:        []string{"webhook_id", "-created_at"},
+		Background: true,
+	}
+
+	err = session.WebhookDeliveriesCol().EnsureIndex(index)
+	if err != nil {
+		panic(err)
+	}
+
+	// deliveries log is automatically pruned
+	index = mgo.Index{
+		Key:         []string{"created_at"},
+		Background:  true,
+		ExpireAfter: webhookDeliveriesTTL,
+	}
+
+	err = session.WebhookDeliveriesCol().EnsureIndex(index)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// FindWebhook finds a webhook by id
+func (session *DBSession) FindWebhook(webhookID bson.ObjectId) *Webhook {
+	var result Webhook
+
+	if err := session.WebhooksCol().FindId(webhookID).One(&result); err != nil {
+		return nil
+	}
+
+	result.dbSession = session
+
+	return &result
+}
+
+// CreateWebhook creates a new webhook in database
+// Side effect: 'Id', 'CreatedAt' and 'UpdatedAt' fields are set on webhook record
+func (session *DBSession) CreateWebhook(webhook *Webhook) error {
+	webhook.ID = bson.NewObjectId()
+
+	now := time.Now()
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	if err := session.WebhooksCol().Insert(webhook); err != nil {
+		return err
+	}
+
+	webhook.dbSession = session
+
+	return nil
+}
+
+// CreateWebhookDelivery creates a new webhook delivery in database
+// Side effect: 'Id' and 'CreatedAt' fields are set on webhook delivery record
+func (session *DBSession) CreateWebhookDelivery(delivery *WebhookDelivery) error {
+	delivery.ID = bson.NewObjectId()
+	delivery.CreatedAt = time.Now()
+
+	if err := session.WebhookDeliveriesCol().Insert(delivery); err != nil {
+		return err
+	}
+
+	delivery.dbSession = session
+
+	return nil
+}
+
+// ValidWebhookEvent returns true if given event exists
+func ValidWebhookEvent(event string) bool {
+	for _, webhookEvent := range WebhookEvents {
+		if event == webhookEvent {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// Webhook
+//
+
+// FindSite fetches site that webhook belongs to
+func (webhook *Webhook) FindSite() *Site {
+	return webhook.dbSession.FindSite(webhook.SiteID)
+}
+
+// HandleEvent returns true if webhook must be fired for given event
+func (webhook *Webhook) HandleEvent(event string) bool {
+	if !webhook.Active {
+		return false
+	}
+
+	if len(webhook.Events) == 0 {
+		return true
+	}
+
+	for _, webhookEvent := range webhook.Events {
+		if webhookEvent == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (webhook *Webhook) deliveriesBaseQuery() *mgo.Query {
+	return webhook.dbSession.WebhookDeliveriesCol().Find(bson.M{"webhook_id": webhook.ID})
+}
+
+// DeliveriesNb returns the total number of logged deliveries
+func (webhook *Webhook) DeliveriesNb() int {
+	result, err := webhook.deliveriesBaseQuery().Count()
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+// FindDeliveries fetches logged deliveries, most recent first
+func (webhook *Webhook) FindDeliveries(skip int, limit int) *WebhookDeliveriesList {
+	result := WebhookDeliveriesList{}
+
+	query := webhook.deliveriesBaseQuery().Sort("-created_at")
+
+	if skip > 0 {
+		query = query.Skip(skip)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.All(&result); err != nil {
+		panic(err)
+	}
+
+	// inject dbSession in all result items
+	for _, delivery := range result {
+		delivery.dbSession = webhook.dbSession
+	}
+
+	return &result
+}
+
+// Delete deletes webhook and its deliveries log from database
+func (webhook *Webhook) Delete() error {
+	if err := webhook.dbSession.WebhooksCol().RemoveId(webhook.ID); err != nil {
+		return err
+	}
+
+	// @todo Catch and report errors
+	webhook.dbSession.WebhookDeliveriesCol().RemoveAll(bson.M{"webhook_id": webhook.ID})
+
+	return nil
+}
+
+// Update updates webhook in database
+func (webhook *Webhook) Update(newWebhook *Webhook) (bool, error) {
+	var set, modifier bson.D
+
+	// URL
+	if webhook.URL != newWebhook.URL {
+		webhook.URL = newWebhook.URL
+
+		set = append(set, bson.DocElem{"url", webhook.URL})
+	}
+
+	// Events
+	if !reflect.DeepEqual(webhook.Events, newWebhook.Events) {
+		webhook.Events = newWebhook.Events
+
+		set = append(set, bson.DocElem{"events", webhook.Events})
+	}
+
+	// Active
+	if webhook.Active != newWebhook.Active {
+		webhook.Active = newWebhook.Active
+
+		set = append(set, bson.DocElem{"active", webhook.Active})
+	}
+
+	if len(set) > 0 {
+		webhook.UpdatedAt = time.Now()
+		set = append(set, bson.DocElem{"updated_at", webhook.UpdatedAt})
+
+		modifier = append(modifier, bson.DocElem{"$set", set})
+
+		return true, webhook.dbSession.WebhooksCol().UpdateId(webhook.ID, modifier)
+	}
+
+	return false, nil
+}
+
+//
+// WebhookDelivery
+//
+
+// Update saves delivery result in database
+func (delivery *WebhookDelivery) Update() error {
+	fields := bson.M{
+		"attempts":    delivery.Attempts,
+		"status_code": delivery.StatusCode,
+		"error":       delivery.Error,
+		"success":     delivery.Success,
+	}
+
+	if !delivery.DeliveredAt.IsZero() {
+		fields["delivered_at"] = delivery.DeliveredAt
+	}
+
+	return delivery.dbSession.WebhookDeliveriesCol().UpdateId(delivery.ID, bson.M{"$set": fields})
+}
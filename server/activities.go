@@ -24,9 +24,15 @@ func (app *Application) handleGetActivities(rw http.ResponseWriter, req *http.Re
 			return
 		}
 
-		pagination.Total = site.ActivitiesNb()
+		filter, err := newListFilter(req, site, models.ActivitiesSorts)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		pagination.Total = site.FilteredActivitiesNb(filter)
 
-		activities := site.FindActivities(pagination.Skip, pagination.PerPage)
+		activities := site.FindFilteredActivities(filter, pagination.Skip, pagination.PerPage)
 
 		// fetch covers
 		images := []*models.Image{}
@@ -69,8 +75,7 @@ func (app *Application) handlePostActivities(rw http.ResponseWriter, req *http.R
 		return
 	}
 
-	currentUser := app.getCurrentUser(req)
-	if site.UserID != currentUser.ID {
+	if !app.siteOwnerAccess(req, site) {
 		unauthorized(rw)
 		return
 	}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/kowa/token"
+)
+
+type apiTokenJSON struct {
+	APIToken models.APIToken `json:"apiToken"`
+}
+
+// apiTokenAllowsMethod returns true if API token scopes allow given HTTP method
+func apiTokenAllowsMethod(apiToken *models.APIToken, method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return apiToken.HasScope(models.APITokenScopeRead) || apiToken.HasScope(models.APITokenScopeWrite)
+	default:
+		return apiToken.HasScope(models.APITokenScopeWrite)
+	}
+}
+
+// GET /api/me/api_tokens
+func (app *Application) handleGetAPITokens(rw http.ResponseWriter, req *http.Request) {
+	currentUser := app.getCurrentUser(req)
+
+	app.render.JSON(rw, http.StatusOK, renderMap{"apiTokens": currentUser.FindAPITokens()})
+}
+
+// POST /api/me/api_tokens
+func (app *Application) handlePostAPITokens(rw http.ResponseWriter, req *http.Request) {
+	currentDBSession := app.getCurrentDBSession(req)
+	currentUser := app.getCurrentUser(req)
+
+	var reqJSON apiTokenJSON
+
+	if err := json.NewDecoder(req.Body).Decode(&reqJSON); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimSpace(reqJSON.APIToken.Name)
+	if name == "" {
+		http.Error(rw, "Missing name field in API token record", http.StatusBadRequest)
+		return
+	}
+
+	// check scopes
+	if len(reqJSON.APIToken.Scopes) == 0 {
+		http.Error(rw, "Missing scopes field in API token record", http.StatusBadRequest)
+		return
+	}
+
+	for _, scope := range reqJSON.APIToken.Scopes {
+		valid := false
+		for _, availableScope := range models.APITokenScopes {
+			if scope == availableScope {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			http.Error(rw, "Invalid scope: "+scope, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// check site
+	if siteID := reqJSON.APIToken.SiteID; siteID != "" {
+		site := currentDBSession.FindSite(siteID)
+		if (site == nil) || (site.UserID != currentUser.ID) {
+			http.Error(rw, "Site not found", http.StatusBadRequest)
+			return
+		}
+	}
+
+	value := token.NewPersonalToken()
+
+	apiToken := &models.APIToken{
+		UserID: currentUser.ID,
+		Name:   name,
+		Hash:   token.HashPersonalToken(value),
+		Hint:   token.PersonalTokenHint(value),
+		Scopes: reqJSON.APIToken.Scopes,
+		SiteID: reqJSON.APIToken.SiteID,
+	}
+
+	if err := currentDBSession.CreateAPIToken(apiToken); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to create API token", http.StatusInternalServerError)
+		return
+	}
+
+	// token value is never displayed again
+	app.render.JSON(rw, http.StatusCreated, renderMap{"apiToken": apiToken, "token": value})
+}
+
+// DELETE /api/me/api_tokens/{api_token_id}
+func (app *Application) handleDeleteAPIToken(rw http.ResponseWriter, req *http.Request) {
+	currentDBSession := app.getCurrentDBSession(req)
+	currentUser := app.getCurrentUser(req)
+
+	apiTokenID := mux.Vars(req)["api_token_id"]
+	if !bson.IsObjectIdHex(apiTokenID) {
+		http.NotFound(rw, req)
+		return
+	}
+
+	apiToken := currentDBSession.FindAPIToken(bson.ObjectIdHex(apiTokenID))
+	if (apiToken == nil) || (apiToken.UserID != currentUser.ID) {
+		http.NotFound(rw, req)
+		return
+	}
+
+	if err := apiToken.Delete(); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to delete API token", http.StatusInternalServerError)
+		return
+	}
+
+	// returns deleted API token
+	app.render.JSON(rw, http.StatusOK, renderMap{"apiToken": apiToken})
+}
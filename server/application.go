@@ -2,7 +2,9 @@ package server
 
 import (
 	"log"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/RangelReale/osin"
@@ -11,11 +13,15 @@ import (
 	"github.com/unrolled/render"
 
 	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/derivatives"
 	"github.com/aymerick/kowa/mailers"
 	"github.com/aymerick/kowa/models"
 	"github.com/aymerick/kowa/themes"
 )
 
+// number of workers generating images derivatives
+const derivativesWorkersNb = 2
+
 // Application represents the application
 type Application struct {
 	port         string
@@ -24,6 +30,19 @@ type Application struct {
 	oauthStorage *oauthStorage
 	oauthServer  *osin.Server
 	buildMaster  *BuildMaster
+
+	webhookSender *webhookSender
+
+	derivativesPipeline *derivatives.Pipeline
+
+	publicRateLimiter *rateLimiter
+	trustedProxies    []*net.IPNet
+
+	// failed two-factor authentication attempts per user
+	twoFactorFailures *rateLimiter
+
+	// password reset mails sent per user
+	passwordResetMails *rateLimiter
 }
 
 // NewApplication instanciates a new application
@@ -41,14 +60,38 @@ func NewApplication() *Application {
 	oauthStorage := newOAuthStorage()
 	oauthServer := osin.NewServer(osinConfig, oauthStorage)
 
-	return &Application{
+	trustedProxies, err := parseTrustedProxies(strings.Split(viper.GetString("trusted_proxies"), ","))
+	if err != nil {
+		log.Fatalf("ERROR: Invalid trusted_proxies setting: %v", err)
+	}
+
+	app := &Application{
 		port:         viper.GetString("port"),
 		render:       render.New(render.Options{}),
 		dbSession:    dbSession,
 		oauthStorage: oauthStorage,
 		oauthServer:  oauthServer,
 		buildMaster:  NewBuildMaster(),
+
+		webhookSender: newWebhookSender(),
+
+		derivativesPipeline: derivatives.NewPipeline(dbSession, derivativesWorkersNb),
+
+		publicRateLimiter: newRateLimiter(publicRateLimitMax, publicRateLimitPeriod),
+		trustedProxies:    trustedProxies,
+
+		twoFactorFailures: newRateLimiter(twoFactorMaxFailures, twoFactorLockoutPeriod),
+
+		passwordResetMails: newRateLimiter(passwordResetMailsMax, passwordResetMailsPeriod),
 	}
+
+	// fire webhooks on build completion
+	app.buildMaster.onJobDone = app.onBuildJobDone
+
+	// rebuild site when image derivatives are generated
+	app.derivativesPipeline.OnDone = app.onDerivativesDone
+
+	return app
 }
 
 // Setup setups the application server
@@ -74,6 +117,10 @@ func (app *Application) Run() {
 	// start build master
 	app.buildMaster.run()
 
+	// start derivatives generation, and resume images left pending by a previous run
+	app.derivativesPipeline.Start()
+	go app.derivativesPipeline.EnqueuePending()
+
 	// TODO: only for dev
 	if viper.GetBool("serve_output") {
 		go app.buildMaster.serveSites()
@@ -86,6 +133,9 @@ func (app *Application) Run() {
 
 // Stop stops the application server
 func (app *Application) Stop() {
+	// wait for pending derivatives first, as they trigger site builds
+	app.derivativesPipeline.Stop()
+
 	// stop build master
 	app.buildMaster.stop()
 }
@@ -107,12 +157,28 @@ func (app *Application) onSiteChange(site *models.Site) {
 
 	// rebuild changed site
 	app.buildSite(site)
+
+	app.fireWebhooks(site, models.WebhookEventSiteChanged)
+}
+
+// onDerivativesDone is called when image derivatives are generated
+func (app *Application) onDerivativesDone(img *models.Image, err error) {
+	if site := img.FindSite(); site != nil {
+		app.buildSite(site)
+	}
 }
 
 // onSiteDeletion is called when site is deleted
 func (app *Application) onSiteDeletion(site *models.Site) {
 	// delete build
 	app.deleteBuild(site, site.BuildDir())
+
+	// webhooks are loaded before being deleted with site
+	app.fireWebhooks(site, models.WebhookEventSiteDeleted)
+
+	if err := site.DeleteWebhooks(); err != nil {
+		log.Printf("ERROR: %v", err)
+	}
 }
 
 //
@@ -133,6 +199,13 @@ func (app *Application) getCurrentUser(req *http.Request) *models.User {
 	return nil
 }
 
+func (app *Application) getCurrentAPIToken(req *http.Request) *models.APIToken {
+	if currentAPIToken := context.Get(req, "currentAPIToken"); currentAPIToken != nil {
+		return currentAPIToken.(*models.APIToken)
+	}
+	return nil
+}
+
 func (app *Application) getCurrentSite(req *http.Request) *models.Site {
 	if currentSite := context.Get(req, "currentSite"); currentSite != nil {
 		return currentSite.(*models.Site)
@@ -175,6 +248,34 @@ func (app *Application) getCurrentMember(req *http.Request) *models.Member {
 	return nil
 }
 
+func (app *Application) getCurrentLocation(req *http.Request) *models.Location {
+	if currentLocation := context.Get(req, "currentLocation"); currentLocation != nil {
+		return currentLocation.(*models.Location)
+	}
+	return nil
+}
+
+func (app *Application) getCurrentMessage(req *http.Request) *models.Message {
+	if currentMessage := context.Get(req, "currentMessage"); currentMessage != nil {
+		return currentMessage.(*models.Message)
+	}
+	return nil
+}
+
+func (app *Application) getCurrentWebhook(req *http.Request) *models.Webhook {
+	if currentWebhook := context.Get(req, "currentWebhook"); currentWebhook != nil {
+		return currentWebhook.(*models.Webhook)
+	}
+	return nil
+}
+
+func (app *Application) getCurrentSubscriber(req *http.Request) *models.Subscriber {
+	if currentSubscriber := context.Get(req, "currentSubscriber"); currentSubscriber != nil {
+		return currentSubscriber.(*models.Subscriber)
+	}
+	return nil
+}
+
 func (app *Application) getCurrentImage(req *http.Request) *models.Image {
 	if currentImage := context.Get(req, "currentImage"); currentImage != nil {
 		return currentImage.(*models.Image)
@@ -206,6 +307,8 @@ func (app *Application) handleGetConfig(rw http.ResponseWriter, req *http.Reques
 		},
 		"themes":  themes.AllConf(), // @todo Translate theme palettes names
 		"domains": viper.GetStringSlice("service_domains"),
+
+		"twoFactorRequired": twoFactorRequired(),
 	}
 
 	app.render.JSON(rw, http.StatusOK, result)
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"archive/zip"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/i18n"
+
+	"github.com/aymerick/kowa/archive"
+	"github.com/aymerick/kowa/helpers"
+)
+
+const (
+	maxImportSize = 512 * 1024 * 1024
+
+	// max uncompressed size of an imported archive
+	maxImportContentSize = 2 * 1024 * 1024 * 1024
+)
+
+// GET /sites/{site_id}/export
+func (app *Application) handleExportSite(rw http.ResponseWriter, req *http.Request) {
+	site := app.getCurrentSite(req)
+	if site != nil {
+		rw.Header().Set("Content-Type", "application/zip")
+		rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.zip\"", site.ID))
+
+		if err := archive.Export(site, rw); err != nil {
+			// headers are already sent
+			log.Printf("ERROR: Failed to export site %s: %v", site.ID, err)
+		}
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// POST /sites/import
+func (app *Application) handleImportSite(rw http.ResponseWriter, req *http.Request) {
+	currentDBSession := app.getCurrentDBSession(req)
+	currentUser := app.getCurrentUser(req)
+
+	req.Body = http.MaxBytesReader(rw, req.Body, maxImportSize)
+
+	file, _, err := req.FormFile("file")
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Archive file not found in multipart", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	size, err := file.Seek(0, os.SEEK_END)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to read archive", http.StatusInternalServerError)
+		return
+	}
+
+	zr, err := zip.NewReader(file, size)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Invalid archive", http.StatusBadRequest)
+		return
+	}
+
+	// check uncompressed sizes before reading anything
+	total, largestUpload := archive.Size(zr)
+	if total > maxImportContentSize {
+		http.Error(rw, "Archive content is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if maxSize := uploadMaxSize(); (maxSize > 0) && (largestUpload > uint64(maxSize)) {
+		http.Error(rw, "Archived file is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if quota := siteStorageQuota(); quota > 0 {
+		if used := archive.UploadsSize(zr); used > uint64(quota) {
+			quotaExceeded(rw, int64(used), quota)
+			return
+		}
+	}
+
+	manifest, err := archive.ReadManifest(zr)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Invalid archive", http.StatusBadRequest)
+		return
+	}
+
+	T := i18n.MustTfunc(currentUser.Lang)
+
+	// validate site id
+	errors := make(map[string]string)
+
+	siteID := strings.TrimSpace(req.FormValue("id"))
+	if siteID == "" {
+		siteID = manifest.SiteID
+	}
+
+	if siteID != helpers.NormalizeToSiteID(siteID) {
+		errors["id"] = T("signup_id_invalid")
+	} else if len(siteID) < 3 {
+		errors["id"] = T("signup_id_too_short")
+	} else if exSite := currentDBSession.FindSite(siteID); exSite != nil {
+		errors["id"] = T("signup_id_not_available")
+	}
+
+	if len(errors) > 0 {
+		app.render.JSON(rw, http.StatusBadRequest, renderMap{"errors": errors})
+		return
+	}
+
+	site, err := archive.Import(currentDBSession, zr, siteID, currentUser.ID)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to import site", http.StatusInternalServerError)
+		return
+	}
+
+	// site content has changed
+	app.onSiteChange(site)
+
+	app.render.JSON(rw, http.StatusCreated, renderMap{"site": site})
+}
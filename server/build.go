@@ -36,6 +36,9 @@ type BuildMaster struct {
 	currentJobs   map[string]*buildJob
 	throttledJobs map[string]*buildJob
 
+	// called when a job ends
+	onJobDone func(kind string, siteID string, failed bool)
+
 	stopChan chan bool
 }
 
@@ -138,6 +141,10 @@ func (master *BuildMaster) run() {
 				// remove from current jobs
 				delete(master.currentJobs, jobKey)
 
+				if master.onJobDone != nil {
+					go master.onJobDone(job.kind, job.siteID, job.failed)
+				}
+
 				if newJob := master.throttledJobs[jobKey]; newJob != nil {
 					delete(master.throttledJobs, jobKey)
 
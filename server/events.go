@@ -4,14 +4,46 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/aymerick/kowa/models"
 )
 
+const (
+	// default time range when listing occurrences of a recurring event
+	defaultOccurrencesRange = 365 * 24 * time.Hour
+)
+
 type eventJSON struct {
 	Event models.Event `json:"event"`
 }
 
+type eventOverrideJSON struct {
+	Occurrence models.EventOverride `json:"occurrence"`
+}
+
+type eventOccurrenceJSON struct {
+	ID         string    `json:"id"`
+	StartDate  time.Time `json:"startDate"`
+	EndDate    time.Time `json:"endDate"`
+	Title      string    `json:"title"`
+	Place      string    `json:"place"`
+	Overridden bool      `json:"overridden"`
+}
+
+// checkEventRecurrence checks event recurrence rule and occurrence overrides
+func checkEventRecurrence(rw http.ResponseWriter, event *models.Event, loc *time.Location) bool {
+	if err := event.CheckRecurrence(loc); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
 // GET /events?site={site_id}
 // GET /sites/{site_id}/events
 func (app *Application) handleGetEvents(rw http.ResponseWriter, req *http.Request) {
@@ -24,20 +56,35 @@ func (app *Application) handleGetEvents(rw http.ResponseWriter, req *http.Reques
 			return
 		}
 
-		pagination.Total = site.EventsNb()
+		filter, err := newListFilter(req, site, models.EventsSorts)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-		events := site.FindEvents(pagination.Skip, pagination.PerPage)
+		pagination.Total = site.FilteredEventsNb(filter)
 
-		// fetch covers
+		events := site.FindFilteredEvents(filter, pagination.Skip, pagination.PerPage)
+
+		// fetch covers and locations
 		images := []*models.Image{}
+		locations := []*models.Location{}
+		locationIDs := map[bson.ObjectId]bool{}
 
 		for _, event := range *events {
 			if image := event.FindCover(); image != nil {
 				images = append(images, image)
 			}
+
+			if (event.Location != "") && !locationIDs[event.Location] {
+				if location := event.FindLocation(); location != nil {
+					locations = append(locations, location)
+					locationIDs[event.Location] = true
+				}
+			}
 		}
 
-		app.render.JSON(rw, http.StatusOK, renderMap{"events": events, "meta": pagination, "images": images})
+		app.render.JSON(rw, http.StatusOK, renderMap{"events": events, "meta": pagination, "images": images, "locations": locations})
 	} else {
 		http.NotFound(rw, req)
 	}
@@ -69,12 +116,15 @@ func (app *Application) handlePostEvents(rw http.ResponseWriter, req *http.Reque
 		return
 	}
 
-	currentUser := app.getCurrentUser(req)
-	if site.UserID != currentUser.ID {
+	if !app.siteOwnerAccess(req, site) {
 		unauthorized(rw)
 		return
 	}
 
+	if !checkEventRecurrence(rw, event, site.TZLocation()) || !app.checkLocation(rw, req, event.Location, site.ID) {
+		return
+	}
+
 	if err := currentDBSession.CreateEvent(event); err != nil {
 		log.Printf("ERROR: %v", err)
 		http.Error(rw, "Failed to create event", http.StatusInternalServerError)
@@ -109,7 +159,16 @@ func (app *Application) handleUpdateEvent(rw http.ResponseWriter, req *http.Requ
 			return
 		}
 
+		site := app.getCurrentSite(req)
+
+		// existing overrides must still match an occurrence
+		reqJSON.Event.Overrides = event.Overrides
+
 		// @todo [security] Check all fields !
+		if !checkEventRecurrence(rw, &reqJSON.Event, site.TZLocation()) || !app.checkLocation(rw, req, reqJSON.Event.Location, event.SiteID) {
+			return
+		}
+
 		updated, err := event.Update(&reqJSON.Event)
 		if err != nil {
 			log.Printf("ERROR: %v", err)
@@ -118,8 +177,6 @@ func (app *Application) handleUpdateEvent(rw http.ResponseWriter, req *http.Requ
 		}
 
 		if updated {
-			site := app.getCurrentSite(req)
-
 			// site content has changed
 			app.onSiteChange(site)
 		}
@@ -149,3 +206,114 @@ func (app *Application) handleDeleteEvent(rw http.ResponseWriter, req *http.Requ
 		http.NotFound(rw, req)
 	}
 }
+
+// GET /events/{event_id}/occurrences?from={date}&to={date}
+func (app *Application) handleGetEventOccurrences(rw http.ResponseWriter, req *http.Request) {
+	event := app.getCurrentEvent(req)
+	if event != nil {
+		site := app.getCurrentSite(req)
+
+		from := time.Now()
+		to := from.Add(defaultOccurrencesRange)
+
+		var err error
+
+		if value := req.URL.Query().Get("from"); value != "" {
+			if from, err = time.Parse(time.RFC3339, value); err != nil {
+				http.Error(rw, "Invalid from parameter", http.StatusBadRequest)
+				return
+			}
+		}
+
+		if value := req.URL.Query().Get("to"); value != "" {
+			if to, err = time.Parse(time.RFC3339, value); err != nil {
+				http.Error(rw, "Invalid to parameter", http.StatusBadRequest)
+				return
+			}
+		}
+
+		occurrences := []*eventOccurrenceJSON{}
+
+		for _, occurrence := range event.Occurrences(from, to, site.TZLocation()) {
+			occurrences = append(occurrences, &eventOccurrenceJSON{
+				ID:         occurrence.OccurrenceID,
+				StartDate:  occurrence.StartDate,
+				EndDate:    occurrence.EndDate,
+				Title:      occurrence.Title,
+				Place:      occurrence.Place,
+				Overridden: event.Overrides[occurrence.OccurrenceID] != nil,
+			})
+		}
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"occurrences": occurrences})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// PUT /events/{event_id}/occurrences/{occurrence_id}
+func (app *Application) handleSetEventOverride(rw http.ResponseWriter, req *http.Request) {
+	event := app.getCurrentEvent(req)
+	if event != nil {
+		site := app.getCurrentSite(req)
+
+		occurrenceID := mux.Vars(req)["occurrence_id"]
+		if !event.HasOccurrence(occurrenceID, site.TZLocation()) {
+			http.Error(rw, "Invalid occurrence: "+occurrenceID, http.StatusBadRequest)
+			return
+		}
+
+		var reqJSON eventOverrideJSON
+
+		if err := json.NewDecoder(req.Body).Decode(&reqJSON); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+			return
+		}
+
+		override := &reqJSON.Occurrence
+		override.ID = occurrenceID
+
+		if err := event.SetOverride(override); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to set occurrence override", http.StatusInternalServerError)
+			return
+		}
+
+		// site content has changed
+		app.onSiteChange(site)
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"occurrence": override})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// DELETE /events/{event_id}/occurrences/{occurrence_id}
+func (app *Application) handleDeleteEventOverride(rw http.ResponseWriter, req *http.Request) {
+	event := app.getCurrentEvent(req)
+	if event != nil {
+		occurrenceID := mux.Vars(req)["occurrence_id"]
+
+		override := event.Overrides[occurrenceID]
+		if override == nil {
+			http.NotFound(rw, req)
+			return
+		}
+
+		if err := event.DeleteOverride(occurrenceID); err != nil {
+			http.Error(rw, "Failed to delete occurrence override", http.StatusInternalServerError)
+			return
+		}
+
+		site := app.getCurrentSite(req)
+
+		// site content has changed
+		app.onSiteChange(site)
+
+		// returns deleted override
+		app.render.JSON(rw, http.StatusOK, renderMap{"occurrence": override})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/json"
 	"log"
 	"net/http"
 
@@ -9,7 +10,20 @@ import (
 	"gopkg.in/mgo.v2/bson"
 )
 
-var acceptedFileContentTypes = []string{"application/pdf", "text/plain"}
+var acceptedFileContentTypes = []string{
+	"application/pdf",
+	"text/plain",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.ms-excel",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"application/vnd.oasis.opendocument.text",
+	"application/vnd.oasis.opendocument.spreadsheet",
+}
+
+type fileJSON struct {
+	File models.File `json:"file"`
+}
 
 // GET /files?site={site_id}
 // GET /sites/{site_id}/files
@@ -22,9 +36,11 @@ func (app *Application) handleGetFiles(rw http.ResponseWriter, req *http.Request
 			return
 		}
 
-		pagination.Total = site.FilesNb()
+		filter := newMediaFilter(req)
 
-		app.render.JSON(rw, http.StatusOK, renderMap{"files": site.FindFiles(pagination.Skip, pagination.PerPage), "meta": pagination})
+		pagination.Total = site.FilteredFilesNb(filter)
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"files": site.FindFilteredFiles(filter, pagination.Skip, pagination.PerPage), "meta": pagination})
 	} else {
 		http.NotFound(rw, req)
 	}
@@ -40,16 +56,66 @@ func (app *Application) handleGetFile(rw http.ResponseWriter, req *http.Request)
 	}
 }
 
+// GET /files/{file_id}/usages
+func (app *Application) handleGetFileUsages(rw http.ResponseWriter, req *http.Request) {
+	file := app.getCurrentFile(req)
+	if file != nil {
+		app.render.JSON(rw, http.StatusOK, renderMap{"usages": app.getCurrentSite(req).FileUsages(file)})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// PUT /files/{file_id}
+func (app *Application) handleUpdateFile(rw http.ResponseWriter, req *http.Request) {
+	file := app.getCurrentFile(req)
+	if file != nil {
+		var reqJSON fileJSON
+
+		if err := json.NewDecoder(req.Body).Decode(&reqJSON); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+			return
+		}
+
+		updated, err := file.Update(&reqJSON.File)
+		if err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to update file", http.StatusInternalServerError)
+			return
+		}
+
+		if updated {
+			// site content has changed
+			app.onSiteChange(app.getCurrentSite(req))
+		}
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"file": file})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
 // DELETE /files/{file_id}
+// DELETE /files/{file_id}?force=true
+//
+// Returns a 409 with file usages if file is in use and force parameter is not set.
 func (app *Application) handleDeleteFile(rw http.ResponseWriter, req *http.Request) {
 	file := app.getCurrentFile(req)
 	if file != nil {
+		site := app.getCurrentSite(req)
+
+		if !forceDeletion(req) {
+			if usages := site.FileUsages(file); len(usages) > 0 {
+				app.render.JSON(rw, http.StatusConflict, renderMap{"usages": usages})
+				return
+			}
+		}
+
 		if err := file.Delete(); err != nil {
 			http.Error(rw, "Failed to delete file", http.StatusInternalServerError)
 		} else {
 			// remove all references to file from site content
-			site := app.getCurrentSite(req)
-
 			if err := site.RemoveFileReferences(file); err != nil {
 				log.Printf("Failed to remove file references: %v", err.Error())
 				http.Error(rw, "Error while deleting file", http.StatusInternalServerError)
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 
@@ -10,6 +12,10 @@ import (
 
 var acceptedImageContentTypes = []string{"image/jpeg", "image/png", "image/gif"}
 
+type imageJSON struct {
+	Image models.Image `json:"image"`
+}
+
 // GET /images?site={site_id}
 // GET /sites/{site_id}/images
 func (app *Application) handleGetImages(rw http.ResponseWriter, req *http.Request) {
@@ -21,9 +27,12 @@ func (app *Application) handleGetImages(rw http.ResponseWriter, req *http.Reques
 			return
 		}
 
-		pagination.Total = site.ImagesNb()
+		filter := newMediaFilter(req)
+		filter.Kind = ""
 
-		app.render.JSON(rw, http.StatusOK, renderMap{"images": site.FindImages(pagination.Skip, pagination.PerPage), "meta": pagination})
+		pagination.Total = site.FilteredImagesNb(filter)
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"images": site.FindFilteredImages(filter, pagination.Skip, pagination.PerPage), "meta": pagination})
 	} else {
 		http.NotFound(rw, req)
 	}
@@ -39,16 +48,81 @@ func (app *Application) handleGetImage(rw http.ResponseWriter, req *http.Request
 	}
 }
 
+// GET /images/{image_id}/usages
+func (app *Application) handleGetImageUsages(rw http.ResponseWriter, req *http.Request) {
+	image := app.getCurrentImage(req)
+	if image != nil {
+		app.render.JSON(rw, http.StatusOK, renderMap{"usages": app.getCurrentSite(req).ImageUsages(image)})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// PUT /images/{image_id}
+func (app *Application) handleUpdateImage(rw http.ResponseWriter, req *http.Request) {
+	image := app.getCurrentImage(req)
+	if image != nil {
+		var reqJSON imageJSON
+
+		if err := json.NewDecoder(req.Body).Decode(&reqJSON); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+			return
+		}
+
+		if err := reqJSON.Image.ValidateEdits(); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resetDerivatives := image.CroppingChanged(&reqJSON.Image)
+
+		updated, err := image.Update(&reqJSON.Image)
+		if err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to update image", http.StatusInternalServerError)
+			return
+		}
+
+		if updated {
+			if resetDerivatives {
+				image.DeleteCustomDerivatives()
+
+				if err := app.derivativesPipeline.Enqueue(image, true); err != nil {
+					log.Printf("Failed to enqueue image derivatives: %s - %v", image.Path, err.Error())
+				}
+			}
+
+			// site content has changed
+			app.onSiteChange(app.getCurrentSite(req))
+		}
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"image": image})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
 // DELETE /images/{image_id}
+// DELETE /images/{image_id}?force=true
+//
+// Returns a 409 with image usages if image is in use and force parameter is not set.
 func (app *Application) handleDeleteImage(rw http.ResponseWriter, req *http.Request) {
 	image := app.getCurrentImage(req)
 	if image != nil {
+		site := app.getCurrentSite(req)
+
+		if !forceDeletion(req) {
+			if usages := site.ImageUsages(image); len(usages) > 0 {
+				app.render.JSON(rw, http.StatusConflict, renderMap{"usages": usages})
+				return
+			}
+		}
+
 		if err := image.Delete(); err != nil {
 			http.Error(rw, "Failed to delete image", http.StatusInternalServerError)
 		} else {
 			// remove all references to image from site content
-			site := app.getCurrentSite(req)
-
 			if err := site.RemoveImageReferences(image); err != nil {
 				log.Printf("Failed to remove image references: %v", err.Error())
 				http.Error(rw, "Error while deleting image", http.StatusInternalServerError)
@@ -90,6 +164,27 @@ func (app *Application) handleUploadImage(rw http.ResponseWriter, req *http.Requ
 		Name:   upload.name,
 		Size:   upload.info.Size(),
 		Type:   upload.ctype,
+		Status: models.ImageStatusPending,
+	}
+
+	// check image dimensions
+	width, height, err := img.Dimensions()
+	if err != nil {
+		log.Printf("Invalid image: %s - %v", img.Path, err.Error())
+		http.Error(rw, "Invalid image", http.StatusBadRequest)
+		upload.remove()
+		return
+	}
+
+	if maxDim := imageMaxDimension(); (maxDim > 0) && ((width > maxDim) || (height > maxDim)) {
+		http.Error(rw, fmt.Sprintf("Image too large: maximum dimensions are %dx%d pixels", maxDim, maxDim), http.StatusRequestEntityTooLarge)
+		upload.remove()
+		return
+	}
+
+	// apply EXIF orientation and strip GPS data
+	if _, err := img.NormalizeOriginal(); err != nil {
+		log.Printf("Failed to normalize image: %s - %v", img.Path, err.Error())
 	}
 
 	if err := currentDBSession.CreateImage(img); err != nil {
@@ -98,9 +193,9 @@ func (app *Application) handleUploadImage(rw http.ResponseWriter, req *http.Requ
 		return
 	}
 
-	// @todo Async that the day it becomes problematic
-	if err := img.GenerateDerivatives(true); err != nil {
-		log.Printf("Failed to generate image derivatives: %s - %v", img.Path, err.Error())
+	// generate derivatives in background
+	if err := app.derivativesPipeline.Enqueue(img, true); err != nil {
+		log.Printf("Failed to enqueue image derivatives: %s - %v", img.Path, err.Error())
 	}
 
 	// returns uploaded file path
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aymerick/kowa/models"
+)
+
+// date format of list filter parameters, when time is not specified
+const filterDateFormat = "2006-01-02"
+
+// create list filter from request parameters:
+//
+//	q:         full-text search
+//	from:      min date (inclusive), eg: 2015-03-01 or 2015-03-01T10:00:00+01:00
+//	to:        max date (exclusive)
+//	published: true | false
+//	sort:      sort key, prefixed by '-' for descending order, eg: -date
+//
+// Dates without time are in site timezone.
+func newListFilter(req *http.Request, site *models.Site, sorts models.ListSorts) (*models.ListFilter, error) {
+	var err error
+
+	params := req.URL.Query()
+
+	result := &models.ListFilter{
+		Text: strings.TrimSpace(params.Get("q")),
+		Sort: params.Get("sort"),
+	}
+
+	if !sorts.Valid(result.Sort) {
+		return nil, errors.New("Invalid sort parameter")
+	}
+
+	loc := site.TZLocation()
+
+	if result.From, err = parseFilterDate(params.Get("from"), loc); err != nil {
+		return nil, errors.New("Invalid from parameter")
+	}
+
+	if result.To, err = parseFilterDate(params.Get("to"), loc); err != nil {
+		return nil, errors.New("Invalid to parameter")
+	}
+
+	if published := params.Get("published"); published != "" {
+		value, err := strconv.ParseBool(published)
+		if err != nil {
+			return nil, errors.New("Invalid published parameter")
+		}
+
+		result.Published = &value
+	}
+
+	return result, nil
+}
+
+// parse a date parameter
+func parseFilterDate(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if result, err := time.ParseInLocation(filterDateFormat, value, loc); err == nil {
+		return result, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
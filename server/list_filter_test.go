@@ -0,0 +1,71 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/aymerick/kowa/models"
+)
+
+type ListFilterTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestListFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(ListFilterTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *ListFilterTestSuite) TestNewListFilter() {
+	t := suite.T()
+
+	site := &models.Site{TZ: "Europe/Paris"}
+
+	req, _ := http.NewRequest("GET", "/api/posts?site=test&q=+summer+party+&from=2015-03-01&to=2015-04-01T00:00:00Z&published=false&sort=-date", nil)
+
+	filter, err := newListFilter(req, site, models.PostsSorts)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "summer party", filter.Text)
+	assert.Equal(t, "-date", filter.Sort)
+	assert.True(t, filter.From.Equal(time.Date(2015, time.February, 28, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, filter.To.Equal(time.Date(2015, time.April, 1, 0, 0, 0, 0, time.UTC)))
+
+	if assert.NotNil(t, filter.Published) {
+		assert.False(t, *filter.Published)
+	}
+
+	// no filter
+	req, _ = http.NewRequest("GET", "/api/posts?site=test", nil)
+
+	filter, err = newListFilter(req, site, models.PostsSorts)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "", filter.Text)
+		assert.True(t, filter.From.IsZero())
+		assert.Nil(t, filter.Published)
+	}
+}
+
+func (suite *ListFilterTestSuite) TestNewListFilterErrors() {
+	t := suite.T()
+
+	site := &models.Site{}
+
+	for _, query := range []string{"sort=fullname", "sort=-", "from=yesterday", "to=2015-13-01", "published=maybe"} {
+		req, _ := http.NewRequest("GET", "/api/members?site=test&"+query, nil)
+
+		_, err := newListFilter(req, site, models.MembersSorts)
+		assert.NotNil(t, err, query)
+	}
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/models"
+)
+
+type locationJSON struct {
+	Location models.Location `json:"location"`
+}
+
+// checkLocation checks that given location belongs to given site
+func (app *Application) checkLocation(rw http.ResponseWriter, req *http.Request, locationID bson.ObjectId, siteID string) bool {
+	if locationID == "" {
+		return true
+	}
+
+	location := app.getCurrentDBSession(req).FindLocation(locationID)
+	if (location == nil) || (location.SiteID != siteID) {
+		http.Error(rw, "Location not found", http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+// GET /locations?site={site_id}
+// GET /sites/{site_id}/locations
+func (app *Application) handleGetLocations(rw http.ResponseWriter, req *http.Request) {
+	site := app.getCurrentSite(req)
+	if site != nil {
+		// fetch paginated records
+		pagination := newPagination()
+		if err := pagination.fillFromRequest(req); err != nil {
+			http.Error(rw, "Invalid pagination parameters", http.StatusBadRequest)
+			return
+		}
+
+		pagination.Total = site.LocationsNb()
+
+		locations := site.FindLocations(pagination.Skip, pagination.PerPage)
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"locations": locations, "meta": pagination})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// POST /locations
+func (app *Application) handlePostLocations(rw http.ResponseWriter, req *http.Request) {
+	currentDBSession := app.getCurrentDBSession(req)
+
+	var reqJSON locationJSON
+
+	if err := json.NewDecoder(req.Body).Decode(&reqJSON); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+		return
+	}
+
+	// @todo [security] Check all fields !
+	location := &reqJSON.Location
+
+	if location.SiteID == "" {
+		http.Error(rw, "Missing site field in location record", http.StatusBadRequest)
+		return
+	}
+
+	site := currentDBSession.FindSite(location.SiteID)
+	if site == nil {
+		http.Error(rw, "Site not found", http.StatusBadRequest)
+		return
+	}
+
+	if !app.siteOwnerAccess(req, site) {
+		unauthorized(rw)
+		return
+	}
+
+	if err := currentDBSession.CreateLocation(location); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to create location", http.StatusInternalServerError)
+		return
+	}
+
+	app.render.JSON(rw, http.StatusCreated, renderMap{"location": location})
+}
+
+// GET /locations/{location_id}
+func (app *Application) handleGetLocation(rw http.ResponseWriter, req *http.Request) {
+	location := app.getCurrentLocation(req)
+	if location != nil {
+		app.render.JSON(rw, http.StatusOK, renderMap{"location": location})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// PUT /locations/{location_id}
+func (app *Application) handleUpdateLocation(rw http.ResponseWriter, req *http.Request) {
+	location := app.getCurrentLocation(req)
+	if location != nil {
+		var reqJSON locationJSON
+
+		if err := json.NewDecoder(req.Body).Decode(&reqJSON); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+			return
+		}
+
+		// @todo [security] Check all fields !
+		updated, err := location.Update(&reqJSON.Location)
+		if err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to update location", http.StatusInternalServerError)
+			return
+		}
+
+		if updated {
+			site := app.getCurrentSite(req)
+
+			// site content has changed
+			app.onSiteChange(site)
+		}
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"location": location})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// DELETE /locations/{location_id}
+func (app *Application) handleDeleteLocation(rw http.ResponseWriter, req *http.Request) {
+	location := app.getCurrentLocation(req)
+	if location != nil {
+		if err := location.Delete(); err != nil {
+			http.Error(rw, "Failed to delete location", http.StatusInternalServerError)
+		} else {
+			site := app.getCurrentSite(req)
+
+			// site content has changed
+			app.onSiteChange(site)
+
+			// returns deleted location
+			app.render.JSON(rw, http.StatusOK, renderMap{"location": location})
+		}
+	} else {
+		http.NotFound(rw, req)
+	}
+}
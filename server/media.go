@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aymerick/kowa/models"
+)
+
+// create media filter from request parameters:
+//
+//	folder: media library folder, eg: events/2015
+//	tag:    media library tag
+//	kind:   file kind, eg: document (ignored for images)
+func newMediaFilter(req *http.Request) *models.MediaFilter {
+	params := req.URL.Query()
+
+	return &models.MediaFilter{
+		Folder: params.Get("folder"),
+		Tag:    params.Get("tag"),
+		Kind:   params.Get("kind"),
+	}
+}
+
+// returns true if request asks to delete media even if it is in use
+func forceDeletion(req *http.Request) bool {
+	force, _ := strconv.ParseBool(req.URL.Query().Get("force"))
+	return force
+}
+
+// GET /sites/{site_id}/storage
+func (app *Application) handleGetSiteStorage(rw http.ResponseWriter, req *http.Request) {
+	site := app.getCurrentSite(req)
+	if site != nil {
+		app.render.JSON(rw, http.StatusOK, renderMap{"storage": site.StorageUsage(), "quota": siteStorageQuota(), "maxUploadSize": uploadMaxSize()})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// GET /media/folders?site={site_id}
+func (app *Application) handleGetMediaFolders(rw http.ResponseWriter, req *http.Request) {
+	site := app.getCurrentSite(req)
+	if site != nil {
+		app.render.JSON(rw, http.StatusOK, renderMap{"folders": site.MediaFolders(), "tags": site.MediaTags()})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// GET /media/unused?site={site_id}
+func (app *Application) handleGetUnusedMedia(rw http.ResponseWriter, req *http.Request) {
+	site := app.getCurrentSite(req)
+	if site != nil {
+		app.render.JSON(rw, http.StatusOK, renderMap{"images": site.FindUnusedImages(), "files": site.FindUnusedFiles()})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
@@ -26,9 +26,15 @@ func (app *Application) handleGetMembers(rw http.ResponseWriter, req *http.Reque
 			return
 		}
 
-		pagination.Total = site.MembersNb()
+		filter, err := newListFilter(req, site, models.MembersSorts)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		pagination.Total = site.FilteredMembersNb(filter)
 
-		members := site.FindMembers(pagination.Skip, pagination.PerPage)
+		members := site.FindFilteredMembers(filter, pagination.Skip, pagination.PerPage)
 
 		// fetch photos
 		images := []*models.Image{}
@@ -71,8 +77,7 @@ func (app *Application) handlePostMembers(rw http.ResponseWriter, req *http.Requ
 		return
 	}
 
-	currentUser := app.getCurrentUser(req)
-	if site.UserID != currentUser.ID {
+	if !app.siteOwnerAccess(req, site) {
 		unauthorized(rw)
 		return
 	}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/mailers"
+	"github.com/aymerick/kowa/models"
+)
+
+const (
+	maxMessageSubject = 200
+	maxMessageBody    = 10000
+)
+
+type messageJSON struct {
+	Message models.Message `json:"message"`
+}
+
+// POST /api/public/sites/{site_id}/contact
+func (app *Application) handlePostPublicContact(rw http.ResponseWriter, req *http.Request) {
+	currentDBSession := app.getCurrentDBSession(req)
+	site := app.getCurrentSite(req)
+
+	if err := req.ParseForm(); err != nil {
+		http.Error(rw, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+
+	redirectURL := publicRedirectURL(req, site)
+
+	if publicFormSpam(req) {
+		// pretend that everything went well
+		app.renderPublicForm(rw, req, redirectURL, "message", nil)
+		return
+	}
+
+	lang := site.Lang
+	if lang == "" {
+		lang = core.DefaultLang
+	}
+
+	T := core.MustTfunc(lang)
+
+	errors := make(map[string]string)
+
+	// check name
+	name := singleLine(req.Form.Get("name"))
+	if name == "" {
+		errors["name"] = T("contact_name_missing")
+	}
+
+	// check email format
+	emailAddr, err := mail.ParseAddress(req.Form.Get("email"))
+	if err != nil || emailAddr.Address == "" {
+		errors["email"] = T("contact_email_invalid")
+	}
+
+	// check subject
+	subject := messageSubject(req.Form.Get("subject"))
+
+	// check body
+	body := strings.TrimSpace(req.Form.Get("body"))
+	if body == "" {
+		errors["body"] = T("contact_body_missing")
+	} else if len(body) > maxMessageBody {
+		errors["body"] = T("contact_body_too_long")
+	}
+
+	if len(errors) > 0 {
+		app.render.JSON(rw, http.StatusBadRequest, renderMap{"errors": errors})
+		return
+	}
+
+	message := &models.Message{
+		SiteID:  site.ID,
+		Name:    name,
+		Email:   emailAddr.Address,
+		Subject: subject,
+		Body:    body,
+	}
+
+	if err := currentDBSession.CreateMessage(message); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to create message", http.StatusInternalServerError)
+		return
+	}
+
+	if site.Email != "" {
+		// deliver message to site owner
+		go mailers.NewContactMailer(message, site).Send()
+	}
+
+	app.renderPublicForm(rw, req, redirectURL, "message", message)
+}
+
+// GET /messages?site={site_id}
+// GET /sites/{site_id}/messages
+func (app *Application) handleGetMessages(rw http.ResponseWriter, req *http.Request) {
+	site := app.getCurrentSite(req)
+	if site != nil {
+		// fetch paginated records
+		pagination := newPagination()
+		if err := pagination.fillFromRequest(req); err != nil {
+			http.Error(rw, "Invalid pagination parameters", http.StatusBadRequest)
+			return
+		}
+
+		pagination.Total = site.MessagesNb()
+
+		messages := site.FindMessages(pagination.Skip, pagination.PerPage)
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"messages": messages, "meta": pagination})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// GET /messages/{message_id}
+func (app *Application) handleGetMessage(rw http.ResponseWriter, req *http.Request) {
+	message := app.getCurrentMessage(req)
+	if message != nil {
+		app.render.JSON(rw, http.StatusOK, renderMap{"message": message})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// PUT /messages/{message_id}
+func (app *Application) handleUpdateMessage(rw http.ResponseWriter, req *http.Request) {
+	message := app.getCurrentMessage(req)
+	if message != nil {
+		var reqJSON messageJSON
+
+		if err := json.NewDecoder(req.Body).Decode(&reqJSON); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+			return
+		}
+
+		// only the read flag can be changed
+		if err := message.SetRead(reqJSON.Message.Read); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to update message", http.StatusInternalServerError)
+			return
+		}
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"message": message})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// DELETE /messages/{message_id}
+func (app *Application) handleDeleteMessage(rw http.ResponseWriter, req *http.Request) {
+	message := app.getCurrentMessage(req)
+	if message != nil {
+		if err := message.Delete(); err != nil {
+			http.Error(rw, "Failed to delete message", http.StatusInternalServerError)
+		} else {
+			// returns deleted message
+			app.render.JSON(rw, http.StatusOK, renderMap{"message": message})
+		}
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// singleLine replaces line breaks and other control characters by spaces, so that value can't inject mail headers
+func singleLine(value string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+
+		return r
+	}, value))
+}
+
+// messageSubject returns a single line subject, truncated to maximum length, that is safe to use in mail headers
+func messageSubject(value string) string {
+	result := singleLine(value)
+
+	if utf8.RuneCountInString(result) > maxMessageSubject {
+		result = strings.TrimSpace(string([]rune(result)[:maxMessageSubject]))
+	}
+
+	return result
+}
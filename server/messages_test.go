@@ -0,0 +1,44 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MessagesTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestMessagesTestSuite(t *testing.T) {
+	suite.Run(t, new(MessagesTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *MessagesTestSuite) TestSingleLine() {
+	t := suite.T()
+
+	assert.Equal(t, "Hello", singleLine(" Hello "))
+	assert.Equal(t, "Hello  Bcc: evil@example.com", singleLine("Hello\r\nBcc: evil@example.com"))
+	assert.Equal(t, "Hello world", singleLine("Hello\tworld\n"))
+	assert.Equal(t, "Café à côté", singleLine("Café à côté"))
+}
+
+func (suite *MessagesTestSuite) TestMessageSubject() {
+	t := suite.T()
+
+	assert.Equal(t, "Hello  Bcc: evil@example.com", messageSubject(" Hello\r\nBcc: evil@example.com"))
+
+	// truncated by runes, not by bytes
+	subject := messageSubject(strings.Repeat("é", maxMessageSubject+10))
+	assert.Equal(t, maxMessageSubject, utf8.RuneCountInString(subject))
+	assert.True(t, utf8.ValidString(subject))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/RangelReale/osin"
 	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/kowa/token"
 	"github.com/gorilla/context"
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
@@ -72,6 +73,20 @@ func (app *Application) corsMiddleware() func(next http.Handler) http.Handler {
 	return result.Handler
 }
 
+// middleware: limits the number of requests per client on public endpoints
+func (app *Application) publicRateLimitMiddleware(next http.Handler) http.Handler {
+	fn := func(rw http.ResponseWriter, req *http.Request) {
+		if !app.publicRateLimiter.allow(clientIP(req, app.trustedProxies)) {
+			http.Error(rw, "Too many requests", 429)
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
 // middleware: ensures user is NOT authenticated
 func (app *Application) ensureNotAuthMiddleware(next http.Handler) http.Handler {
 	fn := func(rw http.ResponseWriter, req *http.Request) {
@@ -100,26 +115,94 @@ func (app *Application) ensureAuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		var accessData *osin.AccessData
-		accessData, err = app.oauthServer.Storage.LoadAccess(authValue[7:])
-		if err != nil {
+		currentDBSession := app.getCurrentDBSession(req)
+
+		var userID string
+
+		if bearer := authValue[7:]; token.IsPersonalToken(bearer) {
+			// personal API token
+			apiToken := currentDBSession.FindAPITokenByHash(token.HashPersonalToken(bearer))
+			if apiToken == nil {
+				unauthorized(rw)
+				return
+			}
+
+			if !apiTokenAllowsMethod(apiToken, req.Method) {
+				http.Error(rw, "Insufficient token scope", http.StatusForbidden)
+				return
+			}
+
+			if err := apiToken.Touch(); err != nil {
+				log.Printf("ERROR: %v", err)
+			}
+
+			context.Set(req, "currentAPIToken", apiToken)
+
+			userID = apiToken.UserID
+		} else {
+			// OAuth access token
+			var accessData *osin.AccessData
+			accessData, err = app.oauthServer.Storage.LoadAccess(bearer)
+			if err != nil {
+				unauthorized(rw)
+				return
+			}
+
+			// @todo Check accessData.CreatedAt
+
+			var ok bool
+			userID, ok = accessData.UserData.(string)
+			if !ok || userID == "" {
+				unauthorized(rw)
+				return
+			}
+		}
+
+		if currentUser := currentDBSession.FindUser(userID); currentUser != nil {
+			context.Set(req, "currentUser", currentUser)
+		} else {
 			unauthorized(rw)
 			return
 		}
 
-		// @todo Check accessData.CreatedAt
+		next.ServeHTTP(rw, req)
+	}
 
-		userID, ok := accessData.UserData.(string)
-		if !ok || userID == "" {
-			unauthorized(rw)
+	return http.HandlerFunc(fn)
+}
+
+// middleware: ensures current user enabled two-factor authentication, when that is mandatory
+func (app *Application) ensureTwoFactorMiddleware(next http.Handler) http.Handler {
+	fn := func(rw http.ResponseWriter, req *http.Request) {
+		if twoFactorRequired() && !app.getCurrentUser(req).TwoFactor {
+			app.render.JSON(rw, http.StatusForbidden, renderMap{"error": errTwoFactorEnrollmentRequired})
 			return
 		}
 
-		currentDBSession := app.getCurrentDBSession(req)
+		next.ServeHTTP(rw, req)
+	}
 
-		if currentUser := currentDBSession.FindUser(userID); currentUser != nil {
-			context.Set(req, "currentUser", currentUser)
-		} else {
+	return http.HandlerFunc(fn)
+}
+
+// middleware: ensures request is NOT authenticated with a personal API token
+func (app *Application) ensureNotAPITokenMiddleware(next http.Handler) http.Handler {
+	fn := func(rw http.ResponseWriter, req *http.Request) {
+		if app.getCurrentAPIToken(req) != nil {
+			http.Error(rw, "Not allowed with a personal API token", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// middleware: ensures request is NOT authenticated with a personal API token restricted to a single site
+func (app *Application) ensureNotSiteAPITokenMiddleware(next http.Handler) http.Handler {
+	fn := func(rw http.ResponseWriter, req *http.Request) {
+		if apiToken := app.getCurrentAPIToken(req); (apiToken != nil) && (apiToken.SiteID != "") {
 			unauthorized(rw)
 			return
 		}
@@ -130,6 +213,20 @@ func (app *Application) ensureAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(fn)
 }
 
+// siteOwnerAccess returns true if current request is allowed to access given site
+func (app *Application) siteOwnerAccess(req *http.Request, site *models.Site) bool {
+	if site.UserID != app.getCurrentUser(req).ID {
+		return false
+	}
+
+	if apiToken := app.getCurrentAPIToken(req); (apiToken != nil) && !apiToken.AllowSite(site.ID) {
+		// personal API token restricted to another site
+		return false
+	}
+
+	return true
+}
+
 // middleware: ensures that currently authenticated user is allowed to access a /users/{user_id}/* requests
 func (app *Application) ensureUserAccessMiddleware(next http.Handler) http.Handler {
 	fn := func(rw http.ResponseWriter, req *http.Request) {
@@ -210,6 +307,38 @@ func (app *Application) ensureSiteMiddleware(next http.Handler) http.Handler {
 			}
 		}
 
+		// location
+		if currentSite == nil {
+			currentLocation := app.getCurrentLocation(req)
+			if currentLocation != nil {
+				currentSite = currentLocation.FindSite()
+			}
+		}
+
+		// message
+		if currentSite == nil {
+			currentMessage := app.getCurrentMessage(req)
+			if currentMessage != nil {
+				currentSite = currentMessage.FindSite()
+			}
+		}
+
+		// subscriber
+		if currentSite == nil {
+			currentSubscriber := app.getCurrentSubscriber(req)
+			if currentSubscriber != nil {
+				currentSite = currentSubscriber.FindSite()
+			}
+		}
+
+		// webhook
+		if currentSite == nil {
+			currentWebhook := app.getCurrentWebhook(req)
+			if currentWebhook != nil {
+				currentSite = currentWebhook.FindSite()
+			}
+		}
+
 		// image
 		if currentSite == nil {
 			currentImage := app.getCurrentImage(req)
@@ -255,7 +384,7 @@ func (app *Application) ensureSiteOwnerAccessMiddleware(next http.Handler) http.
 			panic("Should have site")
 		}
 
-		if currentSite.UserID != currentUser.ID {
+		if !app.siteOwnerAccess(req, currentSite) {
 			unauthorized(rw)
 			return
 		}
@@ -386,6 +515,107 @@ func (app *Application) ensureMemberMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(fn)
 }
 
+// middleware: ensures location exists and injects 'currentLocation' in context
+func (app *Application) ensureLocationMiddleware(next http.Handler) http.Handler {
+	fn := func(rw http.ResponseWriter, req *http.Request) {
+		currentDBSession := app.getCurrentDBSession(req)
+
+		vars := mux.Vars(req)
+		locationID := vars["location_id"]
+		if locationID == "" {
+			panic("Should have location_id")
+		}
+
+		if currentLocation := currentDBSession.FindLocation(bson.ObjectIdHex(locationID)); currentLocation != nil {
+			context.Set(req, "currentLocation", currentLocation)
+		} else {
+			http.NotFound(rw, req)
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// middleware: ensures message exists and injects 'currentMessage' in context
+func (app *Application) ensureMessageMiddleware(next http.Handler) http.Handler {
+	fn := func(rw http.ResponseWriter, req *http.Request) {
+		currentDBSession := app.getCurrentDBSession(req)
+
+		vars := mux.Vars(req)
+		messageID := vars["message_id"]
+		if messageID == "" {
+			panic("Should have message_id")
+		}
+
+		if currentMessage := currentDBSession.FindMessage(bson.ObjectIdHex(messageID)); currentMessage != nil {
+			context.Set(req, "currentMessage", currentMessage)
+		} else {
+			http.NotFound(rw, req)
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// middleware: ensures subscriber exists and injects 'currentSubscriber' in context
+func (app *Application) ensureSubscriberMiddleware(next http.Handler) http.Handler {
+	fn := func(rw http.ResponseWriter, req *http.Request) {
+		currentDBSession := app.getCurrentDBSession(req)
+
+		vars := mux.Vars(req)
+		subscriberID := vars["subscriber_id"]
+		if subscriberID == "" {
+			panic("Should have subscriber_id")
+		}
+
+		if currentSubscriber := currentDBSession.FindSubscriber(bson.ObjectIdHex(subscriberID)); currentSubscriber != nil {
+			context.Set(req, "currentSubscriber", currentSubscriber)
+		} else {
+			http.NotFound(rw, req)
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// middleware: ensures webhook exists and injects 'currentWebhook' in context
+func (app *Application) ensureWebhookMiddleware(next http.Handler) http.Handler {
+	fn := func(rw http.ResponseWriter, req *http.Request) {
+		currentDBSession := app.getCurrentDBSession(req)
+
+		vars := mux.Vars(req)
+		webhookID := vars["webhook_id"]
+		if webhookID == "" {
+			panic("Should have webhook_id")
+		}
+
+		if !bson.IsObjectIdHex(webhookID) {
+			http.NotFound(rw, req)
+			return
+		}
+
+		if currentWebhook := currentDBSession.FindWebhook(bson.ObjectIdHex(webhookID)); currentWebhook != nil {
+			context.Set(req, "currentWebhook", currentWebhook)
+		} else {
+			http.NotFound(rw, req)
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
 // middleware: ensures image exists and injects 'currentImage' in context
 func (app *Application) ensureImageMiddleware(next http.Handler) http.Handler {
 	fn := func(rw http.ResponseWriter, req *http.Request) {
@@ -29,6 +29,13 @@ func (app *Application) handleOauthToken(rw http.ResponseWriter, req *http.Reque
 			if (user != nil) && (user.Status == models.UserStatusActive) {
 				err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(ar.Password))
 				if err == nil {
+					if user.TwoFactor {
+						// second step: a TOTP or recovery code must be provided along with credentials
+						if !app.checkOauthTwoFactor(resp, user, req.Form.Get("otp")) {
+							break
+						}
+					}
+
 					ar.UserData = user.ID
 					ar.Authorized = true
 				}
@@ -42,6 +49,33 @@ func (app *Application) handleOauthToken(rw http.ResponseWriter, req *http.Reque
 	osin.OutputJSON(resp, rw, req)
 }
 
+// checkOauthTwoFactor checks two-factor authentication code provided during token exchange
+func (app *Application) checkOauthTwoFactor(resp *osin.Response, user *models.User, code string) bool {
+	if code == "" {
+		resp.SetError(errTwoFactorRequired, "Two-factor authentication code required")
+		return false
+	}
+
+	ok, err := app.checkTwoFactorCode(user, code)
+	if err == errTwoFactorTooManyFailures {
+		resp.SetError(errTwoFactorLocked, "Too many failed two-factor authentication attempts, try again later")
+		return false
+	}
+
+	if err != nil {
+		resp.SetError(osin.E_SERVER_ERROR, "")
+		resp.InternalError = err
+		return false
+	}
+
+	if !ok {
+		resp.SetError(errTwoFactorInvalid, "Invalid two-factor authentication code")
+		return false
+	}
+
+	return true
+}
+
 // POST /oauth/revoke
 func (app *Application) handleOauthRevoke(rw http.ResponseWriter, req *http.Request) {
 	resp := app.oauthServer.NewResponse()
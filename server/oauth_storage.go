@@ -193,3 +193,9 @@ func (storage *oauthStorage) RemoveRefresh(token string) error {
 
 	return storage.accessesCol().Update(selector, modifier)
 }
+
+// RemoveUserAccesses removes all access and refresh tokens granted to given user
+func (storage *oauthStorage) RemoveUserAccesses(userID string) error {
+	_, err := storage.accessesCol().RemoveAll(bson.M{"userdata": userID})
+	return err
+}
@@ -5,6 +5,8 @@ import (
 	"log"
 	"net/http"
 
+	"gopkg.in/mgo.v2/bson"
+
 	"github.com/aymerick/kowa/models"
 )
 
@@ -24,9 +26,15 @@ func (app *Application) handleGetPages(rw http.ResponseWriter, req *http.Request
 			return
 		}
 
-		pagination.Total = site.PagesNb()
+		filter, err := newListFilter(req, site, models.PagesSorts)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		pagination.Total = site.FilteredPagesNb(filter)
 
-		pages := site.FindPages(pagination.Skip, pagination.PerPage)
+		pages := site.FindFilteredPages(filter, pagination.Skip, pagination.PerPage)
 
 		// fetch covers
 		images := []*models.Image{}
@@ -69,12 +77,16 @@ func (app *Application) handlePostPages(rw http.ResponseWriter, req *http.Reques
 		return
 	}
 
-	currentUser := app.getCurrentUser(req)
-	if site.UserID != currentUser.ID {
+	if !app.siteOwnerAccess(req, site) {
 		unauthorized(rw)
 		return
 	}
 
+	if !app.checkPageParent(page, page.ParentID, currentDBSession) {
+		http.Error(rw, "Invalid parent page", http.StatusBadRequest)
+		return
+	}
+
 	if err := currentDBSession.CreatePage(page); err != nil {
 		log.Printf("ERROR: %v", err)
 		http.Error(rw, "Failed to create page", http.StatusInternalServerError)
@@ -109,6 +121,11 @@ func (app *Application) handleUpdatePage(rw http.ResponseWriter, req *http.Reque
 			return
 		}
 
+		if !app.checkPageParent(page, reqJSON.Page.ParentID, app.getCurrentDBSession(req)) {
+			http.Error(rw, "Invalid parent page", http.StatusBadRequest)
+			return
+		}
+
 		// @todo [security] Check all fields !
 		updated, err := page.Update(&reqJSON.Page)
 		if err != nil {
@@ -149,3 +166,55 @@ func (app *Application) handleDeletePage(rw http.ResponseWriter, req *http.Reque
 		http.NotFound(rw, req)
 	}
 }
+
+// PUT /pages/order
+func (app *Application) handlePutPagesOrder(rw http.ResponseWriter, req *http.Request) {
+	var ids []bson.ObjectId
+
+	if err := json.NewDecoder(req.Body).Decode(&ids); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+		return
+	}
+
+	site := app.getCurrentSite(req)
+
+	order := 1
+	for _, id := range ids {
+		if err := site.UpdatePageOrder(id, order); err != nil {
+			// previous pages may have been reordered
+			if order > 1 {
+				app.onSiteChange(site)
+			}
+
+			if err == models.ErrPageNotFound {
+				http.Error(rw, "Unknown page: "+id.Hex(), http.StatusBadRequest)
+			} else {
+				log.Printf("ERROR: %v", err)
+				http.Error(rw, "Failed to update pages order", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		order++
+	}
+
+	// site content has changed
+	app.onSiteChange(site)
+
+	app.render.JSON(rw, http.StatusOK, renderMap{})
+}
+
+// checks that given parent page can be set on page
+func (app *Application) checkPageParent(page *models.Page, parentID bson.ObjectId, dbSession *models.DBSession) bool {
+	if parentID == "" {
+		return true
+	}
+
+	parent := dbSession.FindPage(parentID)
+	if parent == nil {
+		return false
+	}
+
+	return page.CanHaveParent(parent)
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"code.google.com/p/go.crypto/bcrypt"
+
+	"github.com/nicksnyder/go-i18n/i18n"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/mailers"
+	"github.com/aymerick/kowa/token"
+)
+
+const (
+	// max password reset mails sent to a user during period
+	passwordResetMailsMax    = 3
+	passwordResetMailsPeriod = time.Hour
+)
+
+// POST /api/password/forgot
+func (app *Application) handlePasswordForgot(rw http.ResponseWriter, req *http.Request) {
+	currentDBSession := app.getCurrentDBSession(req)
+
+	if err := req.ParseForm(); err != nil {
+		http.Error(rw, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+
+	emailAddr, err := mail.ParseAddress(req.Form.Get("email"))
+	if err != nil || emailAddr.Address == "" {
+		T := i18n.MustTfunc(core.DefaultLang)
+
+		app.render.JSON(rw, http.StatusBadRequest, renderMap{"errors": map[string]string{"email": T("signup_email_invalid")}})
+		return
+	}
+
+	if user := currentDBSession.FindUserByEmail(emailAddr.Address); user != nil {
+		if app.passwordResetMails.allow(user.ID) {
+			// send password reset email
+			go mailers.NewPasswordResetMailer(user).Send()
+		} else {
+			log.Printf("Too many password reset requests for user %s, mail not sent", user.ID)
+		}
+	}
+
+	// don't disclose if email is registered
+	app.render.JSON(rw, http.StatusOK, renderMap{"response": "ok"})
+}
+
+// POST /api/password/reset
+func (app *Application) handlePasswordReset(rw http.ResponseWriter, req *http.Request) {
+	currentDBSession := app.getCurrentDBSession(req)
+
+	if err := req.ParseForm(); err != nil {
+		http.Error(rw, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+
+	T := i18n.MustTfunc(core.DefaultLang)
+
+	tok := token.Decode(req.Form.Get("token"))
+	if (tok == nil) || (tok.PasswordResetUser() == "") || tok.Expired() {
+		// Missing, invalid or expired token
+		app.render.JSON(rw, http.StatusUnauthorized, renderMap{"errors": map[string]string{"token": T("password_reset_token_invalid")}})
+		return
+	}
+
+	user := currentDBSession.FindUser(tok.PasswordResetUser())
+	if user == nil {
+		http.NotFound(rw, req)
+		return
+	}
+
+	if !tok.PasswordResetValid(user) {
+		// token was already used, or password was changed since
+		app.render.JSON(rw, http.StatusUnauthorized, renderMap{"errors": map[string]string{"token": T("password_reset_token_invalid")}})
+		return
+	}
+
+	if user.Lang != "" {
+		T = i18n.MustTfunc(user.Lang)
+	}
+
+	// check password length
+	password := req.Form.Get("password")
+	if len(password) < 8 {
+		app.render.JSON(rw, http.StatusBadRequest, renderMap{"errors": map[string]string{"password": T("signup_password_too_weak")}})
+		return
+	}
+
+	// encrypt password
+	encryptedPassword, errPass := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if errPass != nil {
+		http.Error(rw, "Failed to encrypt password", http.StatusInternalServerError)
+		return
+	}
+
+	if err := user.SetPassword(string(encryptedPassword)); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to update password", http.StatusInternalServerError)
+		return
+	}
+
+	// email address ownership is proven
+	if !user.AccountValidated() {
+		user.SetAccountValidated()
+	}
+
+	// logout all sessions
+	if err := app.oauthStorage.RemoveUserAccesses(user.ID); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to revoke accesses", http.StatusInternalServerError)
+		return
+	}
+
+	// API tokens may have been created by someone who had access to the account
+	if err := user.RemoveAPITokens(); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to revoke API tokens", http.StatusInternalServerError)
+		return
+	}
+
+	app.render.JSON(rw, http.StatusOK, renderMap{"user": user})
+}
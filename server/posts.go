@@ -24,9 +24,15 @@ func (app *Application) handleGetPosts(rw http.ResponseWriter, req *http.Request
 			return
 		}
 
-		pagination.Total = site.PostsNb()
+		filter, err := newListFilter(req, site, models.PostsSorts)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-		posts := site.FindPosts(pagination.Skip, pagination.PerPage, false)
+		pagination.Total = site.FilteredPostsNb(filter)
+
+		posts := site.FindFilteredPosts(filter, pagination.Skip, pagination.PerPage)
 
 		// fetch covers
 		images := []*models.Image{}
@@ -69,8 +75,7 @@ func (app *Application) handlePostPosts(rw http.ResponseWriter, req *http.Reques
 		return
 	}
 
-	currentUser := app.getCurrentUser(req)
-	if site.UserID != currentUser.ID {
+	if !app.siteOwnerAccess(req, site) {
 		unauthorized(rw)
 		return
 	}
@@ -84,6 +89,9 @@ func (app *Application) handlePostPosts(rw http.ResponseWriter, req *http.Reques
 	// site content has changed
 	app.onSiteChange(site)
 
+	// notify subscribers
+	app.sendPostNewsletter(site, post)
+
 	app.render.JSON(rw, http.StatusCreated, renderMap{"post": post})
 }
 
@@ -122,6 +130,9 @@ func (app *Application) handleUpdatePost(rw http.ResponseWriter, req *http.Reque
 
 			// site content has changed
 			app.onSiteChange(site)
+
+			// notify subscribers
+			app.sendPostNewsletter(site, post)
 		}
 
 		app.render.JSON(rw, http.StatusOK, renderMap{"post": post})
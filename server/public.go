@@ -0,0 +1,68 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/models"
+)
+
+// publicFormSpam returns true if public form was filled by a spam bot
+func publicFormSpam(req *http.Request) bool {
+	if req.Form.Get(core.PublicFormHoneypot) != "" {
+		log.Printf("Spam detected on public form: %s", req.URL.Path)
+		return true
+	}
+
+	return false
+}
+
+// publicRedirectURL returns the URL to redirect to after a public form submission, if any
+func publicRedirectURL(req *http.Request, site *models.Site) string {
+	result := req.Form.Get("redirect")
+	if (result != "") && !isSiteURL(site, result) {
+		// don't be an open redirector
+		return ""
+	}
+
+	return result
+}
+
+// renderPublicForm renders response to a public form submission
+func (app *Application) renderPublicForm(rw http.ResponseWriter, req *http.Request, redirectURL string, key string, record interface{}) {
+	if redirectURL != "" {
+		http.Redirect(rw, req, redirectURL, http.StatusSeeOther)
+	} else {
+		app.render.JSON(rw, http.StatusCreated, renderMap{key: record})
+	}
+}
+
+// isSiteURL returns true if given URL is located under site base URL
+func isSiteURL(site *models.Site, rawURL string) bool {
+	base, err := url.Parse(site.BaseUrl())
+	if err != nil {
+		return false
+	}
+
+	target, err := url.Parse(rawURL)
+	if (err != nil) || (target.Opaque != "") || (target.User != nil) {
+		return false
+	}
+
+	if (target.Scheme != base.Scheme) || !strings.EqualFold(target.Host, base.Host) {
+		return false
+	}
+
+	basePath := strings.TrimSuffix(base.Path, "/")
+	if basePath == "" {
+		return true
+	}
+
+	targetPath := path.Clean("/" + target.Path)
+
+	return (targetPath == basePath) || strings.HasPrefix(targetPath, basePath+"/")
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/aymerick/kowa/models"
+)
+
+type PublicTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestPublicTestSuite(t *testing.T) {
+	suite.Run(t, new(PublicTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *PublicTestSuite) TestIsSiteURL() {
+	t := suite.T()
+
+	site := &models.Site{ID: "foo", CustomURL: "http://foo.example.com"}
+
+	assert.True(t, isSiteURL(site, "http://foo.example.com"))
+	assert.True(t, isSiteURL(site, "http://foo.example.com/"))
+	assert.True(t, isSiteURL(site, "http://foo.example.com/events/bar/?ok=1"))
+	assert.True(t, isSiteURL(site, "http://FOO.example.com/"))
+
+	assert.False(t, isSiteURL(site, "http://foo.example.com.evil.net/"))
+	assert.False(t, isSiteURL(site, "http://foo.example.com@evil.net/"))
+	assert.False(t, isSiteURL(site, "http://user@foo.example.com/"))
+	assert.False(t, isSiteURL(site, "https://foo.example.com/"))
+	assert.False(t, isSiteURL(site, "//evil.net/"))
+	assert.False(t, isSiteURL(site, "/events/"))
+	assert.False(t, isSiteURL(site, "javascript:alert(1)"))
+}
+
+func (suite *PublicTestSuite) TestIsSiteURLWithPath() {
+	t := suite.T()
+
+	site := &models.Site{ID: "foo", CustomURL: "http://example.com/foo"}
+
+	assert.True(t, isSiteURL(site, "http://example.com/foo"))
+	assert.True(t, isSiteURL(site, "http://example.com/foo/events/"))
+
+	assert.False(t, isSiteURL(site, "http://example.com/"))
+	assert.False(t, isSiteURL(site, "http://example.com/foobar/"))
+	assert.False(t, isSiteURL(site, "http://example.com/foo/../bar/"))
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	publicRateLimitMax    = 5
+	publicRateLimitPeriod = 10 * time.Minute
+)
+
+// rateLimiter limits the number of hits per client during a period of time
+type rateLimiter struct {
+	max    int
+	period time.Duration
+
+	mutex sync.Mutex
+	hits  map[string][]time.Time
+}
+
+// newRateLimiter instanciates a new rateLimiter
+func newRateLimiter(max int, period time.Duration) *rateLimiter {
+	return &rateLimiter{
+		max:    max,
+		period: period,
+		hits:   make(map[string][]time.Time),
+	}
+}
+
+// allow registers a hit for given key and returns false if limit is exceeded
+func (limiter *rateLimiter) allow(key string) bool {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	now := time.Now()
+	limit := now.Add(-limiter.period)
+
+	// forget old hits
+	hits := []time.Time{}
+	for _, hit := range limiter.hits[key] {
+		if hit.After(limit) {
+			hits = append(hits, hit)
+		}
+	}
+
+	if len(hits) >= limiter.max {
+		limiter.hits[key] = hits
+		return false
+	}
+
+	limiter.hits[key] = append(hits, now)
+
+	// cleanup from time to time
+	if len(limiter.hits) > 1000 {
+		limiter.cleanup(limit)
+	}
+
+	return true
+}
+
+// exceeded returns true if limit is exceeded for given key, without registering a hit
+func (limiter *rateLimiter) exceeded(key string) bool {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	limit := time.Now().Add(-limiter.period)
+
+	count := 0
+	for _, hit := range limiter.hits[key] {
+		if hit.After(limit) {
+			count++
+		}
+	}
+
+	return count >= limiter.max
+}
+
+// reset forgets all hits for given key
+func (limiter *rateLimiter) reset(key string) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	delete(limiter.hits, key)
+}
+
+// cleanup removes all keys without recent hits
+func (limiter *rateLimiter) cleanup(limit time.Time) {
+	for key, hits := range limiter.hits {
+		if (len(hits) == 0) || hits[len(hits)-1].Before(limit) {
+			delete(limiter.hits, key)
+		}
+	}
+}
+
+// parseTrustedProxies parses given trusted proxies IP addresses or CIDR ranges
+func parseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	result := []*net.IPNet{}
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("Invalid IP address: %s", value)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+
+			result = append(result, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, ipNet)
+	}
+
+	return result, nil
+}
+
+// clientIP returns the IP address of request client. The X-Forwarded-For header is only read when request comes
+// from a trusted proxy, and then the right-most untrusted hop is returned.
+func clientIP(req *http.Request, trustedProxies []*net.IPNet) string {
+	result, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		result = req.RemoteAddr
+	}
+
+	if !trustedIP(result, trustedProxies) {
+		return result
+	}
+
+	hops := strings.Split(strings.Join(req.Header["X-Forwarded-For"], ","), ",")
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+		if ip == nil {
+			// forged or malformed header
+			break
+		}
+
+		result = ip.String()
+
+		if !trustedIP(result, trustedProxies) {
+			break
+		}
+	}
+
+	return result
+}
+
+// trustedIP returns true if given IP address belongs to trusted proxies
+func trustedIP(value string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false
+	}
+
+	for _, ipNet := range trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
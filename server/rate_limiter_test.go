@@ -0,0 +1,104 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RateLimiterTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestRateLimiterTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimiterTestSuite))
+}
+
+func newTestClientRequest(remoteAddr string, forwarded ...string) *http.Request {
+	req, _ := http.NewRequest("POST", "http://localhost/sites/foo/contact", nil)
+	req.RemoteAddr = remoteAddr
+
+	for _, value := range forwarded {
+		req.Header.Add("X-Forwarded-For", value)
+	}
+
+	return req
+}
+
+//
+// Tests
+//
+
+func (suite *RateLimiterTestSuite) TestAllow() {
+	t := suite.T()
+
+	limiter := newRateLimiter(2, publicRateLimitPeriod)
+
+	assert.True(t, limiter.allow("1.2.3.4"))
+	assert.True(t, limiter.allow("1.2.3.4"))
+	assert.False(t, limiter.allow("1.2.3.4"))
+
+	assert.True(t, limiter.allow("5.6.7.8"))
+}
+
+func (suite *RateLimiterTestSuite) TestExceededAndReset() {
+	t := suite.T()
+
+	limiter := newRateLimiter(2, publicRateLimitPeriod)
+
+	assert.False(t, limiter.exceeded("bob"))
+
+	limiter.allow("bob")
+	assert.False(t, limiter.exceeded("bob"))
+
+	limiter.allow("bob")
+	assert.True(t, limiter.exceeded("bob"))
+	assert.True(t, limiter.exceeded("bob"))
+	assert.False(t, limiter.exceeded("alice"))
+
+	limiter.reset("bob")
+	assert.False(t, limiter.exceeded("bob"))
+}
+
+func (suite *RateLimiterTestSuite) TestParseTrustedProxies() {
+	t := suite.T()
+
+	proxies, err := parseTrustedProxies([]string{"", " 10.0.0.1 ", "192.168.0.0/16", "::1"})
+	if assert.Nil(t, err) {
+		assert.Len(t, proxies, 3)
+	}
+
+	_, err = parseTrustedProxies([]string{"foo"})
+	assert.NotNil(t, err)
+
+	_, err = parseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.NotNil(t, err)
+}
+
+func (suite *RateLimiterTestSuite) TestClientIP() {
+	t := suite.T()
+
+	// no trusted proxy: header is ignored
+	assert.Equal(t, "1.2.3.4", clientIP(newTestClientRequest("1.2.3.4:1234", "5.6.7.8"), nil))
+
+	proxies, _ := parseTrustedProxies([]string{"10.0.0.0/8"})
+
+	// untrusted client: header is ignored
+	assert.Equal(t, "1.2.3.4", clientIP(newTestClientRequest("1.2.3.4:1234", "5.6.7.8"), proxies))
+
+	// trusted proxy
+	assert.Equal(t, "5.6.7.8", clientIP(newTestClientRequest("10.0.0.1:1234", "5.6.7.8"), proxies))
+	assert.Equal(t, "10.0.0.1", clientIP(newTestClientRequest("10.0.0.1:1234"), proxies))
+
+	// right-most untrusted hop, forged left-most values are ignored
+	assert.Equal(t, "5.6.7.8", clientIP(newTestClientRequest("10.0.0.1:1234", "9.9.9.9, 5.6.7.8, 10.0.0.2"), proxies))
+	assert.Equal(t, "5.6.7.8", clientIP(newTestClientRequest("10.0.0.1:1234", "9.9.9.9", "5.6.7.8"), proxies))
+
+	// malformed hop
+	assert.Equal(t, "5.6.7.8", clientIP(newTestClientRequest("10.0.0.1:1234", "foo, 5.6.7.8"), proxies))
+	assert.Equal(t, "10.0.0.1", clientIP(newTestClientRequest("10.0.0.1:1234", "5.6.7.8, foo"), proxies))
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/mailers"
+	"github.com/aymerick/kowa/models"
+)
+
+// registrationFormField returns form field name for given registration custom field
+func registrationFormField(field *models.EventRegistrationField) string {
+	return fmt.Sprintf("fields[%s]", field.Name)
+}
+
+// POST /api/public/events/{event_id}/registrations
+func (app *Application) handlePostPublicRegistration(rw http.ResponseWriter, req *http.Request) {
+	event := app.getCurrentEvent(req)
+	site := app.getCurrentSite(req)
+
+	if err := req.ParseForm(); err != nil {
+		http.Error(rw, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+
+	redirectURL := publicRedirectURL(req, site)
+
+	if publicFormSpam(req) {
+		// pretend that everything went well
+		app.renderPublicForm(rw, req, redirectURL, "registration", nil)
+		return
+	}
+
+	lang := site.Lang
+	if lang == "" {
+		lang = core.DefaultLang
+	}
+
+	T := core.MustTfunc(lang)
+
+	errors := make(map[string]string)
+
+	if !event.RegistrationOpen() {
+		if event.RegistrationEnabled && event.RegistrationFull() {
+			errors["event"] = T("registration_full")
+		} else {
+			errors["event"] = T("registration_closed")
+		}
+
+		app.render.JSON(rw, http.StatusForbidden, renderMap{"errors": errors})
+		return
+	}
+
+	// check name
+	name := strings.TrimSpace(req.Form.Get("name"))
+	if name == "" {
+		errors["name"] = T("registration_name_missing")
+	}
+
+	// check email format
+	emailAddr, err := mail.ParseAddress(req.Form.Get("email"))
+	if err != nil || emailAddr.Address == "" {
+		errors["email"] = T("registration_email_invalid")
+	}
+
+	// check custom fields
+	fields := make(map[string]string)
+
+	for _, field := range event.RegistrationFields {
+		value := strings.TrimSpace(req.Form.Get(registrationFormField(field)))
+		if value == "" {
+			if field.Required {
+				errors[registrationFormField(field)] = T("registration_field_required")
+			}
+		} else {
+			fields[field.Name] = value
+		}
+	}
+
+	if errors["email"] == "" {
+		// check if already registered
+		if registration := event.FindRegistrationByEmail(emailAddr.Address); registration != nil {
+			errors["email"] = T("registration_already_registered")
+		}
+	}
+
+	if len(errors) > 0 {
+		app.render.JSON(rw, http.StatusBadRequest, renderMap{"errors": errors})
+		return
+	}
+
+	registration := &models.Registration{
+		Name:   name,
+		Email:  emailAddr.Address,
+		Fields: fields,
+	}
+
+	if err := event.CreateRegistration(registration); err != nil {
+		switch err {
+		case models.ErrRegistrationFull:
+			errors["event"] = T("registration_full")
+			app.render.JSON(rw, http.StatusForbidden, renderMap{"errors": errors})
+		case models.ErrAlreadyRegistered:
+			errors["email"] = T("registration_already_registered")
+			app.render.JSON(rw, http.StatusBadRequest, renderMap{"errors": errors})
+		default:
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to create registration", http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	// send registration confirmation email
+	go mailers.NewRegistrationMailer(registration, event, site).Send()
+
+	if event.RegistrationFull() {
+		// registration form must be removed from built site
+		app.onSiteChange(site)
+	}
+
+	app.renderPublicForm(rw, req, redirectURL, "registration", registration)
+}
+
+// GET /events/{event_id}/registrations
+func (app *Application) handleGetRegistrations(rw http.ResponseWriter, req *http.Request) {
+	event := app.getCurrentEvent(req)
+	if event != nil {
+		// fetch paginated registrations
+		pagination := newPagination()
+		if err := pagination.fillFromRequest(req); err != nil {
+			http.Error(rw, "Invalid pagination parameters", http.StatusBadRequest)
+			return
+		}
+
+		pagination.Total = event.RegistrationsNb()
+
+		registrations := event.FindRegistrations(pagination.Skip, pagination.PerPage)
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"registrations": registrations, "meta": pagination})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// GET /events/{event_id}/registrations.csv
+func (app *Application) handleGetRegistrationsCSV(rw http.ResponseWriter, req *http.Request) {
+	event := app.getCurrentEvent(req)
+	if event != nil {
+		site := app.getCurrentSite(req)
+
+		rw.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"registrations-%s.csv\"", event.ID.Hex()))
+
+		writer := csv.NewWriter(rw)
+
+		// header
+		record := []string{"Date", "Name", "Email"}
+		for _, field := range event.RegistrationFields {
+			record = append(record, field.Label)
+		}
+
+		writer.Write(csvRecord(record))
+
+		// registrants
+		for _, registration := range *event.FindAllRegistrations() {
+			record = []string{registration.CreatedAt.In(site.TZLocation()).Format("2006-01-02 15:04:05"), registration.Name, registration.Email}
+			for _, field := range event.RegistrationFields {
+				record = append(record, registration.Fields[field.Name])
+			}
+
+			writer.Write(csvRecord(record))
+		}
+
+		writer.Flush()
+
+		if err := writer.Error(); err != nil {
+			log.Printf("ERROR: %v", err)
+		}
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// csvRecord escapes cells that a spreadsheet would interpret as formulas
+func csvRecord(record []string) []string {
+	result := make([]string, len(record))
+
+	for i, cell := range record {
+		if (cell != "") && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			cell = "'" + cell
+		}
+
+		result[i] = cell
+	}
+
+	return result
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegistrationsTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestRegistrationsTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistrationsTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *RegistrationsTestSuite) TestCSVRecord() {
+	t := suite.T()
+
+	record := []string{"2015-06-01 20:00:00", "Jean-Claude", "", "=HYPERLINK(\"http://evil.net\")", "+33 6 12 34 56 78", "-1", "@SUM(A1)", "\tfoo", "\rbar", "a=b"}
+
+	assert.Equal(t, []string{"2015-06-01 20:00:00", "Jean-Claude", "", "'=HYPERLINK(\"http://evil.net\")", "'+33 6 12 34 56 78", "'-1", "'@SUM(A1)", "'\tfoo", "'\rbar", "a=b"}, csvRecord(record))
+}
@@ -26,6 +26,10 @@ type siteThemeSettingsJSON struct {
 	SiteThemeSettings models.SiteThemeSettings `json:"siteThemeSetting"`
 }
 
+type siteNavBarLinkJSON struct {
+	SiteNavBarLink models.SiteNavBarLink `json:"siteNavBarLink"`
+}
+
 // POST /api/sites
 func (app *Application) handlePostSite(rw http.ResponseWriter, req *http.Request) {
 	currentDBSession := app.getCurrentDBSession(req)
@@ -147,6 +151,10 @@ func (app *Application) handleUpdateSite(rw http.ResponseWriter, req *http.Reque
 
 		prevBuildDir := site.BuildDir()
 
+		if !app.checkLocation(rw, req, respJSON.Site.Location, site.ID) {
+			return
+		}
+
 		// @todo [security] Check all fields !
 		updated, err := site.Update(&respJSON.Site)
 		if err != nil {
@@ -297,3 +305,105 @@ func (app *Application) handleSetThemeSettings(rw http.ResponseWriter, req *http
 		http.NotFound(rw, req)
 	}
 }
+
+// POST /sites/{site_id}/navbar-links
+// PUT /sites/{site_id}/navbar-links/{link_id}
+func (app *Application) handleSetNavBarLink(rw http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	site := app.getCurrentSite(req)
+	if site != nil {
+		var respJSON siteNavBarLinkJSON
+
+		if err := json.NewDecoder(req.Body).Decode(&respJSON); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+			return
+		}
+
+		link := &respJSON.SiteNavBarLink
+
+		if vars["link_id"] != "" {
+			// this is an update
+			existingLink := site.NavBarLinks[vars["link_id"]]
+			if existingLink == nil {
+				http.NotFound(rw, req)
+				return
+			}
+
+			if (link.ID != "") && (existingLink.ID != link.ID) {
+				http.Error(rw, "Navigation bar link id mismatch", http.StatusBadRequest)
+				return
+			} else if link.ID == "" {
+				link.ID = existingLink.ID
+			}
+		} else {
+			link.ID = ""
+		}
+
+		if err := site.SetNavBarLink(link); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to set navigation bar link", http.StatusBadRequest)
+			return
+		}
+
+		// site content has changed
+		app.onSiteChange(site)
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"siteNavBarLink": link})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// DELETE /sites/{site_id}/navbar-links/{link_id}
+func (app *Application) handleDeleteNavBarLink(rw http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	site := app.getCurrentSite(req)
+	if site != nil {
+		link := site.NavBarLinks[vars["link_id"]]
+		if link == nil {
+			http.NotFound(rw, req)
+			return
+		}
+
+		if err := site.DeleteNavBarLink(link); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to delete navigation bar link", http.StatusInternalServerError)
+			return
+		}
+
+		// site content has changed
+		app.onSiteChange(site)
+
+		// returns deleted link
+		app.render.JSON(rw, http.StatusOK, renderMap{"siteNavBarLink": link})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// PUT /sites/{site_id}/navbar/order
+func (app *Application) handlePutNavBarOrder(rw http.ResponseWriter, req *http.Request) {
+	var keys []string
+
+	if err := json.NewDecoder(req.Body).Decode(&keys); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+		return
+	}
+
+	site := app.getCurrentSite(req)
+
+	if err := site.SetNavBarOrder(keys); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to update navigation bar order", http.StatusInternalServerError)
+		return
+	}
+
+	// site content has changed
+	app.onSiteChange(site)
+
+	app.render.JSON(rw, http.StatusOK, renderMap{})
+}
@@ -0,0 +1,299 @@
+package server
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/mailers"
+	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/kowa/token"
+)
+
+// unsubscription confirmation page
+var unsubscribeTpl = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <meta name="robots" content="noindex">
+  <title>{{.Title}}</title>
+</head>
+<body>
+  <h1>{{.Title}}</h1>
+  <p>{{.Text}}</p>
+  <form method="post">
+    <input type="hidden" name="token" value="{{.Token}}">
+    <button type="submit">{{.Button}}</button>
+  </form>
+</body>
+</html>
+`))
+
+// unsubscribeVars represents unsubscription confirmation page variables
+type unsubscribeVars struct {
+	Lang   string
+	Title  string
+	Text   string
+	Button string
+	Token  string
+}
+
+// POST /api/public/sites/{site_id}/subscribers
+func (app *Application) handlePostPublicSubscriber(rw http.ResponseWriter, req *http.Request) {
+	currentDBSession := app.getCurrentDBSession(req)
+	site := app.getCurrentSite(req)
+
+	if site.Newsletter == "" {
+		http.NotFound(rw, req)
+		return
+	}
+
+	if err := req.ParseForm(); err != nil {
+		http.Error(rw, "Failed to parse form data", http.StatusBadRequest)
+		return
+	}
+
+	redirectURL := publicRedirectURL(req, site)
+
+	if publicFormSpam(req) {
+		// pretend that everything went well
+		app.renderPublicForm(rw, req, redirectURL, "subscriber", nil)
+		return
+	}
+
+	lang := site.Lang
+	if lang == "" {
+		lang = core.DefaultLang
+	}
+
+	T := core.MustTfunc(lang)
+
+	// check email format
+	emailAddr, err := mail.ParseAddress(req.Form.Get("email"))
+	if err != nil || emailAddr.Address == "" {
+		app.render.JSON(rw, http.StatusBadRequest, renderMap{"errors": map[string]string{"email": T("subscription_email_invalid")}})
+		return
+	}
+
+	subscriber := currentDBSession.FindSubscriberByEmail(site.ID, emailAddr.Address)
+	if subscriber == nil {
+		subscriber = &models.Subscriber{
+			SiteID: site.ID,
+			Email:  emailAddr.Address,
+		}
+
+		if err := currentDBSession.CreateSubscriber(subscriber); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to create subscriber", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if !subscriber.Confirmed {
+		// double opt-in: subscriber must confirm its email address
+		sendMail, err := subscriber.MarkConfirmationSent()
+		if err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to update subscriber", http.StatusInternalServerError)
+			return
+		}
+
+		if sendMail {
+			go mailers.NewSubscriptionMailer(subscriber, site).Send()
+		}
+	}
+
+	// don't disclose subscription status
+	app.renderPublicForm(rw, req, redirectURL, "subscriber", &models.Subscriber{SiteID: site.ID, Email: subscriber.Email})
+}
+
+// subscriberFromToken returns subscriber corresponding to token request parameter
+func (app *Application) subscriberFromToken(rw http.ResponseWriter, req *http.Request, decode func(*token.Token) string) *models.Subscriber {
+	currentDBSession := app.getCurrentDBSession(req)
+
+	if err := req.ParseForm(); err != nil {
+		http.Error(rw, "Failed to parse form data", http.StatusBadRequest)
+		return nil
+	}
+
+	tok := token.Decode(req.Form.Get("token"))
+	if tok == nil {
+		// Missing or invalid token
+		unauthorized(rw)
+		return nil
+	}
+
+	subscriberID := decode(tok)
+	if (subscriberID == "") || !bson.IsObjectIdHex(subscriberID) {
+		// Erroneous token
+		unauthorized(rw)
+		return nil
+	}
+
+	if tok.Expired() {
+		http.Error(rw, "Subscription token expired", http.StatusUnauthorized)
+		return nil
+	}
+
+	subscriber := currentDBSession.FindSubscriber(bson.ObjectIdHex(subscriberID))
+	if subscriber == nil {
+		http.NotFound(rw, req)
+		return nil
+	}
+
+	return subscriber
+}
+
+// GET /api/public/subscribers/confirm?token={token}
+func (app *Application) handlePublicConfirmSubscriber(rw http.ResponseWriter, req *http.Request) {
+	subscriber := app.subscriberFromToken(rw, req, (*token.Token).SubscriptionConfirmationSubscriber)
+	if subscriber == nil {
+		// there was an error
+		return
+	}
+
+	if err := subscriber.Confirm(); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to confirm subscription", http.StatusInternalServerError)
+		return
+	}
+
+	app.redirectToSubscriberSite(rw, req, subscriber)
+}
+
+// GET /api/public/subscribers/unsubscribe?token={token}
+//
+// Renders a confirmation page: link scanners and prefetchers must not unsubscribe anyone.
+func (app *Application) handlePublicUnsubscribeConfirmation(rw http.ResponseWriter, req *http.Request) {
+	subscriber := app.subscriberFromToken(rw, req, (*token.Token).UnsubscriptionSubscriber)
+	if subscriber == nil {
+		// there was an error
+		return
+	}
+
+	vars := &unsubscribeVars{
+		Token: req.Form.Get("token"),
+		Lang:  core.DefaultLang,
+	}
+
+	siteName := ""
+	if site := subscriber.FindSite(); site != nil {
+		siteName = site.Name
+
+		if site.Lang != "" {
+			vars.Lang = site.Lang
+		}
+	}
+
+	T := core.MustTfunc(vars.Lang)
+
+	vars.Title = T("unsubscribe_page_title", core.P{"SiteName": siteName})
+	vars.Text = T("unsubscribe_page_text", core.P{"SiteName": siteName, "Email": subscriber.Email})
+	vars.Button = T("unsubscribe_page_button")
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := unsubscribeTpl.Execute(rw, vars); err != nil {
+		log.Printf("ERROR: %v", err)
+	}
+}
+
+// POST /api/public/subscribers/unsubscribe?token={token}
+func (app *Application) handlePublicUnsubscribe(rw http.ResponseWriter, req *http.Request) {
+	subscriber := app.subscriberFromToken(rw, req, (*token.Token).UnsubscriptionSubscriber)
+	if subscriber == nil {
+		// there was an error
+		return
+	}
+
+	if err := subscriber.Delete(); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to unsubscribe", http.StatusInternalServerError)
+		return
+	}
+
+	if req.Form.Get("List-Unsubscribe") == "One-Click" {
+		// one-click unsubscription from mail client (RFC 8058)
+		rw.WriteHeader(http.StatusOK)
+		return
+	}
+
+	app.redirectToSubscriberSite(rw, req, subscriber)
+}
+
+func (app *Application) redirectToSubscriberSite(rw http.ResponseWriter, req *http.Request, subscriber *models.Subscriber) {
+	site := subscriber.FindSite()
+	if site == nil {
+		app.render.JSON(rw, http.StatusOK, renderMap{"subscriber": subscriber})
+		return
+	}
+
+	http.Redirect(rw, req, site.BaseUrl(), http.StatusSeeOther)
+}
+
+// GET /subscribers?site={site_id}
+// GET /sites/{site_id}/subscribers
+func (app *Application) handleGetSubscribers(rw http.ResponseWriter, req *http.Request) {
+	site := app.getCurrentSite(req)
+	if site != nil {
+		// fetch paginated records
+		pagination := newPagination()
+		if err := pagination.fillFromRequest(req); err != nil {
+			http.Error(rw, "Invalid pagination parameters", http.StatusBadRequest)
+			return
+		}
+
+		pagination.Total = site.SubscribersNb()
+
+		subscribers := site.FindSubscribers(pagination.Skip, pagination.PerPage, false)
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"subscribers": subscribers, "meta": pagination})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// DELETE /subscribers/{subscriber_id}
+func (app *Application) handleDeleteSubscriber(rw http.ResponseWriter, req *http.Request) {
+	subscriber := app.getCurrentSubscriber(req)
+	if subscriber != nil {
+		if err := subscriber.Delete(); err != nil {
+			http.Error(rw, "Failed to delete subscriber", http.StatusInternalServerError)
+		} else {
+			// returns deleted subscriber
+			app.render.JSON(rw, http.StatusOK, renderMap{"subscriber": subscriber})
+		}
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// sendPostNewsletter sends newly published post to site subscribers, if site newsletter is enabled
+func (app *Application) sendPostNewsletter(site *models.Site, post *models.Post) {
+	if (site.Newsletter != models.NewsletterPost) || !post.Published || !post.NotifiedAt.IsZero() {
+		return
+	}
+
+	// never notify the same post twice
+	if err := post.SetNotifiedAt(time.Now()); err != nil {
+		log.Printf("ERROR: %v", err)
+		return
+	}
+
+	subscribers := *site.FindConfirmedSubscribers()
+	if len(subscribers) == 0 {
+		return
+	}
+
+	go func() {
+		if _, err := mailers.NewPostNewsletter(post, site).Send(subscribers); err != nil {
+			log.Printf("ERROR: %v", err)
+		}
+	}()
+}
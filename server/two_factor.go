@@ -0,0 +1,222 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/i18n"
+	"github.com/spf13/viper"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/kowa/token"
+)
+
+const (
+	errTwoFactorRequired           = "two_factor_required"
+	errTwoFactorInvalid            = "invalid_two_factor_code"
+	errTwoFactorEnrollmentRequired = "two_factor_enrollment_required"
+	errTwoFactorLocked             = "two_factor_locked"
+
+	// failed two-factor authentication attempts allowed per user during lockout period
+	twoFactorMaxFailures   = 5
+	twoFactorLockoutPeriod = 15 * time.Minute
+)
+
+// errTwoFactorTooManyFailures is returned when checking a code for a user with too many failed attempts
+var errTwoFactorTooManyFailures = errors.New("Too many failed two-factor authentication attempts")
+
+// twoFactorRequired returns true if two-factor authentication is mandatory on that deployment
+func twoFactorRequired() bool {
+	return viper.GetBool("two_factor_required")
+}
+
+// checkTwoFactorCode checks a TOTP code or a recovery code for given user
+//
+// Failed attempts are counted per user, and codes are not checked anymore once there were too many failures, so that
+// codes can't be brute-forced.
+func (app *Application) checkTwoFactorCode(user *models.User, code string) (bool, error) {
+	if app.twoFactorFailures.exceeded(user.ID) {
+		return false, errTwoFactorTooManyFailures
+	}
+
+	ok, err := validTwoFactorCode(user, code)
+	if err != nil {
+		return false, err
+	}
+
+	if ok {
+		app.twoFactorFailures.reset(user.ID)
+	} else {
+		app.twoFactorFailures.allow(user.ID)
+	}
+
+	return ok, nil
+}
+
+// validTwoFactorCode returns true if given code is a valid TOTP code or recovery code for given user
+func validTwoFactorCode(user *models.User, code string) (bool, error) {
+	if user.TOTPSecret == "" {
+		return false, nil
+	}
+
+	if step, ok := token.ValidateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		// a code can't be used twice
+		return user.UseTOTPStep(step)
+	}
+
+	if !user.TwoFactor {
+		// recovery codes are only available once enrollment is done
+		return false, nil
+	}
+
+	return user.UseRecoveryCode(token.HashRecoveryCode(code))
+}
+
+// renderTwoFactorCodeInvalid renders an invalid code error
+func (app *Application) renderTwoFactorCodeInvalid(rw http.ResponseWriter, user *models.User) {
+	lang := user.Lang
+	if lang == "" {
+		lang = core.DefaultLang
+	}
+
+	T := i18n.MustTfunc(lang)
+
+	app.render.JSON(rw, http.StatusBadRequest, renderMap{"errors": map[string]string{"code": T("two_factor_code_invalid")}})
+}
+
+// currentUserTwoFactorCode parses form and checks two-factor authentication code of current user
+//
+// Returns the current user, or nil if there was an error.
+func (app *Application) currentUserTwoFactorCode(rw http.ResponseWriter, req *http.Request) *models.User {
+	user := app.getCurrentUser(req)
+
+	if err := req.ParseForm(); err != nil {
+		http.Error(rw, "Failed to parse form data", http.StatusBadRequest)
+		return nil
+	}
+
+	ok, err := app.checkTwoFactorCode(user, req.Form.Get("code"))
+	if err == errTwoFactorTooManyFailures {
+		http.Error(rw, "Too many failed attempts", http.StatusTooManyRequests)
+		return nil
+	}
+
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to check code", http.StatusInternalServerError)
+		return nil
+	}
+
+	if !ok {
+		app.renderTwoFactorCodeInvalid(rw, user)
+		return nil
+	}
+
+	return user
+}
+
+// renderRecoveryCodes generates new recovery codes for user and renders them
+//
+// That is the only time those codes are ever displayed.
+func (app *Application) renderRecoveryCodes(rw http.ResponseWriter, user *models.User, setter func([]string) error) {
+	codes := token.NewRecoveryCodes()
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = token.HashRecoveryCode(code)
+	}
+
+	if err := setter(hashes); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to save recovery codes", http.StatusInternalServerError)
+		return
+	}
+
+	app.render.JSON(rw, http.StatusOK, renderMap{"user": user, "recoveryCodes": codes})
+}
+
+// POST /api/me/two_factor/enroll
+func (app *Application) handleTwoFactorEnroll(rw http.ResponseWriter, req *http.Request) {
+	user := app.getCurrentUser(req)
+
+	if user.TwoFactor {
+		http.Error(rw, "Two-factor authentication already enabled", http.StatusBadRequest)
+		return
+	}
+
+	secret := token.NewTOTPSecret()
+
+	if err := user.SetTOTPSecret(secret); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to enroll", http.StatusInternalServerError)
+		return
+	}
+
+	app.render.JSON(rw, http.StatusOK, renderMap{
+		"secret": secret,
+		"uri":    token.TOTPProvisioningURI(secret, user.Email, viper.GetString("service_name")),
+	})
+}
+
+// POST /api/me/two_factor/enable
+func (app *Application) handleTwoFactorEnable(rw http.ResponseWriter, req *http.Request) {
+	if user := app.getCurrentUser(req); user.TwoFactor {
+		http.Error(rw, "Two-factor authentication already enabled", http.StatusBadRequest)
+		return
+	}
+
+	// user proves that authenticator app is correctly setup
+	user := app.currentUserTwoFactorCode(rw, req)
+	if user == nil {
+		// there was an error
+		return
+	}
+
+	app.renderRecoveryCodes(rw, user, user.EnableTwoFactor)
+}
+
+// POST /api/me/two_factor/recovery_codes
+func (app *Application) handleTwoFactorRecoveryCodes(rw http.ResponseWriter, req *http.Request) {
+	if user := app.getCurrentUser(req); !user.TwoFactor {
+		http.Error(rw, "Two-factor authentication not enabled", http.StatusBadRequest)
+		return
+	}
+
+	user := app.currentUserTwoFactorCode(rw, req)
+	if user == nil {
+		// there was an error
+		return
+	}
+
+	app.renderRecoveryCodes(rw, user, user.SetRecoveryCodes)
+}
+
+// DELETE /api/me/two_factor
+func (app *Application) handleTwoFactorDisable(rw http.ResponseWriter, req *http.Request) {
+	if twoFactorRequired() {
+		http.Error(rw, "Two-factor authentication is mandatory", http.StatusForbidden)
+		return
+	}
+
+	if user := app.getCurrentUser(req); !user.TwoFactor {
+		http.Error(rw, "Two-factor authentication not enabled", http.StatusBadRequest)
+		return
+	}
+
+	user := app.currentUserTwoFactorCode(rw, req)
+	if user == nil {
+		// there was an error
+		return
+	}
+
+	if err := user.DisableTwoFactor(); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to disable two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	app.render.JSON(rw, http.StatusOK, renderMap{"user": user})
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/aymerick/kowa/models"
+)
+
+type TwoFactorTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestTwoFactorTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *TwoFactorTestSuite) TestTwoFactorFailures() {
+	t := suite.T()
+
+	app := &Application{twoFactorFailures: newRateLimiter(twoFactorMaxFailures, twoFactorLockoutPeriod)}
+
+	user := &models.User{ID: "bob"}
+
+	for i := 0; i < twoFactorMaxFailures; i++ {
+		ok, err := app.checkTwoFactorCode(user, "123456")
+		assert.False(t, ok)
+		assert.Nil(t, err)
+	}
+
+	// locked out
+	ok, err := app.checkTwoFactorCode(user, "123456")
+	assert.False(t, ok)
+	assert.Equal(t, errTwoFactorTooManyFailures, err)
+
+	// other users are not locked out
+	ok, err = app.checkTwoFactorCode(&models.User{ID: "alice"}, "123456")
+	assert.False(t, ok)
+	assert.Nil(t, err)
+}
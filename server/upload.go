@@ -1,19 +1,49 @@
 package server
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"log"
+	"mime"
 	"net/http"
 	"os"
 
+	"github.com/spf13/viper"
+
 	"github.com/aymerick/kowa/core"
 	"github.com/aymerick/kowa/helpers"
 	"github.com/aymerick/kowa/models"
 )
 
+const (
+	// number of bytes used to sniff uploaded file content type
+	sniffLen = 512
+
+	megabyte = 1024 * 1024
+)
+
+// magic bytes of OLE compound files (legacy Microsoft Office documents)
+var oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// content types of documents stored in a ZIP container
+var zipContentTypes = map[string]bool{
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":       true,
+	"application/vnd.oasis.opendocument.text":                                 true,
+	"application/vnd.oasis.opendocument.spreadsheet":                          true,
+}
+
+// content types of documents stored in an OLE container
+var oleContentTypes = map[string]bool{
+	"application/msword":       true,
+	"application/vnd.ms-excel": true,
+}
+
 type upload struct {
 	name  string
 	ctype string
+	path  string
 	info  os.FileInfo
 }
 
@@ -21,6 +51,23 @@ func newUpload() *upload {
 	return &upload{}
 }
 
+// uploadMaxSize returns the maximum size of an uploaded file, in bytes (0 means unlimited)
+func uploadMaxSize() int64 {
+	return int64(viper.GetInt("upload_max_size")) * megabyte
+}
+
+// siteStorageQuota returns the maximum storage used by site uploads, in bytes (0 means unlimited)
+func siteStorageQuota() int64 {
+	return int64(viper.GetInt("site_storage_quota")) * megabyte
+}
+
+// imageMaxDimension returns the maximum width and height of an uploaded image, in pixels (0 means unlimited)
+func imageMaxDimension() int {
+	return viper.GetInt("image_max_dimension")
+}
+
+// Copies uploaded file to site upload directory. Content type is checked against file content, and file size
+// is checked against upload max size and site storage quota.
 func handleUpload(rw http.ResponseWriter, req *http.Request, site *models.Site, allowedTypes []string) *upload {
 	reader, err := req.MultipartReader()
 	if err != nil {
@@ -29,6 +76,26 @@ func handleUpload(rw http.ResponseWriter, req *http.Request, site *models.Site,
 		return nil
 	}
 
+	maxSize := uploadMaxSize()
+
+	// check quota before reading file
+	quota := siteStorageQuota()
+
+	var used int64
+	var limitedByQuota bool
+
+	if quota > 0 {
+		if used = site.StorageUsage().Total; used >= quota {
+			quotaExceeded(rw, used, quota)
+			return nil
+		}
+
+		if (maxSize == 0) || (quota-used < maxSize) {
+			maxSize = quota - used
+			limitedByQuota = true
+		}
+	}
+
 	result := newUpload()
 
 	for result.name == "" {
@@ -42,11 +109,27 @@ func handleUpload(rw http.ResponseWriter, req *http.Request, site *models.Site,
 			continue
 		}
 
-		// Check content type
-		result.ctype = part.Header.Get("Content-Type")
+		// sniff content type
+		head := make([]byte, sniffLen)
+
+		n, err := io.ReadFull(part, head)
+		if err == io.EOF {
+			http.Error(rw, "Uploaded file is empty", http.StatusBadRequest)
+			return nil
+		}
+
+		if (err != nil) && (err != io.ErrUnexpectedEOF) {
+			log.Printf("Failed to read uploaded file: %v", err)
+			http.Error(rw, "Failed to read uploaded file", http.StatusBadRequest)
+			return nil
+		}
+
+		head = head[:n]
+
+		result.ctype = sniffContentType(part.Header.Get("Content-Type"), head)
 
 		if !allowedContentType(result.ctype, allowedTypes) {
-			log.Printf("Unsupported content type for file upload: %v", result.ctype)
+			log.Printf("Unsupported content type for file upload: %v (declared: %v)", result.ctype, part.Header.Get("Content-Type"))
 			http.Error(rw, "Unsupported content type", http.StatusBadRequest)
 			return nil
 		}
@@ -54,25 +137,45 @@ func handleUpload(rw http.ResponseWriter, req *http.Request, site *models.Site,
 		// copy uploaded file
 		log.Printf("Handling uploaded file: %s", result.name)
 
-		dstPath := helpers.AvailableFilePath(core.UploadSiteFilePath(site.ID, result.name))
+		result.path = helpers.AvailableFilePath(core.UploadSiteFilePath(site.ID, result.name))
 
-		dst, err := os.Create(dstPath)
+		dst, err := os.Create(result.path)
 		if err != nil {
-			log.Printf("Can't create file: %s - %v", dstPath, err.Error())
+			log.Printf("Can't create file: %s - %v", result.path, err.Error())
 			http.Error(rw, "Failed to create uploaded file", http.StatusInternalServerError)
 			return nil
 		}
 
 		defer dst.Close()
 
-		if _, err := io.Copy(dst, part); err != nil {
-			log.Printf("Can't save file: %s - %v", dstPath, err.Error())
+		var src io.Reader = io.MultiReader(bytes.NewReader(head), part)
+		if maxSize > 0 {
+			// read one more byte to detect oversized files
+			src = io.LimitReader(src, maxSize+1)
+		}
+
+		written, err := io.Copy(dst, src)
+		if err != nil {
+			log.Printf("Can't save file: %s - %v", result.path, err.Error())
 			http.Error(rw, "Failed to save uploaded file", http.StatusInternalServerError)
+			result.remove()
+			return nil
+		}
+
+		if (maxSize > 0) && (written > maxSize) {
+			result.remove()
+
+			if limitedByQuota {
+				quotaExceeded(rw, used, quota)
+			} else {
+				http.Error(rw, fmt.Sprintf("File too large: maximum size is %d MB", maxSize/megabyte), http.StatusRequestEntityTooLarge)
+			}
+
 			return nil
 		}
 
 		var errStat error
-		result.info, errStat = os.Stat(dstPath)
+		result.info, errStat = os.Stat(result.path)
 		if os.IsNotExist(errStat) {
 			http.Error(rw, "Failed to create uploaded file", http.StatusInternalServerError)
 			return nil
@@ -87,6 +190,41 @@ func handleUpload(rw http.ResponseWriter, req *http.Request, site *models.Site,
 	return result
 }
 
+// replies with a storage quota error
+func quotaExceeded(rw http.ResponseWriter, used int64, quota int64) {
+	http.Error(rw, fmt.Sprintf("Storage quota exceeded: %d MB used of %d MB", used/megabyte, quota/megabyte), http.StatusInsufficientStorage)
+}
+
+// remove uploaded file
+func (u *upload) remove() {
+	if err := os.Remove(u.path); err != nil {
+		log.Printf("Failed to remove uploaded file: %s - %v", u.path, err)
+	}
+}
+
+// Computes content type from file content. Declared content type is used only for documents stored in a
+// ZIP or OLE container, and only if content matches that container.
+func sniffContentType(declared string, head []byte) string {
+	if mediaType, _, err := mime.ParseMediaType(declared); err == nil {
+		declared = mediaType
+	}
+
+	result := http.DetectContentType(head)
+	if mediaType, _, err := mime.ParseMediaType(result); err == nil {
+		result = mediaType
+	}
+
+	switch {
+	case (result == "application/zip") && zipContentTypes[declared]:
+		return declared
+
+	case bytes.HasPrefix(head, oleMagic) && oleContentTypes[declared]:
+		return declared
+	}
+
+	return result
+}
+
 func allowedContentType(ct string, allowed []string) bool {
 	for _, allowedCT := range allowed {
 		if ct == allowedCT {
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type UploadTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestUploadTestSuite(t *testing.T) {
+	suite.Run(t, new(UploadTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *UploadTestSuite) TestSniffContentType() {
+	t := suite.T()
+
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewGray(image.Rect(0, 0, 2, 2)))
+
+	// content wins over declared type
+	assert.Equal(t, "image/png", sniffContentType("image/jpeg", buf.Bytes()))
+	assert.Equal(t, "application/pdf", sniffContentType("application/octet-stream", []byte("%PDF-1.4\n")))
+	assert.Equal(t, "text/plain", sniffContentType("text/plain; charset=utf-8", []byte("Meeting minutes")))
+	assert.Equal(t, "text/html", sniffContentType("application/pdf", []byte("<html><body>hello</body></html>")))
+
+	// documents containers
+	zip := []byte("PK\x03\x04\x14\x00\x06\x00")
+	assert.Equal(t, "application/vnd.oasis.opendocument.text", sniffContentType("application/vnd.oasis.opendocument.text", zip))
+	assert.Equal(t, "application/zip", sniffContentType("application/pdf", zip))
+
+	ole := append(append([]byte{}, oleMagic...), 0, 0, 0, 0)
+	assert.Equal(t, "application/msword", sniffContentType("application/msword", ole))
+	assert.NotEqual(t, "application/msword", sniffContentType("application/msword", []byte("not a word document")))
+}
@@ -22,6 +22,18 @@ func (app *Application) newWebRouter() *mux.Router {
 	// /api/configuration
 	apiRouter.Methods("GET").Path("/configuration").Handler(baseChain.ThenFunc(app.handleGetConfig))
 
+	// /api/public
+	publicSiteChain := baseChain.Append(app.ensureSiteMiddleware)
+	publicEventChain := baseChain.Append(app.ensureEventMiddleware, app.ensureSiteMiddleware)
+
+	publicRouter := apiRouter.PathPrefix("/public").Subrouter()
+	publicRouter.Methods("POST").Path("/sites/{site_id}/contact").Handler(publicSiteChain.Append(app.publicRateLimitMiddleware).ThenFunc(app.handlePostPublicContact))
+	publicRouter.Methods("POST").Path("/sites/{site_id}/subscribers").Handler(publicSiteChain.Append(app.publicRateLimitMiddleware).ThenFunc(app.handlePostPublicSubscriber))
+	publicRouter.Methods("GET").Path("/subscribers/confirm").Handler(baseChain.ThenFunc(app.handlePublicConfirmSubscriber))
+	publicRouter.Methods("GET").Path("/subscribers/unsubscribe").Handler(baseChain.ThenFunc(app.handlePublicUnsubscribeConfirmation))
+	publicRouter.Methods("POST").Path("/subscribers/unsubscribe").Handler(baseChain.ThenFunc(app.handlePublicUnsubscribe))
+	publicRouter.Methods("POST").Path("/events/{event_id}/registrations").Handler(publicEventChain.Append(app.publicRateLimitMiddleware).ThenFunc(app.handlePostPublicRegistration))
+
 	notAuthChain := baseChain.Append(app.ensureNotAuthMiddleware)
 
 	// /api/signup
@@ -29,17 +41,36 @@ func (app *Application) newWebRouter() *mux.Router {
 	apiRouter.Methods("POST").Path("/signup/validate").Handler(notAuthChain.ThenFunc(app.handleSignupValidate))
 	apiRouter.Methods("POST").Path("/signup/sendmail").Handler(notAuthChain.ThenFunc(app.handleSignupSendMail))
 
+	// /api/password
+	apiRouter.Methods("POST").Path("/password/forgot").Handler(notAuthChain.Append(app.publicRateLimitMiddleware).ThenFunc(app.handlePasswordForgot))
+	apiRouter.Methods("POST").Path("/password/reset").Handler(notAuthChain.Append(app.publicRateLimitMiddleware).ThenFunc(app.handlePasswordReset))
+
 	// /api/oauth
 	oauthRouter := apiRouter.PathPrefix("/oauth").Subrouter()
 	oauthRouter.Methods("POST").Path("/token").Handler(baseChain.ThenFunc(app.handleOauthToken))
 	oauthRouter.Methods("POST").Path("/revoke").Handler(baseChain.ThenFunc(app.handleOauthRevoke))
 
-	authChain := baseChain.Append(app.ensureAuthMiddleware)
+	twoFactorChain := baseChain.Append(app.ensureAuthMiddleware)
+	authChain := twoFactorChain.Append(app.ensureTwoFactorMiddleware)
 
 	// /api/me
-	apiRouter.Methods("GET").Path("/me").Handler(authChain.ThenFunc(app.handleGetMe))
+	apiRouter.Methods("GET").Path("/me").Handler(twoFactorChain.ThenFunc(app.handleGetMe))
+
+	// /api/me/two_factor
+	twoFactorSessionChain := twoFactorChain.Append(app.ensureNotAPITokenMiddleware)
+	authSessionChain := authChain.Append(app.ensureNotAPITokenMiddleware)
+
+	apiRouter.Methods("POST").Path("/me/two_factor/enroll").Handler(twoFactorSessionChain.ThenFunc(app.handleTwoFactorEnroll))
+	apiRouter.Methods("POST").Path("/me/two_factor/enable").Handler(twoFactorSessionChain.ThenFunc(app.handleTwoFactorEnable))
+	apiRouter.Methods("POST").Path("/me/two_factor/recovery_codes").Handler(authSessionChain.ThenFunc(app.handleTwoFactorRecoveryCodes))
+	apiRouter.Methods("DELETE").Path("/me/two_factor").Handler(authSessionChain.ThenFunc(app.handleTwoFactorDisable))
 
-	curUserChain := authChain.Append(app.ensureUserAccessMiddleware)
+	// /api/me/api_tokens
+	apiRouter.Methods("GET").Path("/me/api_tokens").Handler(authSessionChain.ThenFunc(app.handleGetAPITokens))
+	apiRouter.Methods("POST").Path("/me/api_tokens").Handler(authSessionChain.ThenFunc(app.handlePostAPITokens))
+	apiRouter.Methods("DELETE").Path("/me/api_tokens/{api_token_id}").Handler(authSessionChain.ThenFunc(app.handleDeleteAPIToken))
+
+	curUserChain := authChain.Append(app.ensureNotSiteAPITokenMiddleware, app.ensureUserAccessMiddleware)
 
 	// /api/users/{user_id}
 	apiRouter.Methods("GET").Path("/users/{user_id}").Handler(curUserChain.ThenFunc(app.handleGetUser))
@@ -53,21 +84,32 @@ func (app *Application) newWebRouter() *mux.Router {
 	curPageOwnerChain := authChain.Append(app.ensurePageMiddleware, app.ensureSiteMiddleware, app.ensureSiteOwnerAccessMiddleware)
 	curActivityOwnerChain := authChain.Append(app.ensureActivityMiddleware, app.ensureSiteMiddleware, app.ensureSiteOwnerAccessMiddleware)
 	curMemberOwnerChain := authChain.Append(app.ensureMemberMiddleware, app.ensureSiteMiddleware, app.ensureSiteOwnerAccessMiddleware)
+	curLocationOwnerChain := authChain.Append(app.ensureLocationMiddleware, app.ensureSiteMiddleware, app.ensureSiteOwnerAccessMiddleware)
+	curMessageOwnerChain := authChain.Append(app.ensureMessageMiddleware, app.ensureSiteMiddleware, app.ensureSiteOwnerAccessMiddleware)
+	curSubscriberOwnerChain := authChain.Append(app.ensureSubscriberMiddleware, app.ensureSiteMiddleware, app.ensureSiteOwnerAccessMiddleware)
+	curWebhookOwnerChain := authChain.Append(app.ensureWebhookMiddleware, app.ensureSiteMiddleware, app.ensureSiteOwnerAccessMiddleware)
 	curImageOwnerChain := authChain.Append(app.ensureImageMiddleware, app.ensureSiteMiddleware, app.ensureSiteOwnerAccessMiddleware)
 	curFileOwnerChain := authChain.Append(app.ensureFileMiddleware, app.ensureSiteMiddleware, app.ensureSiteOwnerAccessMiddleware)
 
 	// /api/sites
-	apiRouter.Methods("POST").Path("/sites").Handler(authChain.ThenFunc(app.handlePostSite))
+	apiRouter.Methods("POST").Path("/sites").Handler(authChain.Append(app.ensureNotSiteAPITokenMiddleware).ThenFunc(app.handlePostSite))
+	apiRouter.Methods("POST").Path("/sites/import").Handler(authChain.Append(app.ensureNotSiteAPITokenMiddleware).ThenFunc(app.handleImportSite))
 
 	// /api/sites/{site_id}
 	apiRouter.Methods("GET").Path("/sites/{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleGetSite))
 	apiRouter.Methods("PUT").Path("/sites/{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleUpdateSite))
 	apiRouter.Methods("DELETE").Path("/sites/{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleDeleteSite))
+	apiRouter.Methods("GET").Path("/sites/{site_id}/export").Handler(curSiteOwnerChain.ThenFunc(app.handleExportSite))
+	apiRouter.Methods("GET").Path("/sites/{site_id}/storage").Handler(curSiteOwnerChain.ThenFunc(app.handleGetSiteStorage))
 
 	apiRouter.Methods("GET").Path("/sites/{site_id}/posts").Handler(curSiteOwnerChain.ThenFunc(app.handleGetPosts))
 	apiRouter.Methods("GET").Path("/sites/{site_id}/events").Handler(curSiteOwnerChain.ThenFunc(app.handleGetEvents))
 	apiRouter.Methods("GET").Path("/sites/{site_id}/pages").Handler(curSiteOwnerChain.ThenFunc(app.handleGetPages))
 	apiRouter.Methods("GET").Path("/sites/{site_id}/activities").Handler(curSiteOwnerChain.ThenFunc(app.handleGetActivities))
+	apiRouter.Methods("GET").Path("/sites/{site_id}/locations").Handler(curSiteOwnerChain.ThenFunc(app.handleGetLocations))
+	apiRouter.Methods("GET").Path("/sites/{site_id}/messages").Handler(curSiteOwnerChain.ThenFunc(app.handleGetMessages))
+	apiRouter.Methods("GET").Path("/sites/{site_id}/subscribers").Handler(curSiteOwnerChain.ThenFunc(app.handleGetSubscribers))
+	apiRouter.Methods("GET").Path("/sites/{site_id}/webhooks").Handler(curSiteOwnerChain.ThenFunc(app.handleGetWebhooks))
 	apiRouter.Methods("GET").Path("/sites/{site_id}/images").Handler(curSiteOwnerChain.ThenFunc(app.handleGetImages))
 	apiRouter.Methods("GET").Path("/sites/{site_id}/files").Handler(curSiteOwnerChain.ThenFunc(app.handleGetFiles))
 
@@ -77,6 +119,11 @@ func (app *Application) newWebRouter() *mux.Router {
 	apiRouter.Methods("POST").Path("/sites/{site_id}/theme-settings").Handler(curSiteOwnerChain.ThenFunc(app.handleSetThemeSettings))
 	apiRouter.Methods("PUT").Path("/sites/{site_id}/theme-settings/{setting_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleSetThemeSettings))
 
+	apiRouter.Methods("POST").Path("/sites/{site_id}/navbar-links").Handler(curSiteOwnerChain.ThenFunc(app.handleSetNavBarLink))
+	apiRouter.Methods("PUT").Path("/sites/{site_id}/navbar-links/{link_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleSetNavBarLink))
+	apiRouter.Methods("DELETE").Path("/sites/{site_id}/navbar-links/{link_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleDeleteNavBarLink))
+	apiRouter.Methods("PUT").Path("/sites/{site_id}/navbar/order").Handler(curSiteOwnerChain.ThenFunc(app.handlePutNavBarOrder))
+
 	// /api/posts?site={site_id}
 	apiRouter.Methods("GET").Path("/posts").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleGetPosts))
 	apiRouter.Methods("POST").Path("/posts").Handler(authChain.ThenFunc(app.handlePostPosts))
@@ -90,11 +137,17 @@ func (app *Application) newWebRouter() *mux.Router {
 	apiRouter.Methods("GET").Path("/events/{event_id}").Handler(curEventOwnerChain.ThenFunc(app.handleGetEvent))
 	apiRouter.Methods("PUT").Path("/events/{event_id}").Handler(curEventOwnerChain.ThenFunc(app.handleUpdateEvent))
 	apiRouter.Methods("DELETE").Path("/events/{event_id}").Handler(curEventOwnerChain.ThenFunc(app.handleDeleteEvent))
+	apiRouter.Methods("GET").Path("/events/{event_id}/occurrences").Handler(curEventOwnerChain.ThenFunc(app.handleGetEventOccurrences))
+	apiRouter.Methods("PUT").Path("/events/{event_id}/occurrences/{occurrence_id}").Handler(curEventOwnerChain.ThenFunc(app.handleSetEventOverride))
+	apiRouter.Methods("DELETE").Path("/events/{event_id}/occurrences/{occurrence_id}").Handler(curEventOwnerChain.ThenFunc(app.handleDeleteEventOverride))
+	apiRouter.Methods("GET").Path("/events/{event_id}/registrations").Handler(curEventOwnerChain.ThenFunc(app.handleGetRegistrations))
+	apiRouter.Methods("GET").Path("/events/{event_id}/registrations.csv").Handler(curEventOwnerChain.ThenFunc(app.handleGetRegistrationsCSV))
 
 	// /api/pages?site={site_id}
 	apiRouter.Methods("GET").Path("/pages").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleGetPages))
 	apiRouter.Methods("POST").Path("/pages").Handler(authChain.ThenFunc(app.handlePostPages))
 	apiRouter.Methods("GET").Path("/pages/{page_id}").Handler(curPageOwnerChain.ThenFunc(app.handleGetPage))
+	apiRouter.Methods("PUT").Path("/pages/order").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handlePutPagesOrder))
 	apiRouter.Methods("PUT").Path("/pages/{page_id}").Handler(curPageOwnerChain.ThenFunc(app.handleUpdatePage))
 	apiRouter.Methods("DELETE").Path("/pages/{page_id}").Handler(curPageOwnerChain.ThenFunc(app.handleDeletePage))
 
@@ -113,18 +166,51 @@ func (app *Application) newWebRouter() *mux.Router {
 	apiRouter.Methods("PUT").Path("/members/{member_id}").Handler(curMemberOwnerChain.ThenFunc(app.handleUpdateMember))
 	apiRouter.Methods("DELETE").Path("/members/{member_id}").Handler(curMemberOwnerChain.ThenFunc(app.handleDeleteMember))
 
+	// /api/locations?site={site_id}
+	apiRouter.Methods("GET").Path("/locations").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleGetLocations))
+	apiRouter.Methods("POST").Path("/locations").Handler(authChain.ThenFunc(app.handlePostLocations))
+	apiRouter.Methods("GET").Path("/locations/{location_id}").Handler(curLocationOwnerChain.ThenFunc(app.handleGetLocation))
+	apiRouter.Methods("PUT").Path("/locations/{location_id}").Handler(curLocationOwnerChain.ThenFunc(app.handleUpdateLocation))
+	apiRouter.Methods("DELETE").Path("/locations/{location_id}").Handler(curLocationOwnerChain.ThenFunc(app.handleDeleteLocation))
+
+	// /api/messages?site={site_id}
+	apiRouter.Methods("GET").Path("/messages").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleGetMessages))
+	apiRouter.Methods("GET").Path("/messages/{message_id}").Handler(curMessageOwnerChain.ThenFunc(app.handleGetMessage))
+	apiRouter.Methods("PUT").Path("/messages/{message_id}").Handler(curMessageOwnerChain.ThenFunc(app.handleUpdateMessage))
+	apiRouter.Methods("DELETE").Path("/messages/{message_id}").Handler(curMessageOwnerChain.ThenFunc(app.handleDeleteMessage))
+
+	// /api/subscribers?site={site_id}
+	apiRouter.Methods("GET").Path("/subscribers").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleGetSubscribers))
+	apiRouter.Methods("DELETE").Path("/subscribers/{subscriber_id}").Handler(curSubscriberOwnerChain.ThenFunc(app.handleDeleteSubscriber))
+
+	// /api/webhooks?site={site_id}
+	apiRouter.Methods("GET").Path("/webhooks").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleGetWebhooks))
+	apiRouter.Methods("POST").Path("/webhooks").Handler(authChain.ThenFunc(app.handlePostWebhooks))
+	apiRouter.Methods("GET").Path("/webhooks/{webhook_id}").Handler(curWebhookOwnerChain.ThenFunc(app.handleGetWebhook))
+	apiRouter.Methods("PUT").Path("/webhooks/{webhook_id}").Handler(curWebhookOwnerChain.ThenFunc(app.handleUpdateWebhook))
+	apiRouter.Methods("DELETE").Path("/webhooks/{webhook_id}").Handler(curWebhookOwnerChain.ThenFunc(app.handleDeleteWebhook))
+	apiRouter.Methods("GET").Path("/webhooks/{webhook_id}/deliveries").Handler(curWebhookOwnerChain.ThenFunc(app.handleGetWebhookDeliveries))
+
 	// /api/images?site={site_id}
 	apiRouter.Methods("GET").Path("/images").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleGetImages))
 	apiRouter.Methods("GET").Path("/images/{image_id}").Handler(curImageOwnerChain.ThenFunc(app.handleGetImage))
+	apiRouter.Methods("PUT").Path("/images/{image_id}").Handler(curImageOwnerChain.ThenFunc(app.handleUpdateImage))
+	apiRouter.Methods("GET").Path("/images/{image_id}/usages").Handler(curImageOwnerChain.ThenFunc(app.handleGetImageUsages))
 	apiRouter.Methods("DELETE").Path("/images/{image_id}").Handler(curImageOwnerChain.ThenFunc(app.handleDeleteImage))
 	apiRouter.Methods("POST").Path("/images/upload").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleUploadImage))
 
 	// /api/files?site={site_id}
 	apiRouter.Methods("GET").Path("/files").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleGetFiles))
 	apiRouter.Methods("GET").Path("/files/{file_id}").Handler(curFileOwnerChain.ThenFunc(app.handleGetFile))
+	apiRouter.Methods("PUT").Path("/files/{file_id}").Handler(curFileOwnerChain.ThenFunc(app.handleUpdateFile))
+	apiRouter.Methods("GET").Path("/files/{file_id}/usages").Handler(curFileOwnerChain.ThenFunc(app.handleGetFileUsages))
 	apiRouter.Methods("DELETE").Path("/files/{file_id}").Handler(curFileOwnerChain.ThenFunc(app.handleDeleteFile))
 	apiRouter.Methods("POST").Path("/files/upload").Queries("kind", "{kind}", "site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleUploadFile))
 
+	// /api/media/folders?site={site_id}
+	apiRouter.Methods("GET").Path("/media/folders").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleGetMediaFolders))
+	apiRouter.Methods("GET").Path("/media/unused").Queries("site", "{site_id}").Handler(curSiteOwnerChain.ThenFunc(app.handleGetUnusedMedia))
+
 	return router
 }
 
@@ -0,0 +1,412 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/aymerick/kowa/models"
+	"github.com/aymerick/kowa/token"
+)
+
+const (
+	webhookSecretSize   = 32 // bytes
+	webhookTimeout      = 10 * time.Second
+	webhookMaxAttempts  = 5
+	webhookFirstBackoff = 30 * time.Second // doubled after each failed attempt
+
+	webhookSignatureHeader = "X-Kowa-Signature"
+	webhookEventHeader     = "X-Kowa-Event"
+	webhookDeliveryHeader  = "X-Kowa-Delivery"
+)
+
+// errWebhookForbiddenAddress is returned when webhook URL resolves to a loopback, private or link-local address
+var errWebhookForbiddenAddress = errors.New("Webhook URL resolves to a forbidden address")
+
+type webhookJSON struct {
+	Webhook models.Webhook `json:"webhook"`
+}
+
+// webhookPayload represents the JSON body sent to webhooks
+type webhookPayload struct {
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"createdAt"`
+	SiteID    string    `json:"site"`
+	SiteName  string    `json:"siteName"`
+	SiteURL   string    `json:"siteUrl"`
+}
+
+// webhookSender posts webhook payloads, with retries
+type webhookSender struct {
+	client       *http.Client
+	maxAttempts  int
+	firstBackoff time.Duration
+}
+
+func newWebhookSender() *webhookSender {
+	// addresses are checked at dial time, after DNS resolution and on each redirect
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: webhookDialControl,
+	}
+
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: webhookTimeout,
+	}
+
+	return &webhookSender{
+		client:       &http.Client{Timeout: webhookTimeout, Transport: transport},
+		maxAttempts:  webhookMaxAttempts,
+		firstBackoff: webhookFirstBackoff,
+	}
+}
+
+// webhookDialControl rejects connections to loopback, private and link-local addresses, so that webhooks can't be
+// used to probe server network
+func webhookDialControl(network string, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if (ip == nil) || !webhookAllowedIP(ip) {
+		return errWebhookForbiddenAddress
+	}
+
+	return nil
+}
+
+// webhookAllowedIP returns true if webhooks can be delivered to given IP address
+func webhookAllowedIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// webhookSignature computes the HMAC signature of given payload
+func webhookSignature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// send delivers payload to webhook, and fills delivery with result
+//
+// Failed requests are retried with an exponential backoff, except on client errors that would fail again.
+func (sender *webhookSender) send(webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	backoff := sender.firstBackoff
+
+	for delivery.Attempts < sender.maxAttempts {
+		if delivery.Attempts > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		delivery.Attempts++
+
+		statusCode, err := sender.post(webhook, delivery)
+
+		delivery.StatusCode = statusCode
+		delivery.Error = ""
+
+		if err != nil {
+			delivery.Error = err.Error()
+
+			if errors.Is(err, errWebhookForbiddenAddress) {
+				// retrying won't help
+				return
+			}
+
+			continue
+		}
+
+		if (statusCode >= 200) && (statusCode < 300) {
+			delivery.Success = true
+			delivery.DeliveredAt = time.Now()
+			return
+		}
+
+		delivery.Error = fmt.Sprintf("Unexpected status code: %d", statusCode)
+
+		if (statusCode >= 400) && (statusCode < 500) && (statusCode != http.StatusRequestTimeout) && (statusCode != http.StatusTooManyRequests) {
+			// retrying won't help
+			return
+		}
+	}
+}
+
+// post sends a single webhook request and returns response status code
+func (sender *webhookSender) post(webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	payload := []byte(delivery.Payload)
+
+	req, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Kowa-Webhook")
+	req.Header.Set(webhookEventHeader, delivery.Event)
+	req.Header.Set(webhookDeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(webhookSignatureHeader, webhookSignature(webhook.Secret, payload))
+
+	resp, err := sender.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drain body so that connection can be reused
+	io.Copy(ioutil.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+// fireWebhooks delivers given event to all site webhooks that handle it
+func (app *Application) fireWebhooks(site *models.Site, event string) {
+	webhooks := site.FindEventWebhooks(event)
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(&webhookPayload{
+		Event:     event,
+		CreatedAt: time.Now(),
+		SiteID:    site.ID,
+		SiteName:  site.Name,
+		SiteURL:   site.BaseUrl(),
+	})
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		go app.deliverWebhook(webhook, event, string(payload))
+	}
+}
+
+// deliverWebhook sends payload to webhook and logs delivery
+func (app *Application) deliverWebhook(webhook *models.Webhook, event string, payload string) {
+	dbSession := app.dbSession.Copy()
+	defer dbSession.Close()
+
+	delivery := &models.WebhookDelivery{
+		WebhookID: webhook.ID,
+		SiteID:    webhook.SiteID,
+		Event:     event,
+		Payload:   payload,
+	}
+
+	if err := dbSession.CreateWebhookDelivery(delivery); err != nil {
+		log.Printf("ERROR: %v", err)
+		return
+	}
+
+	app.webhookSender.send(webhook, delivery)
+
+	if !delivery.Success {
+		log.Printf("[webhook] Delivery %s of %s event to %s failed after %d attempts: %s", delivery.ID.Hex(), event, webhook.URL, delivery.Attempts, delivery.Error)
+	}
+
+	if err := delivery.Update(); err != nil {
+		log.Printf("ERROR: %v", err)
+	}
+}
+
+// onBuildJobDone is called by build master when a job ends
+func (app *Application) onBuildJobDone(kind string, siteID string, failed bool) {
+	if kind != jobKindBuild {
+		return
+	}
+
+	dbSession := app.dbSession.Copy()
+	defer dbSession.Close()
+
+	site := dbSession.FindSite(siteID)
+	if site == nil {
+		return
+	}
+
+	if failed {
+		app.fireWebhooks(site, models.WebhookEventBuildFailed)
+	} else {
+		app.fireWebhooks(site, models.WebhookEventBuildSucceeded)
+	}
+}
+
+// checkWebhook checks webhook fields sent by client
+func checkWebhook(rw http.ResponseWriter, webhook *models.Webhook) bool {
+	endpoint, err := url.Parse(webhook.URL)
+	if (err != nil) || ((endpoint.Scheme != "http") && (endpoint.Scheme != "https")) || (endpoint.Host == "") {
+		http.Error(rw, "Invalid webhook URL", http.StatusBadRequest)
+		return false
+	}
+
+	for _, event := range webhook.Events {
+		if !models.ValidWebhookEvent(event) {
+			http.Error(rw, "Invalid webhook event: "+event, http.StatusBadRequest)
+			return false
+		}
+	}
+
+	return true
+}
+
+// GET /webhooks?site={site_id}
+// GET /sites/{site_id}/webhooks
+func (app *Application) handleGetWebhooks(rw http.ResponseWriter, req *http.Request) {
+	site := app.getCurrentSite(req)
+	if site != nil {
+		// fetch paginated records
+		pagination := newPagination()
+		if err := pagination.fillFromRequest(req); err != nil {
+			http.Error(rw, "Invalid pagination parameters", http.StatusBadRequest)
+			return
+		}
+
+		pagination.Total = site.WebhooksNb()
+
+		webhooks := site.FindWebhooks(pagination.Skip, pagination.PerPage)
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"webhooks": webhooks, "meta": pagination})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// POST /webhooks
+func (app *Application) handlePostWebhooks(rw http.ResponseWriter, req *http.Request) {
+	currentDBSession := app.getCurrentDBSession(req)
+
+	var reqJSON webhookJSON
+
+	if err := json.NewDecoder(req.Body).Decode(&reqJSON); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+		return
+	}
+
+	webhook := &models.Webhook{
+		SiteID: reqJSON.Webhook.SiteID,
+		URL:    reqJSON.Webhook.URL,
+		Events: reqJSON.Webhook.Events,
+		Active: reqJSON.Webhook.Active,
+		Secret: token.NewSecret(webhookSecretSize),
+	}
+
+	if webhook.SiteID == "" {
+		http.Error(rw, "Missing site field in webhook record", http.StatusBadRequest)
+		return
+	}
+
+	site := currentDBSession.FindSite(webhook.SiteID)
+	if site == nil {
+		http.Error(rw, "Site not found", http.StatusBadRequest)
+		return
+	}
+
+	if !app.siteOwnerAccess(req, site) {
+		unauthorized(rw)
+		return
+	}
+
+	if !checkWebhook(rw, webhook) {
+		return
+	}
+
+	if err := currentDBSession.CreateWebhook(webhook); err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(rw, "Failed to create webhook", http.StatusInternalServerError)
+		return
+	}
+
+	app.render.JSON(rw, http.StatusCreated, renderMap{"webhook": webhook})
+}
+
+// GET /webhooks/{webhook_id}
+func (app *Application) handleGetWebhook(rw http.ResponseWriter, req *http.Request) {
+	webhook := app.getCurrentWebhook(req)
+	if webhook != nil {
+		app.render.JSON(rw, http.StatusOK, renderMap{"webhook": webhook})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// PUT /webhooks/{webhook_id}
+func (app *Application) handleUpdateWebhook(rw http.ResponseWriter, req *http.Request) {
+	webhook := app.getCurrentWebhook(req)
+	if webhook != nil {
+		var reqJSON webhookJSON
+
+		if err := json.NewDecoder(req.Body).Decode(&reqJSON); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to decode JSON data", http.StatusBadRequest)
+			return
+		}
+
+		if !checkWebhook(rw, &reqJSON.Webhook) {
+			return
+		}
+
+		if _, err := webhook.Update(&reqJSON.Webhook); err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(rw, "Failed to update webhook", http.StatusInternalServerError)
+			return
+		}
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"webhook": webhook})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// DELETE /webhooks/{webhook_id}
+func (app *Application) handleDeleteWebhook(rw http.ResponseWriter, req *http.Request) {
+	webhook := app.getCurrentWebhook(req)
+	if webhook != nil {
+		if err := webhook.Delete(); err != nil {
+			http.Error(rw, "Failed to delete webhook", http.StatusInternalServerError)
+		} else {
+			// returns deleted webhook
+			app.render.JSON(rw, http.StatusOK, renderMap{"webhook": webhook})
+		}
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+// GET /webhooks/{webhook_id}/deliveries
+func (app *Application) handleGetWebhookDeliveries(rw http.ResponseWriter, req *http.Request) {
+	webhook := app.getCurrentWebhook(req)
+	if webhook != nil {
+		// fetch paginated records
+		pagination := newPagination()
+		if err := pagination.fillFromRequest(req); err != nil {
+			http.Error(rw, "Invalid pagination parameters", http.StatusBadRequest)
+			return
+		}
+
+		pagination.Total = webhook.DeliveriesNb()
+
+		deliveries := webhook.FindDeliveries(pagination.Skip, pagination.PerPage)
+
+		app.render.JSON(rw, http.StatusOK, renderMap{"webhookDeliveries": deliveries, "meta": pagination})
+	} else {
+		http.NotFound(rw, req)
+	}
+}
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/models"
+)
+
+type WebhooksTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestWebhooksTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhooksTestSuite))
+}
+
+func newTestWebhookSender() *webhookSender {
+	return &webhookSender{
+		client:       &http.Client{Timeout: time.Second},
+		maxAttempts:  3,
+		firstBackoff: time.Millisecond,
+	}
+}
+
+func newTestWebhookDelivery() *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:      bson.NewObjectId(),
+		Event:   models.WebhookEventSiteChanged,
+		Payload: `{"event":"site.changed","site":"my_site"}`,
+	}
+}
+
+//
+// Tests
+//
+
+func (suite *WebhooksTestSuite) TestSend() {
+	t := suite.T()
+
+	var received *http.Request
+	var body []byte
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		received = req
+		body, _ = ioutil.ReadAll(req.Body)
+
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	webhook := &models.Webhook{URL: receiver.URL, Secret: "my_so_secure_secret", Active: true}
+	delivery := newTestWebhookDelivery()
+
+	newTestWebhookSender().send(webhook, delivery)
+
+	assert.True(t, delivery.Success)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusNoContent, delivery.StatusCode)
+	assert.Empty(t, delivery.Error)
+	assert.False(t, delivery.DeliveredAt.IsZero())
+
+	assert.NotNil(t, received)
+	assert.Equal(t, "POST", received.Method)
+	assert.Equal(t, delivery.Payload, string(body))
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, models.WebhookEventSiteChanged, received.Header.Get(webhookEventHeader))
+	assert.Equal(t, delivery.ID.Hex(), received.Header.Get(webhookDeliveryHeader))
+	assert.Equal(t, webhookSignature(webhook.Secret, body), received.Header.Get(webhookSignatureHeader))
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, received.Header.Get(webhookSignatureHeader))
+}
+
+func (suite *WebhooksTestSuite) TestSendRetry() {
+	t := suite.T()
+
+	calls := 0
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+
+		if calls < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	webhook := &models.Webhook{URL: receiver.URL, Secret: "my_so_secure_secret", Active: true}
+	delivery := newTestWebhookDelivery()
+
+	newTestWebhookSender().send(webhook, delivery)
+
+	assert.True(t, delivery.Success)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, http.StatusOK, delivery.StatusCode)
+}
+
+func (suite *WebhooksTestSuite) TestSendFailure() {
+	t := suite.T()
+
+	calls := 0
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	webhook := &models.Webhook{URL: receiver.URL, Secret: "my_so_secure_secret", Active: true}
+	delivery := newTestWebhookDelivery()
+
+	newTestWebhookSender().send(webhook, delivery)
+
+	assert.False(t, delivery.Success)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, delivery.StatusCode)
+	assert.NotEmpty(t, delivery.Error)
+	assert.True(t, delivery.DeliveredAt.IsZero())
+}
+
+func (suite *WebhooksTestSuite) TestSendClientError() {
+	t := suite.T()
+
+	calls := 0
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+
+		rw.WriteHeader(http.StatusGone)
+	}))
+	defer receiver.Close()
+
+	webhook := &models.Webhook{URL: receiver.URL, Secret: "my_so_secure_secret", Active: true}
+	delivery := newTestWebhookDelivery()
+
+	newTestWebhookSender().send(webhook, delivery)
+
+	// no retry on client errors
+	assert.False(t, delivery.Success)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, delivery.Attempts)
+}
+
+func (suite *WebhooksTestSuite) TestSendForbiddenAddress() {
+	t := suite.T()
+
+	calls := 0
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	webhook := &models.Webhook{URL: receiver.URL, Secret: "my_so_secure_secret", Active: true}
+	delivery := newTestWebhookDelivery()
+
+	sender := newWebhookSender()
+	sender.firstBackoff = time.Millisecond
+
+	sender.send(webhook, delivery)
+
+	// loopback receiver is never reached, and there is no retry
+	assert.False(t, delivery.Success)
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, 0, delivery.StatusCode)
+	assert.Contains(t, delivery.Error, errWebhookForbiddenAddress.Error())
+}
+
+func (suite *WebhooksTestSuite) TestWebhookDialControl() {
+	t := suite.T()
+
+	forbidden := []string{
+		"127.0.0.1:80",
+		"[::1]:80",
+		"0.0.0.0:80",
+		"10.1.2.3:443",
+		"172.16.0.1:80",
+		"192.168.1.1:80",
+		"169.254.169.254:80",
+		"[fe80::1]:80",
+		"[fc00::1]:80",
+		"[::ffff:127.0.0.1]:80",
+		"224.0.0.1:80",
+	}
+
+	for _, address := range forbidden {
+		assert.Equal(t, errWebhookForbiddenAddress, webhookDialControl("tcp", address, nil), address)
+	}
+
+	allowed := []string{
+		"93.184.216.34:80",
+		"[2606:2800:220:1:248:1893:25c8:1946]:443",
+	}
+
+	for _, address := range allowed {
+		assert.Nil(t, webhookDialControl("tcp", address, nil), address)
+	}
+}
+
+func (suite *WebhooksTestSuite) TestWebhookSignature() {
+	t := suite.T()
+
+	// echo -n 'hello' | openssl dgst -sha256 -hmac 'key'
+	assert.Equal(t, "sha256=9307b3b915efb5171ff14d8cb55fbcc798c6c0ef1456d66ded1a6aa723a58b7b", webhookSignature("key", []byte("hello")))
+}
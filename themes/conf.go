@@ -2,9 +2,28 @@ package themes
 
 // Conf represents a theme configuration
 type Conf struct {
-	ID       string     `json:"id"`
-	Name     string     `json:"name"`
-	Author   string     `json:"author,omitempty"`
-	Homepage string     `json:"homepage,omitempty"`
-	Palettes []*Palette `json:"palettes,omitempty"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Author      string            `json:"author,omitempty"`
+	Homepage    string            `json:"homepage,omitempty"`
+	Palettes    []*Palette        `json:"palettes,omitempty"`
+	Derivatives []*DerivativeConf `json:"derivatives,omitempty"`
+}
+
+// DerivativeConf represents an image derivative needed by theme
+//
+// Example in theme.toml:
+//
+//	[[derivatives]]
+//	name = "hero"
+//	width = 1600
+//	height = 600
+//	scale = "fill"
+//	anchor = "top"
+type DerivativeConf struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Scale  string `json:"scale"`            // fit | fill
+	Anchor string `json:"anchor,omitempty"` // fill anchor, eg: center, top, bottom_left...
 }
@@ -81,6 +81,13 @@ func (t *Theme) Template(id string) string {
 	return path.Join(t.TemplatesDir, fmt.Sprintf("%s.hbs", id))
 }
 
+// HaveTemplate returns true if theme have given template
+func (t *Theme) HaveTemplate(id string) bool {
+	_, err := os.Stat(t.Template(id))
+
+	return !os.IsNotExist(err)
+}
+
 // Partials returns an array of partials paths
 func (t *Theme) Partials() ([]string, error) {
 	// @todo Recompute on file change
@@ -0,0 +1,83 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	"github.com/aymerick/kowa/models"
+	"github.com/spf13/viper"
+)
+
+const (
+	tokenPasswordReset = "password_reset"
+
+	passwordResetExpiration = time.Hour
+)
+
+// PasswordResetURL generate a token for user password reset
+//
+// The token is bound to current user password, so that it can't be used anymore once password is changed.
+func PasswordResetURL(user *models.User) string {
+	token := NewToken(tokenPasswordReset, map[string]interface{}{
+		"u": user.ID,
+		"p": passwordStamp(user),
+	})
+
+	// token expires in one hour
+	token.SetExpirationTime(time.Now().Add(passwordResetExpiration))
+
+	// create URL
+	endpoint, err := url.Parse(viper.GetString("service_url"))
+	if err != nil {
+		panic("Failed to parse service_url setting")
+	}
+
+	endpoint.Path += "/password/reset"
+
+	query := endpoint.Query()
+	query.Set("token", token.Encode())
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint.String()
+}
+
+// PasswordResetUser returns user id from token
+func (token *Token) PasswordResetUser() string {
+	return token.passwordResetValue("u")
+}
+
+// PasswordResetValid returns true if token was generated for given user and if user password did not change since
+func (token *Token) PasswordResetValid(user *models.User) bool {
+	stamp := token.passwordResetValue("p")
+
+	return (user.ID == token.PasswordResetUser()) && (stamp != "") && hmac.Equal([]byte(stamp), []byte(passwordStamp(user)))
+}
+
+func (token *Token) passwordResetValue(key string) string {
+	if token.Kind != tokenPasswordReset {
+		return ""
+	}
+
+	values, ok := token.Value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	result, ok := values[key].(string)
+	if !ok {
+		return ""
+	}
+
+	return result
+}
+
+// passwordStamp returns a signature of user current password, that does not disclose password hash
+func passwordStamp(user *models.User) string {
+	mac := hmac.New(sha256.New, SigningKey())
+	mac.Write([]byte(user.ID + ":" + user.Password))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
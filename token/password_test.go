@@ -0,0 +1,74 @@
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aymerick/kowa/models"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TokenPasswordTestSuite struct {
+	suite.Suite
+}
+
+// called before all tests
+func (suite *TokenPasswordTestSuite) SetupSuite() {
+	viper.Set("secret_key", "my_so_secure_key")
+	viper.Set("service_url", "http://www.myservice.bar")
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestTokenPasswordTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenPasswordTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *TokenPasswordTestSuite) TestPasswordResetURL() {
+	t := suite.T()
+
+	user := &models.User{
+		ID:        "trucmush",
+		Email:     "trucmush@wanadoo.fr",
+		CreatedAt: time.Now(),
+		Lang:      "en",
+		Password:  "$2a$10$encrypted",
+	}
+
+	url := PasswordResetURL(user)
+
+	expectedPrefix := "http://www.myservice.bar/password/reset?token="
+
+	assert.True(t, strings.HasPrefix(url, expectedPrefix))
+	encoded := url[len(expectedPrefix):len(url)]
+
+	decoded := Decode(encoded)
+	assert.NotNil(t, decoded)
+
+	assert.False(t, decoded.Expired())
+	assert.True(t, decoded.ExpirationTime().Before(time.Now().Add(passwordResetExpiration+time.Minute)))
+	assert.Equal(t, user.ID, decoded.PasswordResetUser())
+	assert.Equal(t, "", decoded.AccountValidationUser())
+	assert.True(t, decoded.PasswordResetValid(user))
+
+	// password hash is not disclosed
+	assert.NotContains(t, encoded, user.Password)
+
+	// token can't be used anymore once password is changed
+	user.Password = "$2a$10$changed"
+	assert.False(t, decoded.PasswordResetValid(user))
+
+	// token can't be used for another user
+	other := &models.User{ID: "jeanjean", Password: "$2a$10$encrypted"}
+	assert.False(t, decoded.PasswordResetValid(other))
+
+	// not a password reset token
+	assert.False(t, NewToken(tokenAccountValidation, user.ID).PasswordResetValid(user))
+}
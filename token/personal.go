@@ -0,0 +1,52 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// PersonalTokenPrefix is the prefix of all personal API tokens, so that they can't be mistaken for OAuth access tokens
+	PersonalTokenPrefix = "kowa_"
+
+	personalTokenSize     = 32 // bytes
+	personalTokenHintSize = 4  // chars after prefix
+)
+
+// NewSecret generates a new random hex encoded secret of given size in bytes
+func NewSecret(size int) string {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(buf)
+}
+
+// NewPersonalToken generates a new random personal API token
+func NewPersonalToken() string {
+	return PersonalTokenPrefix + NewSecret(personalTokenSize)
+}
+
+// IsPersonalToken returns true if given string looks like a personal API token
+func IsPersonalToken(value string) bool {
+	return strings.HasPrefix(value, PersonalTokenPrefix)
+}
+
+// HashPersonalToken returns the hash of a personal API token, that is stored in database
+func HashPersonalToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// PersonalTokenHint returns the beginning of a personal API token, that can be safely displayed
+func PersonalTokenHint(value string) string {
+	if len(value) < len(PersonalTokenPrefix)+personalTokenHintSize {
+		return value
+	}
+
+	return value[:len(PersonalTokenPrefix)+personalTokenHintSize]
+}
@@ -0,0 +1,46 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TokenPersonalTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestTokenPersonalTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenPersonalTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *TokenPersonalTestSuite) TestNewPersonalToken() {
+	t := suite.T()
+
+	value := NewPersonalToken()
+
+	assert.Regexp(t, `^kowa_[0-9a-f]{64}$`, value)
+	assert.True(t, IsPersonalToken(value))
+	assert.False(t, IsPersonalToken("Zjg5ZmEwNDYtNGI3NS00MTk4LWFhYzgtZmVlNGRkZDQ3YzAx"))
+
+	assert.NotEqual(t, value, NewPersonalToken())
+}
+
+func (suite *TokenPersonalTestSuite) TestHashPersonalToken() {
+	t := suite.T()
+
+	value := NewPersonalToken()
+
+	assert.Len(t, HashPersonalToken(value), 64)
+	assert.Equal(t, HashPersonalToken(value), HashPersonalToken(value))
+	assert.NotEqual(t, HashPersonalToken(value), HashPersonalToken(NewPersonalToken()))
+
+	assert.Equal(t, value[:9], PersonalTokenHint(value))
+}
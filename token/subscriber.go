@@ -0,0 +1,68 @@
+package token
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/models"
+)
+
+const (
+	tokenSubscriptionConfirmation = "subscription_confirmation"
+	tokenUnsubscription           = "unsubscription"
+)
+
+// SubscriptionConfirmationURL generate a token for newsletter subscription confirmation
+func SubscriptionConfirmationURL(subscriber *models.Subscriber) string {
+	token := NewToken(tokenSubscriptionConfirmation, subscriber.ID.Hex())
+
+	// token expires in 3 days
+	token.SetExpirationTime(time.Now().Add(time.Hour * 72))
+
+	return subscriberURL("/subscribers/confirm", token)
+}
+
+// UnsubscribeURL generate a token for newsletter unsubscription
+func UnsubscribeURL(subscriber *models.Subscriber) string {
+	// token never expires, so that links in old emails still work
+	token := NewToken(tokenUnsubscription, subscriber.ID.Hex())
+
+	return subscriberURL("/subscribers/unsubscribe", token)
+}
+
+func subscriberURL(apiPath string, token *Token) string {
+	endpoint, err := url.Parse(core.PublicAPIUrl(apiPath))
+	if err != nil {
+		panic("Failed to parse public API URL")
+	}
+
+	query := endpoint.Query()
+	query.Set("token", token.Encode())
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint.String()
+}
+
+// SubscriptionConfirmationSubscriber returns subscriber id from token
+func (token *Token) SubscriptionConfirmationSubscriber() string {
+	return token.subscriberID(tokenSubscriptionConfirmation)
+}
+
+// UnsubscriptionSubscriber returns subscriber id from token
+func (token *Token) UnsubscriptionSubscriber() string {
+	return token.subscriberID(tokenUnsubscription)
+}
+
+func (token *Token) subscriberID(kind string) string {
+	if token.Kind != kind {
+		return ""
+	}
+
+	subscriberID, ok := token.Value.(string)
+	if !ok {
+		return ""
+	}
+
+	return subscriberID
+}
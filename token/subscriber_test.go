@@ -0,0 +1,81 @@
+package token
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/models"
+)
+
+type TokenSubscriberTestSuite struct {
+	suite.Suite
+}
+
+// called before all tests
+func (suite *TokenSubscriberTestSuite) SetupSuite() {
+	viper.Set("secret_key", "my_so_secure_key")
+	viper.Set("service_url", "http://www.myservice.bar")
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestTokenSubscriberTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenSubscriberTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *TokenSubscriberTestSuite) TestSubscriptionConfirmationURL() {
+	t := suite.T()
+
+	subscriber := &models.Subscriber{
+		ID:     bson.NewObjectId(),
+		SiteID: "my_site",
+		Email:  "trucmush@wanadoo.fr",
+	}
+
+	url := SubscriptionConfirmationURL(subscriber)
+
+	expectedPrefix := "http://www.myservice.bar/api/public/subscribers/confirm?token="
+
+	assert.True(t, strings.HasPrefix(url, expectedPrefix))
+	encoded := url[len(expectedPrefix):len(url)]
+
+	decoded := Decode(encoded)
+	assert.NotNil(t, decoded)
+
+	assert.False(t, decoded.Expired())
+	assert.Equal(t, subscriber.ID.Hex(), decoded.SubscriptionConfirmationSubscriber())
+	assert.Equal(t, "", decoded.UnsubscriptionSubscriber())
+}
+
+func (suite *TokenSubscriberTestSuite) TestUnsubscribeURL() {
+	t := suite.T()
+
+	subscriber := &models.Subscriber{
+		ID:     bson.NewObjectId(),
+		SiteID: "my_site",
+		Email:  "trucmush@wanadoo.fr",
+	}
+
+	url := UnsubscribeURL(subscriber)
+
+	expectedPrefix := "http://www.myservice.bar/api/public/subscribers/unsubscribe?token="
+
+	assert.True(t, strings.HasPrefix(url, expectedPrefix))
+	encoded := url[len(expectedPrefix):len(url)]
+
+	decoded := Decode(encoded)
+	assert.NotNil(t, decoded)
+
+	assert.Empty(t, decoded.Expiration)
+	assert.Equal(t, subscriber.ID.Hex(), decoded.UnsubscriptionSubscriber())
+	assert.Equal(t, "", decoded.SubscriptionConfirmationSubscriber())
+}
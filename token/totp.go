@@ -0,0 +1,138 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	totpPeriod     = 30 // seconds
+	totpDigits     = 6
+	totpSecretSize = 20 // bytes, as recommended by RFC 4226
+	totpSkew       = 1  // accepted periods before and after current one
+
+	recoveryCodesNb   = 10
+	recoveryCodeSize  = 10
+	recoveryCodeChars = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// NewTOTPSecret generates a new random base32 encoded TOTP secret
+func NewTOTPSecret() string {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+
+	// no padding: secret size is a multiple of 5 bytes
+	return base32.StdEncoding.EncodeToString(secret)
+}
+
+// TOTPProvisioningURI returns the otpauth URI to display as a QR code in authenticator apps
+func TOTPProvisioningURI(secret string, account string, issuer string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", totpDigits))
+	query.Set("period", fmt.Sprintf("%d", totpPeriod))
+
+	result := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}
+
+	return result.String()
+}
+
+// TOTPStep returns the TOTP time step for given time
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / totpPeriod
+}
+
+// TOTPCode computes the TOTP code for given secret and time step (RFC 6238)
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := base32.StdEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000), nil
+}
+
+// ValidateTOTP checks given code against secret at given time, and returns matching time step
+//
+// Returned step must be greater than the last one used by that user, to prevent replays.
+func ValidateTOTP(secret string, code string, t time.Time) (int64, bool) {
+	code = strings.Replace(strings.TrimSpace(code), " ", "", -1)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := TOTPStep(t)
+
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// NewRecoveryCodes generates 2FA recovery codes
+func NewRecoveryCodes() []string {
+	result := make([]string, recoveryCodesNb)
+
+	for i := range result {
+		buf := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(buf); err != nil {
+			panic(err)
+		}
+
+		code := make([]byte, recoveryCodeSize)
+		for j, b := range buf {
+			code[j] = recoveryCodeChars[int(b)%len(recoveryCodeChars)]
+		}
+
+		result[i] = string(code[:recoveryCodeSize/2]) + "-" + string(code[recoveryCodeSize/2:])
+	}
+
+	return result
+}
+
+// HashRecoveryCode returns the hash of a recovery code, that is stored in database
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.Replace(strings.TrimSpace(code), "-", "", -1))
+
+	sum := sha256.Sum256([]byte(code))
+
+	return hex.EncodeToString(sum[:])
+}
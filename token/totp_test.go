@@ -0,0 +1,95 @@
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TokenTOTPTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestTokenTOTPTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenTOTPTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *TokenTOTPTestSuite) TestTOTPCode() {
+	t := suite.T()
+
+	// RFC 6238 test vectors, truncated to 6 digits
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" // "12345678901234567890"
+
+	tests := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range tests {
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(unix, 0)))
+		assert.Nil(t, err)
+		assert.Equal(t, expected, code)
+	}
+}
+
+func (suite *TokenTOTPTestSuite) TestValidateTOTP() {
+	t := suite.T()
+
+	secret := NewTOTPSecret()
+	assert.Len(t, secret, 32)
+
+	now := time.Now()
+
+	code, err := TOTPCode(secret, TOTPStep(now))
+	assert.Nil(t, err)
+
+	step, ok := ValidateTOTP(secret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, TOTPStep(now), step)
+
+	// clock skew
+	_, ok = ValidateTOTP(secret, code, now.Add(30*time.Second))
+	assert.True(t, ok)
+
+	_, ok = ValidateTOTP(secret, code, now.Add(5*time.Minute))
+	assert.False(t, ok)
+
+	_, ok = ValidateTOTP(secret, "12345", now)
+	assert.False(t, ok)
+}
+
+func (suite *TokenTOTPTestSuite) TestTOTPProvisioningURI() {
+	t := suite.T()
+
+	uri := TOTPProvisioningURI("JBSWY3DPEHPK3PXP", "trucmush@wanadoo.fr", "My Service")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/My%20Service:trucmush@wanadoo.fr?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=My+Service")
+}
+
+func (suite *TokenTOTPTestSuite) TestRecoveryCodes() {
+	t := suite.T()
+
+	codes := NewRecoveryCodes()
+	assert.Len(t, codes, recoveryCodesNb)
+
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-z0-9]{5}-[a-z0-9]{5}$`, code)
+		assert.Equal(t, HashRecoveryCode(code), HashRecoveryCode(" "+strings.ToUpper(code)+" "))
+	}
+
+	assert.NotEqual(t, HashRecoveryCode(codes[0]), HashRecoveryCode(codes[1]))
+}
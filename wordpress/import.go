@@ -0,0 +1,360 @@
+package wordpress
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/aymerick/kowa/core"
+	"github.com/aymerick/kowa/helpers"
+	"github.com/aymerick/kowa/models"
+)
+
+var imageContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Report holds the number of imported documents
+type Report struct {
+	Posts     int
+	Pages     int
+	Images    int
+	Redirects int
+}
+
+// imported represents an imported post or page
+type imported struct {
+	item *Item
+	kind string // models.RedirectKindPost | models.RedirectKindPage
+	post *models.Post
+	page *models.Page
+}
+
+func (imp *imported) id() bson.ObjectId {
+	if imp.post != nil {
+		return imp.post.ID
+	}
+
+	return imp.page.ID
+}
+
+// Importer imports a WXR export into a site
+type Importer struct {
+	dbSession *models.DBSession
+	site      *models.Site
+	mediaDir  string
+
+	report *Report
+
+	// imported images, by WordPress post id
+	images map[string]*models.Image
+
+	// urls of imported images, by path relative to WordPress uploads directory
+	uploadURLs map[string]string
+
+	// internal links to imported contents, by link key
+	links map[string]string
+
+	contents []*imported
+}
+
+// NewImporter instanciates a new Importer
+//
+// The mediaDir is the local copy of the WordPress uploads directory (wp-content/uploads).
+func NewImporter(dbSession *models.DBSession, site *models.Site, mediaDir string) *Importer {
+	return &Importer{
+		dbSession: dbSession,
+		site:      site,
+		mediaDir:  mediaDir,
+
+		report:     &Report{},
+		images:     make(map[string]*models.Image),
+		uploadURLs: make(map[string]string),
+		links:      make(map[string]string),
+	}
+}
+
+// Import imports posts, pages and images attachments from given export
+func (importer *Importer) Import(export *Export) (*Report, error) {
+	core.EnsureSiteUploadDir(importer.site.ID)
+
+	for _, item := range export.Items(TypeAttachment) {
+		if err := importer.importAttachment(item); err != nil {
+			return importer.report, err
+		}
+	}
+
+	for _, item := range export.Items(TypePost) {
+		if err := importer.importPost(item); err != nil {
+			return importer.report, err
+		}
+	}
+
+	pages := make(map[string]*Item)
+	for _, item := range export.Items(TypePage) {
+		pages[item.ID] = item
+	}
+
+	created := make(map[string]*models.Page)
+	for _, item := range export.Items(TypePage) {
+		if _, err := importer.importPage(item, pages, created); err != nil {
+			return importer.report, err
+		}
+	}
+
+	var host string
+	if u, err := url.Parse(export.Channel.BaseSiteURL); err == nil {
+		host = u.Host
+	}
+
+	if err := importer.resolveLinks(host); err != nil {
+		return importer.report, err
+	}
+
+	if err := importer.createRedirects(); err != nil {
+		return importer.report, err
+	}
+
+	return importer.report, nil
+}
+
+// Import image attachment
+func (importer *Importer) importAttachment(item *Item) error {
+	relPath := item.AttachedFile()
+	if relPath == "" {
+		log.Printf("Skipping attachment %s: file path not found", item.ID)
+		return nil
+	}
+
+	ctype := mime.TypeByExtension(path.Ext(relPath))
+	if !imageContentTypes[ctype] {
+		log.Printf("Skipping attachment %s: unsupported content type %s", relPath, ctype)
+		return nil
+	}
+
+	src, err := os.Open(filepath.Join(importer.mediaDir, filepath.FromSlash(relPath)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Printf("Skipping attachment %s: file not found in %s", relPath, importer.mediaDir)
+			return nil
+		}
+
+		return err
+	}
+	defer src.Close()
+
+	dstPath := helpers.AvailableFilePath(core.UploadSiteFilePath(importer.site.ID, path.Base(relPath)))
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(dstPath)
+	if err != nil {
+		return err
+	}
+
+	img := &models.Image{
+		ID:     bson.NewObjectId(),
+		SiteID: importer.site.ID,
+		Path:   info.Name(),
+		Name:   path.Base(relPath),
+		Size:   info.Size(),
+		Type:   ctype,
+	}
+
+	if _, err := img.NormalizeOriginal(); err != nil {
+		log.Printf("Failed to normalize image: %s - %v", img.Path, err)
+	}
+
+	if err := importer.dbSession.CreateImage(img); err != nil {
+		return err
+	}
+
+	if err := img.GenerateDerivatives(true); err != nil {
+		log.Printf("Failed to generate image derivatives: %s - %v", img.Path, err)
+	}
+
+	importer.images[item.ID] = img
+	importer.uploadURLs[relPath] = img.URL()
+	importer.report.Images++
+
+	return nil
+}
+
+// Import post
+func (importer *Importer) importPost(item *Item) error {
+	switch item.Status {
+	case StatusPublish, StatusDraft, StatusPending, StatusFuture, StatusPrivate:
+	default:
+		// trash, auto-draft...
+		return nil
+	}
+
+	post := &models.Post{
+		SiteID:      importer.site.ID,
+		Published:   item.Status == StatusPublish,
+		PublishedAt: item.PublishedAt(importer.site.TZLocation()),
+		Title:       item.CleanTitle(),
+		Body:        rewriteUploadURLs(autop(item.Content), importer.uploadURLs),
+		Format:      models.FormatHTML,
+		Cover:       importer.cover(item),
+	}
+
+	if err := importer.dbSession.CreatePost(post); err != nil {
+		return err
+	}
+
+	importer.addContent(&imported{item: item, kind: models.RedirectKindPost, post: post})
+	importer.report.Posts++
+
+	return nil
+}
+
+// Import published page, after its parent
+func (importer *Importer) importPage(item *Item, items map[string]*Item, created map[string]*models.Page) (*models.Page, error) {
+	if page, ok := created[item.ID]; ok {
+		// already imported, or being imported
+		return page, nil
+	}
+
+	created[item.ID] = nil
+
+	if item.Status != StatusPublish {
+		return nil, nil
+	}
+
+	page := &models.Page{
+		SiteID: importer.site.ID,
+		Title:  item.CleanTitle(),
+		Body:   rewriteUploadURLs(autop(item.Content), importer.uploadURLs),
+		Format: models.FormatHTML,
+		Cover:  importer.cover(item),
+		Order:  item.MenuOrder,
+	}
+
+	if parentItem := items[item.ParentID]; parentItem != nil {
+		parent, err := importer.importPage(parentItem, items, created)
+		if err != nil {
+			return nil, err
+		}
+
+		if parent != nil {
+			page.ParentID = parent.ID
+		}
+	}
+
+	if err := importer.dbSession.CreatePage(page); err != nil {
+		return nil, err
+	}
+
+	created[item.ID] = page
+
+	importer.addContent(&imported{item: item, kind: models.RedirectKindPage, page: page})
+	importer.report.Pages++
+
+	return page, nil
+}
+
+// Returns cover image id for given item
+func (importer *Importer) cover(item *Item) bson.ObjectId {
+	if img := importer.images[item.MetaValue(metaThumbnailID)]; img != nil {
+		return img.ID
+	}
+
+	return ""
+}
+
+// Register imported content
+func (importer *Importer) addContent(content *imported) {
+	importer.contents = append(importer.contents, content)
+
+	if content.post != nil && !content.post.Published {
+		// internal links to unpublished posts would break site build
+		return
+	}
+
+	link := fmt.Sprintf("[[%s:%s]]", content.kind, content.id().Hex())
+
+	for _, key := range []string{linkKey(content.item.Link), linkKey(content.item.GUID)} {
+		if key != "" {
+			importer.links[key] = link
+		}
+	}
+
+	// default permalinks
+	if content.kind == models.RedirectKindPage {
+		importer.links["?page_id="+content.item.ID] = link
+	} else {
+		importer.links["?p="+content.item.ID] = link
+	}
+}
+
+// Replace links to imported contents with internal links
+func (importer *Importer) resolveLinks(host string) error {
+	for _, content := range importer.contents {
+		if content.post != nil {
+			newPost := *content.post
+			newPost.Body = rewriteLinks(newPost.Body, host, importer.links)
+
+			if _, err := content.post.Update(&newPost); err != nil {
+				return err
+			}
+		} else {
+			newPage := *content.page
+			newPage.Body = rewriteLinks(newPage.Body, host, importer.links)
+
+			if _, err := content.page.Update(&newPage); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Record old URLs as redirects
+func (importer *Importer) createRedirects() error {
+	for _, content := range importer.contents {
+		oldPath := content.item.OldPath()
+		if oldPath == "" {
+			continue
+		}
+
+		redirect := &models.Redirect{
+			SiteID:   importer.site.ID,
+			Path:     oldPath,
+			Kind:     content.kind,
+			TargetID: content.id(),
+		}
+
+		if err := importer.dbSession.CreateRedirect(redirect); err != nil {
+			if mgo.IsDup(err) {
+				log.Printf("Skipping redirect %s: already exists", oldPath)
+				continue
+			}
+
+			return err
+		}
+
+		importer.report.Redirects++
+	}
+
+	return nil
+}
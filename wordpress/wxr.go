@@ -0,0 +1,262 @@
+// Package wordpress imports content from WordPress eXtended RSS (WXR) exports.
+package wordpress
+
+import (
+	"encoding/xml"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// WordPress post types
+const (
+	TypePost       = "post"
+	TypePage       = "page"
+	TypeAttachment = "attachment"
+)
+
+// WordPress post statuses
+const (
+	StatusPublish = "publish"
+	StatusDraft   = "draft"
+	StatusPending = "pending"
+	StatusFuture  = "future"
+	StatusPrivate = "private"
+)
+
+const (
+	dateLayout = "2006-01-02 15:04:05"
+	zeroDate   = "0000-00-00 00:00:00"
+
+	metaAttachedFile = "_wp_attached_file"
+	metaThumbnailID  = "_thumbnail_id"
+
+	uploadsURLPath = "wp-content/uploads/"
+)
+
+var (
+	// eg: foo-300x200.jpg
+	sizedImageRegexp = regexp.MustCompile(`-\d+x\d+(\.[A-Za-z0-9]+)$`)
+
+	// urls to uploaded files in contents
+	uploadURLRegexp = regexp.MustCompile(`[^"'\s()<>=]*` + regexp.QuoteMeta(uploadsURLPath) + `([^"'\s()<>?#]+)`)
+
+	// links in contents
+	hrefRegexp = regexp.MustCompile(`href="([^"]*)"`)
+
+	// paragraphs in contents
+	paragraphRegexp = regexp.MustCompile(`(?i)<p[\s>]`)
+
+	// block elements that must not be wrapped in paragraphs
+	blockRegexp = regexp.MustCompile(`^<(?i:p|div|h[1-6]|ul|ol|li|table|blockquote|pre|figure|hr|iframe|form|dl|address|section|article|aside|header|footer|!--)[\s>/]`)
+)
+
+// Export represents a WXR export file
+type Export struct {
+	Channel struct {
+		Title       string  `xml:"title"`
+		BaseSiteURL string  `xml:"base_site_url"`
+		BaseBlogURL string  `xml:"base_blog_url"`
+		Items       []*Item `xml:"item"`
+	} `xml:"channel"`
+}
+
+// Item represents a WXR item: a post, a page, an attachment...
+type Item struct {
+	Title         string  `xml:"title"`
+	Link          string  `xml:"link"`
+	GUID          string  `xml:"guid"`
+	Content       string  `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	ID            string  `xml:"post_id"`
+	Date          string  `xml:"post_date"`
+	DateGMT       string  `xml:"post_date_gmt"`
+	Name          string  `xml:"post_name"`
+	Status        string  `xml:"status"`
+	ParentID      string  `xml:"post_parent"`
+	MenuOrder     int     `xml:"menu_order"`
+	Type          string  `xml:"post_type"`
+	AttachmentURL string  `xml:"attachment_url"`
+	Meta          []*Meta `xml:"postmeta"`
+}
+
+// Meta represents an item metadata
+type Meta struct {
+	Key   string `xml:"meta_key"`
+	Value string `xml:"meta_value"`
+}
+
+// Parse parses a WXR export
+func Parse(r io.Reader) (*Export, error) {
+	result := &Export{}
+
+	if err := xml.NewDecoder(r).Decode(result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Items returns all items of given type
+func (export *Export) Items(kind string) []*Item {
+	var result []*Item
+
+	for _, item := range export.Channel.Items {
+		if item.Type == kind {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// MetaValue returns value of given metadata, or an empty string if not found
+func (item *Item) MetaValue(key string) string {
+	for _, meta := range item.Meta {
+		if meta.Key == key {
+			return meta.Value
+		}
+	}
+
+	return ""
+}
+
+// CleanTitle returns item title, with HTML entities decoded
+func (item *Item) CleanTitle() string {
+	return strings.TrimSpace(html.UnescapeString(item.Title))
+}
+
+// PublishedAt returns item publication date
+func (item *Item) PublishedAt(loc *time.Location) time.Time {
+	if item.DateGMT != "" && item.DateGMT != zeroDate {
+		if result, err := time.Parse(dateLayout, item.DateGMT); err == nil {
+			return result
+		}
+	}
+
+	if item.Date != "" && item.Date != zeroDate {
+		if result, err := time.ParseInLocation(dateLayout, item.Date, loc); err == nil {
+			return result.UTC()
+		}
+	}
+
+	return time.Time{}
+}
+
+// AttachedFile returns the path of attached file, relative to uploads directory, eg: 2015/03/foo.jpg
+func (item *Item) AttachedFile() string {
+	if result := item.MetaValue(metaAttachedFile); result != "" {
+		return cleanUploadPath(result)
+	}
+
+	if i := strings.Index(item.AttachmentURL, uploadsURLPath); i >= 0 {
+		return cleanUploadPath(item.AttachmentURL[i+len(uploadsURLPath):])
+	}
+
+	return ""
+}
+
+// OldPath returns the URL path of item on WordPress site, eg: /2015/03/my-post/
+func (item *Item) OldPath() string {
+	u, err := url.Parse(item.Link)
+	if err != nil || u.Path == "" || u.Path == "/" {
+		return ""
+	}
+
+	return u.Path
+}
+
+// cleanUploadPath returns a safe relative path, or an empty string
+func cleanUploadPath(filePath string) string {
+	result := path.Clean(strings.TrimPrefix(filePath, "/"))
+	if result == "." || result == ".." || strings.HasPrefix(result, "../") {
+		return ""
+	}
+
+	return result
+}
+
+// unsizedUploadPath removes the WordPress image size suffix, eg: 2015/03/foo-300x200.jpg => 2015/03/foo.jpg
+func unsizedUploadPath(filePath string) string {
+	return sizedImageRegexp.ReplaceAllString(filePath, "$1")
+}
+
+// linkKey normalizes a link to a WordPress content, so that absolute and relative links can be compared
+func linkKey(link string) string {
+	u, err := url.Parse(strings.TrimSpace(html.UnescapeString(link)))
+	if err != nil {
+		return ""
+	}
+
+	result := strings.TrimSuffix(u.Path, "/")
+	if u.RawQuery != "" {
+		result += "?" + u.RawQuery
+	}
+
+	return result
+}
+
+// rewriteUploadURLs replaces urls of uploaded files in given content
+func rewriteUploadURLs(content string, urls map[string]string) string {
+	return uploadURLRegexp.ReplaceAllStringFunc(content, func(match string) string {
+		filePath := cleanUploadPath(uploadURLRegexp.FindStringSubmatch(match)[1])
+
+		if result, ok := urls[filePath]; ok {
+			return result
+		}
+
+		if result, ok := urls[unsizedUploadPath(filePath)]; ok {
+			return result
+		}
+
+		return match
+	})
+}
+
+// rewriteLinks replaces links to imported contents with internal links, eg: [[post:5565ad2c4e6f6c4a0c000004]]
+func rewriteLinks(content string, host string, links map[string]string) string {
+	return hrefRegexp.ReplaceAllStringFunc(content, func(match string) string {
+		link := hrefRegexp.FindStringSubmatch(match)[1]
+
+		u, err := url.Parse(html.UnescapeString(link))
+		if err != nil || (u.Host != "" && u.Host != host) {
+			return match
+		}
+
+		if result, ok := links[linkKey(link)]; ok {
+			return `href="` + result + `"`
+		}
+
+		return match
+	})
+}
+
+// autop wraps text blocks in paragraphs, as WordPress does when rendering contents
+func autop(content string) string {
+	content = strings.Replace(content, "\r\n", "\n", -1)
+
+	if paragraphRegexp.MatchString(content) {
+		// content is already formatted
+		return content
+	}
+
+	var result []string
+
+	for _, block := range strings.Split(content, "\n\n") {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+
+		if !blockRegexp.MatchString(block) {
+			block = "<p>" + strings.Replace(block, "\n", "<br />\n", -1) + "</p>"
+		}
+
+		result = append(result, block)
+	}
+
+	return strings.Join(result, "\n")
+}
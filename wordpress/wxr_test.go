@@ -0,0 +1,175 @@
+package wordpress
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const testWXR = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:wfw="http://wellformedweb.org/CommentAPI/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<title>My Association</title>
+	<link>http://old.example.org</link>
+	<wp:base_site_url>http://old.example.org</wp:base_site_url>
+	<wp:base_blog_url>http://old.example.org</wp:base_blog_url>
+	<item>
+		<title>Summer &amp;#8217;party</title>
+		<link>http://old.example.org/2015/03/summer-party/</link>
+		<guid isPermaLink="false">http://old.example.org/?p=12</guid>
+		<content:encoded><![CDATA[First paragraph.
+
+Second line
+<img src="http://old.example.org/wp-content/uploads/2015/03/party-300x200.jpg" />]]></content:encoded>
+		<excerpt:encoded><![CDATA[]]></excerpt:encoded>
+		<wp:post_id>12</wp:post_id>
+		<wp:post_date>2015-03-10 09:30:00</wp:post_date>
+		<wp:post_date_gmt>2015-03-10 08:30:00</wp:post_date_gmt>
+		<wp:post_name>summer-party</wp:post_name>
+		<wp:status>publish</wp:status>
+		<wp:post_parent>0</wp:post_parent>
+		<wp:menu_order>0</wp:menu_order>
+		<wp:post_type>post</wp:post_type>
+		<wp:postmeta>
+			<wp:meta_key>_thumbnail_id</wp:meta_key>
+			<wp:meta_value><![CDATA[13]]></wp:meta_value>
+		</wp:postmeta>
+	</item>
+	<item>
+		<title>party</title>
+		<link>http://old.example.org/2015/03/summer-party/party/</link>
+		<wp:post_id>13</wp:post_id>
+		<wp:post_date>2015-03-10 09:00:00</wp:post_date>
+		<wp:post_date_gmt>0000-00-00 00:00:00</wp:post_date_gmt>
+		<wp:status>inherit</wp:status>
+		<wp:post_parent>12</wp:post_parent>
+		<wp:post_type>attachment</wp:post_type>
+		<wp:attachment_url>http://old.example.org/wp-content/uploads/2015/03/party.jpg</wp:attachment_url>
+		<wp:postmeta>
+			<wp:meta_key>_wp_attached_file</wp:meta_key>
+			<wp:meta_value><![CDATA[2015/03/party.jpg]]></wp:meta_value>
+		</wp:postmeta>
+	</item>
+	<item>
+		<title>About</title>
+		<link>http://old.example.org/about/</link>
+		<content:encoded><![CDATA[<p>See <a href="http://old.example.org/2015/03/summer-party/">our party</a> and <a href="http://other.example.org/2015/03/summer-party/">that one</a>.</p>]]></content:encoded>
+		<wp:post_id>20</wp:post_id>
+		<wp:status>publish</wp:status>
+		<wp:menu_order>3</wp:menu_order>
+		<wp:post_type>page</wp:post_type>
+	</item>
+</channel>
+</rss>
+`
+
+type WXRTestSuite struct {
+	suite.Suite
+}
+
+// In order for 'go test' to run this suite, we need to create
+// a normal test function and pass our suite to suite.Run
+func TestWXRTestSuite(t *testing.T) {
+	suite.Run(t, new(WXRTestSuite))
+}
+
+//
+// Tests
+//
+
+func (suite *WXRTestSuite) TestParse() {
+	t := suite.T()
+
+	export, err := Parse(strings.NewReader(testWXR))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "My Association", export.Channel.Title)
+	assert.Equal(t, "http://old.example.org", export.Channel.BaseSiteURL)
+	assert.Len(t, export.Channel.Items, 3)
+
+	posts := export.Items(TypePost)
+	if assert.Len(t, posts, 1) {
+		post := posts[0]
+
+		assert.Equal(t, "12", post.ID)
+		assert.Equal(t, "Summer ’party", post.CleanTitle())
+		assert.Equal(t, StatusPublish, post.Status)
+		assert.Equal(t, "/2015/03/summer-party/", post.OldPath())
+		assert.Equal(t, "13", post.MetaValue(metaThumbnailID))
+		assert.Equal(t, time.Date(2015, time.March, 10, 8, 30, 0, 0, time.UTC), post.PublishedAt(time.UTC))
+		assert.Contains(t, post.Content, "First paragraph.")
+	}
+
+	attachments := export.Items(TypeAttachment)
+	if assert.Len(t, attachments, 1) {
+		attachment := attachments[0]
+
+		assert.Equal(t, "2015/03/party.jpg", attachment.AttachedFile())
+
+		// no GMT date
+		loc := time.FixedZone("CET", 3600)
+		assert.Equal(t, time.Date(2015, time.March, 10, 8, 0, 0, 0, time.UTC), attachment.PublishedAt(loc))
+	}
+
+	pages := export.Items(TypePage)
+	if assert.Len(t, pages, 1) {
+		assert.Equal(t, 3, pages[0].MenuOrder)
+	}
+}
+
+func (suite *WXRTestSuite) TestAttachedFile() {
+	t := suite.T()
+
+	item := &Item{AttachmentURL: "http://old.example.org/wp-content/uploads/2015/03/foo.png"}
+	assert.Equal(t, "2015/03/foo.png", item.AttachedFile())
+
+	item = &Item{Meta: []*Meta{{Key: metaAttachedFile, Value: "../../etc/passwd"}}}
+	assert.Equal(t, "", item.AttachedFile())
+}
+
+func (suite *WXRTestSuite) TestRewriteUploadURLs() {
+	t := suite.T()
+
+	urls := map[string]string{"2015/03/party.jpg": "/upload/test/party.jpg"}
+
+	assert.Equal(t,
+		`<img src="/upload/test/party.jpg" /><a href="/upload/test/party.jpg">`,
+		rewriteUploadURLs(`<img src="http://old.example.org/wp-content/uploads/2015/03/party-300x200.jpg" /><a href="/wp-content/uploads/2015/03/party.jpg">`, urls))
+
+	// unknown file
+	assert.Equal(t,
+		`<img src="http://old.example.org/wp-content/uploads/2015/03/other.jpg" />`,
+		rewriteUploadURLs(`<img src="http://old.example.org/wp-content/uploads/2015/03/other.jpg" />`, urls))
+}
+
+func (suite *WXRTestSuite) TestRewriteLinks() {
+	t := suite.T()
+
+	links := map[string]string{
+		"/2015/03/summer-party": "[[post:5565ad2c4e6f6c4a0c000004]]",
+		"?page_id=20":           "[[page:5565ad2c4e6f6c4a0c000005]]",
+	}
+
+	assert.Equal(t,
+		`<a href="[[post:5565ad2c4e6f6c4a0c000004]]">a</a> <a href="[[post:5565ad2c4e6f6c4a0c000004]]">b</a> <a href="http://other.example.org/2015/03/summer-party/">c</a> <a href="[[page:5565ad2c4e6f6c4a0c000005]]">d</a>`,
+		rewriteLinks(`<a href="http://old.example.org/2015/03/summer-party/">a</a> <a href="/2015/03/summer-party">b</a> <a href="http://other.example.org/2015/03/summer-party/">c</a> <a href="http://old.example.org/?page_id=20">d</a>`, "old.example.org", links))
+}
+
+func (suite *WXRTestSuite) TestAutop() {
+	t := suite.T()
+
+	assert.Equal(t, "<p>First paragraph.</p>\n<p>Second line<br />\nThird line</p>\n<h2>Title</h2>", autop("First paragraph.\r\n\r\nSecond line\nThird line\n\n<h2>Title</h2>"))
+
+	// already formatted
+	assert.Equal(t, "<p>Foo</p>\n\n<p>Bar</p>", autop("<p>Foo</p>\n\n<p>Bar</p>"))
+}